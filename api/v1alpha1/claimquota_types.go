@@ -0,0 +1,116 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"slices"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClaimQuotaSpec defines the desired state of ClaimQuota
+type ClaimQuotaSpec struct {
+	// Hard is the maximum number of ServiceClaims in the scope of the quota
+	//+kubebuilder:validation:Minimum=0
+	Hard int `json:"hard"`
+
+	// Environments restricts the quota to the ServiceClaims targeting one of
+	// the given environments. All environments are in scope if empty
+	// +optional
+	Environments []string `json:"environments,omitempty"`
+
+	// ServiceClassIdentity restricts the quota to the ServiceClaims whose
+	// ServiceClassIdentity includes all the given items. All ServiceClaims
+	// are in scope if empty
+	// +optional
+	ServiceClassIdentity []ServiceClassIdentityItem `json:"serviceClassIdentity,omitempty"`
+}
+
+// ClaimQuotaStatus defines the observed state of ClaimQuota
+type ClaimQuotaStatus struct {
+	// Hard is the enforced maximum number of ServiceClaims
+	// +optional
+	Hard int `json:"hard"`
+
+	// Used is the number of ServiceClaims in the scope of the quota
+	// +optional
+	Used int `json:"used"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Hard",type="integer",JSONPath=".status.hard",description="the maximum number of service claims"
+//+kubebuilder:printcolumn:name="Used",type="integer",JSONPath=".status.used",description="the number of service claims in the scope of the quota"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// ClaimQuota is the Schema for the claimquotas API.
+// It limits the number of ServiceClaims of the namespace, optionally
+// restricted to some environments and ServiceClassIdentity.
+type ClaimQuota struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClaimQuotaSpec   `json:"spec,omitempty"`
+	Status ClaimQuotaStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ClaimQuotaList contains a list of ClaimQuota
+type ClaimQuotaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClaimQuota `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClaimQuota{}, &ClaimQuotaList{})
+}
+
+// Matches returns true if the ServiceClaim, targeting the given
+// environment, is in the scope of the quota
+func (q *ClaimQuota) Matches(sc ServiceClaim, environment string) bool {
+	if len(q.Spec.Environments) > 0 && !slices.Contains(q.Spec.Environments, environment) {
+		return false
+	}
+	return includesServiceClassIdentity(sc.Spec.ServiceClassIdentity, q.Spec.ServiceClassIdentity)
+}
+
+// CountedClaims returns the ServiceClaims counting against the quota,
+// oldest first. ServiceClaims being deleted or Rejected do not count.
+// The environment of ServiceClaims targeting a ClusterEnvironment is looked
+// up in the given map, by ClusterEnvironment name.
+func (q *ClaimQuota) CountedClaims(claims []ServiceClaim, environments map[string]string) []ServiceClaim {
+	counted := []ServiceClaim{}
+	for _, sc := range claims {
+		if sc.HasDeletionTimestamp() || sc.Status.State == ServiceClaimStateRejected {
+			continue
+		}
+		if q.Matches(sc, sc.Spec.Target.EnvironmentName(environments)) {
+			counted = append(counted, sc)
+		}
+	}
+	sort.SliceStable(counted, func(i, j int) bool {
+		ti, tj := counted[i].CreationTimestamp, counted[j].CreationTimestamp
+		if !ti.Equal(&tj) {
+			return ti.Before(&tj)
+		}
+		return counted[i].Name < counted[j].Name
+	})
+	return counted
+}
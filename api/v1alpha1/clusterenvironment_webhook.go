@@ -0,0 +1,233 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/tools/clientcmd"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// ClusterContextSecretKey is the key of the cluster context secret storing
+// the kubeconfig
+const ClusterContextSecretKey = "kubeconfig"
+
+// log is for logging in this package.
+var clusterenvironmentlog = logf.Log.WithName("clusterenvironment-resource")
+
+type clusterEnvironmentWebhook struct {
+	client client.Client
+}
+
+var _ admission.CustomValidator = &clusterEnvironmentWebhook{}
+var _ admission.CustomDefaulter = &clusterEnvironmentWebhook{}
+
+func (r *ClusterEnvironment) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	w := &clusterEnvironmentWebhook{
+		client: mgr.GetClient(),
+	}
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		WithValidator(w).
+		WithDefaulter(w).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/mutate-primaza-io-v1alpha1-clusterenvironment,mutating=true,failurePolicy=fail,sideEffects=None,groups=primaza.io,resources=clusterenvironments,verbs=create;update,versions=v1alpha1,name=mclusterenvironment.kb.io,admissionReviewVersions=v1
+//+kubebuilder:webhook:path=/validate-primaza-io-v1alpha1-clusterenvironment,mutating=false,failurePolicy=fail,sideEffects=None,groups=primaza.io,resources=clusterenvironments,verbs=create;update,versions=v1alpha1,name=vclusterenvironment.kb.io,admissionReviewVersions=v1
+
+// Default implements admission.CustomDefaulter
+func (w *clusterEnvironmentWebhook) Default(ctx context.Context, obj runtime.Object) error {
+	r, ok := obj.(*ClusterEnvironment)
+	if !ok {
+		return fmt.Errorf("Object is not a Cluster Environment")
+	}
+
+	clusterenvironmentlog.Info("default", "name", r.Name)
+	if r.Spec.SynchronizationStrategy == "" {
+		r.Spec.SynchronizationStrategy = SynchronizationStrategyPush
+	}
+	return nil
+}
+
+// ValidateNamespaces checks that application and service namespaces are
+// valid namespace names, and that no namespace is listed twice
+func (s *ClusterEnvironmentSpec) ValidateNamespaces() field.ErrorList {
+	errs := field.ErrorList{}
+	check := func(path *field.Path, namespaces []string) {
+		seen := map[string]struct{}{}
+		for i, ns := range namespaces {
+			for _, msg := range validation.IsDNS1123Label(ns) {
+				errs = append(errs, field.Invalid(path.Index(i), ns, msg))
+			}
+			if _, found := seen[ns]; found {
+				errs = append(errs, field.Duplicate(path.Index(i), ns))
+			}
+			seen[ns] = struct{}{}
+		}
+	}
+
+	specPath := field.NewPath("spec")
+	check(specPath.Child("applicationNamespaces"), s.ApplicationNamespaces)
+	check(specPath.Child("serviceNamespaces"), s.ServiceNamespaces)
+	for i, ns := range s.ServiceNamespaces {
+		if slices.Contains(s.ApplicationNamespaces, ns) {
+			errs = append(errs, field.Invalid(specPath.Child("serviceNamespaces").Index(i), ns, "namespace is also an application namespace"))
+		}
+	}
+	return errs
+}
+
+// validateClusterContextSecret checks that the cluster context secret exists
+// and contains a valid kubeconfig. It returns the cluster's server.
+func (w *clusterEnvironmentWebhook) validateClusterContextSecret(ctx context.Context, r *ClusterEnvironment) (string, *field.Error, error) {
+	path := field.NewPath("spec", "clusterContextSecret")
+	if r.Spec.ClusterContextSecret == "" {
+		return "", field.Required(path, "cluster context secret must be defined"), nil
+	}
+
+	s := corev1.Secret{}
+	if err := w.client.Get(ctx, types.NamespacedName{Namespace: r.Namespace, Name: r.Spec.ClusterContextSecret}, &s); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", field.NotFound(path, r.Spec.ClusterContextSecret), nil
+		}
+		return "", nil, err
+	}
+
+	cfg, err := clientcmd.RESTConfigFromKubeConfig(s.Data[ClusterContextSecretKey])
+	if err != nil {
+		return "", field.Invalid(path, r.Spec.ClusterContextSecret, fmt.Sprintf("secret does not contain a valid %s: %s", ClusterContextSecretKey, err)), nil
+	}
+	return cfg.Host, nil, nil
+}
+
+// boundNamespacesWarnings warns about the namespaces already bound by other
+// ClusterEnvironments targeting the same cluster
+func (w *clusterEnvironmentWebhook) boundNamespacesWarnings(ctx context.Context, r *ClusterEnvironment, server string) (admission.Warnings, error) {
+	cel := ClusterEnvironmentList{}
+	if err := w.client.List(ctx, &cel, client.InNamespace(r.Namespace)); err != nil {
+		return nil, err
+	}
+
+	warnings := admission.Warnings{}
+	namespaces := append(append([]string{}, r.Spec.ApplicationNamespaces...), r.Spec.ServiceNamespaces...)
+	for _, ce := range cel.Items {
+		if ce.Name == r.Name {
+			continue
+		}
+
+		s := corev1.Secret{}
+		if err := w.client.Get(ctx, types.NamespacedName{Namespace: ce.Namespace, Name: ce.Spec.ClusterContextSecret}, &s); err != nil {
+			continue
+		}
+		cfg, err := clientcmd.RESTConfigFromKubeConfig(s.Data[ClusterContextSecretKey])
+		if err != nil || cfg.Host != server {
+			continue
+		}
+
+		for _, ns := range namespaces {
+			if slices.Contains(ce.Spec.ApplicationNamespaces, ns) || slices.Contains(ce.Spec.ServiceNamespaces, ns) {
+				warnings = append(warnings,
+					fmt.Sprintf("namespace %s is already bound by ClusterEnvironment %s targeting the same cluster", ns, ce.Name))
+			}
+		}
+	}
+	return warnings, nil
+}
+
+func (w *clusterEnvironmentWebhook) validate(ctx context.Context, r *ClusterEnvironment, checkSecret bool) (admission.Warnings, error) {
+	errs := field.ErrorList{}
+	if r.Spec.EnvironmentName == "" {
+		errs = append(errs, field.Required(field.NewPath("spec", "environmentName"), "environment name must be defined"))
+	}
+	errs = append(errs, r.Spec.ValidateNamespaces()...)
+
+	var warnings admission.Warnings
+	if checkSecret {
+		server, ferr, err := w.validateClusterContextSecret(ctx, r)
+		if err != nil {
+			return nil, err
+		}
+		if ferr != nil {
+			errs = append(errs, ferr)
+		} else if warnings, err = w.boundNamespacesWarnings(ctx, r, server); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(errs) > 0 {
+		return warnings, apierrors.NewInvalid(GroupVersion.WithKind("ClusterEnvironment").GroupKind(), r.Name, errs)
+	}
+	return warnings, nil
+}
+
+// ValidateCreate implements admission.CustomValidator
+func (w *clusterEnvironmentWebhook) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	r, ok := obj.(*ClusterEnvironment)
+	if !ok {
+		err := fmt.Errorf("Object is not a Cluster Environment")
+		clusterenvironmentlog.Error(err, "Attempted to validate non-ClusterEnvironment resource", "gvk", obj.GetObjectKind().GroupVersionKind())
+		return nil, err
+	}
+
+	clusterenvironmentlog.Info("validate create", "name", r.Name)
+	return w.validate(ctx, r, true)
+}
+
+// ValidateUpdate implements admission.CustomValidator
+func (w *clusterEnvironmentWebhook) ValidateUpdate(ctx context.Context, oldObj runtime.Object, newObj runtime.Object) (admission.Warnings, error) {
+	newEnv, ok := newObj.(*ClusterEnvironment)
+	if !ok {
+		err := fmt.Errorf("Object is not a Cluster Environment")
+		clusterenvironmentlog.Error(err, "Attempted to validate non-ClusterEnvironment resource", "gvk", newObj.GetObjectKind().GroupVersionKind())
+		return nil, err
+	}
+
+	clusterenvironmentlog.Info("validate update", "name", newEnv.Name)
+
+	oldEnv, ok := oldObj.(*ClusterEnvironment)
+	if !ok {
+		return nil, fmt.Errorf("Old object is not a ClusterEnvironment")
+	}
+
+	// finalizers need to be removed even if the cluster context secret
+	// has already been deleted
+	if newEnv.HasDeletionTimestamp() {
+		return nil, nil
+	}
+	checkSecret := oldEnv.Spec.ClusterContextSecret != newEnv.Spec.ClusterContextSecret ||
+		!slices.Equal(oldEnv.Spec.ApplicationNamespaces, newEnv.Spec.ApplicationNamespaces) ||
+		!slices.Equal(oldEnv.Spec.ServiceNamespaces, newEnv.Spec.ServiceNamespaces)
+	return w.validate(ctx, newEnv, checkSecret)
+}
+
+// ValidateDelete implements admission.CustomValidator
+func (w *clusterEnvironmentWebhook) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil // no validation
+}
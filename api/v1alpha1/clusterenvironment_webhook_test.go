@@ -0,0 +1,146 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func newClusterContextSecret(name, server string) *corev1.Secret {
+	kubeconfig := fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- name: cluster
+  cluster:
+    server: %s
+contexts:
+- name: context
+  context:
+    cluster: cluster
+    user: user
+current-context: context
+users:
+- name: user
+  user:
+    token: token
+`, server)
+	return &corev1.Secret{
+		ObjectMeta: v1.ObjectMeta{Name: name, Namespace: "primaza-system"},
+		Data:       map[string][]byte{ClusterContextSecretKey: []byte(kubeconfig)},
+	}
+}
+
+func newClusterEnvironment(name, secret string, applicationNamespaces, serviceNamespaces []string) *ClusterEnvironment {
+	return &ClusterEnvironment{
+		ObjectMeta: v1.ObjectMeta{Name: name, Namespace: "primaza-system"},
+		Spec: ClusterEnvironmentSpec{
+			EnvironmentName:       "dev",
+			ClusterContextSecret:  secret,
+			ApplicationNamespaces: applicationNamespaces,
+			ServiceNamespaces:     serviceNamespaces,
+		},
+	}
+}
+
+var _ = Describe("ClusterEnvironment webhook", func() {
+	newWebhook := func(objs ...client.Object) clusterEnvironmentWebhook {
+		scheme, err := SchemeBuilder.Build()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		return clusterEnvironmentWebhook{
+			client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+		}
+	}
+	objs := []client.Object{
+		newClusterContextSecret("worker-kubeconfig", "https://worker:6443"),
+		newClusterContextSecret("worker-alias-kubeconfig", "https://worker:6443"),
+		newClusterContextSecret("other-kubeconfig", "https://other:6443"),
+		&corev1.Secret{
+			ObjectMeta: v1.ObjectMeta{Name: "invalid-kubeconfig", Namespace: "primaza-system"},
+			Data:       map[string][]byte{ClusterContextSecretKey: []byte("invalid")},
+		},
+		newClusterEnvironment("worker", "worker-kubeconfig", []string{"applications"}, []string{"services"}),
+	}
+
+	DescribeTable("Creation validation",
+		func(ce *ClusterEnvironment, expectedWarnings admission.Warnings, expected []v1.StatusCause) {
+			w := newWebhook(objs...)
+			warnings, err := w.ValidateCreate(context.Background(), ce)
+			Expect(warnings).To(Equal(expectedWarnings))
+
+			obtained := causes(err)
+			for i := range obtained {
+				obtained[i].Message = ""
+			}
+			Expect(obtained).To(Equal(expected))
+		},
+		Entry("valid cluster environment",
+			newClusterEnvironment("new", "other-kubeconfig", []string{"applications"}, []string{"services"}),
+			admission.Warnings{}, nil),
+		Entry("namespaces already bound on the same cluster",
+			newClusterEnvironment("new", "worker-alias-kubeconfig", []string{"applications", "apps"}, nil),
+			admission.Warnings{"namespace applications is already bound by ClusterEnvironment worker targeting the same cluster"}, nil),
+		Entry("duplicate and overlapping namespaces",
+			newClusterEnvironment("new", "other-kubeconfig", []string{"applications", "applications"}, []string{"applications", "Services"}),
+			admission.Warnings{},
+			[]v1.StatusCause{
+				cause(field.ErrorTypeDuplicate, "spec.applicationNamespaces[1]"),
+				cause(field.ErrorTypeInvalid, "spec.serviceNamespaces[1]"),
+				cause(field.ErrorTypeInvalid, "spec.serviceNamespaces[0]"),
+			}),
+		Entry("missing cluster context secret",
+			newClusterEnvironment("new", "missing-kubeconfig", nil, nil),
+			nil,
+			[]v1.StatusCause{cause(field.ErrorTypeNotFound, "spec.clusterContextSecret")}),
+		Entry("invalid cluster context secret",
+			newClusterEnvironment("new", "invalid-kubeconfig", nil, nil),
+			nil,
+			[]v1.StatusCause{cause(field.ErrorTypeInvalid, "spec.clusterContextSecret")}),
+	)
+
+	It("should not check the cluster context secret of cluster environments being deleted", func() {
+		w := newWebhook()
+		oldEnv := newClusterEnvironment("worker", "missing-kubeconfig", nil, nil)
+		newEnv := oldEnv.DeepCopy()
+		newEnv.Spec.ServiceNamespaces = []string{"services"}
+
+		_, err := w.ValidateUpdate(context.Background(), oldEnv, newEnv)
+		Expect(causes(err)).To(ConsistOf(HaveField("Field", "spec.clusterContextSecret")))
+
+		now := v1.Now()
+		newEnv.DeletionTimestamp = &now
+		_, err = w.ValidateUpdate(context.Background(), oldEnv, newEnv)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should default the synchronization strategy", func() {
+		ce := newClusterEnvironment("worker", "worker-kubeconfig", nil, nil)
+		w := newWebhook()
+		Expect(w.Default(context.Background(), ce)).To(Succeed())
+		Expect(ce.Spec.SynchronizationStrategy).To(Equal(SynchronizationStrategyPush))
+	})
+})
@@ -0,0 +1,88 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// EnvironmentKnownCondition is set on ClusterEnvironments and
+	// ServiceClaims to report whether the environment they refer to is
+	// declared by an Environment resource
+	EnvironmentKnownCondition = "EnvironmentKnown"
+	// EnvironmentCatalogReadyCondition reports whether the ServiceCatalog of
+	// the Environment exists
+	EnvironmentCatalogReadyCondition = "CatalogReady"
+)
+
+// EnvironmentSpec defines the desired state of Environment
+type EnvironmentSpec struct {
+	// Description of the Environment
+	Description string `json:"description,omitempty"`
+
+	// Order is the position of the Environment in the promotion pipeline:
+	// applications are promoted from lower to higher orders, e.g. from dev
+	// to stage and then to prod
+	//+kubebuilder:validation:Minimum=0
+	Order int32 `json:"order,omitempty"`
+
+	// DefaultConstraints are the environment constraints applied to the
+	// RegisteredServices discovered in the Environment's ClusterEnvironments
+	// that do not define any
+	DefaultConstraints []string `json:"defaultConstraints,omitempty"`
+}
+
+// EnvironmentStatus defines the observed state of Environment
+type EnvironmentStatus struct {
+	// ClusterEnvironments lists the ClusterEnvironments associated to the
+	// Environment
+	ClusterEnvironments []string `json:"clusterEnvironments,omitempty"`
+
+	// Status Conditions
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Order",type="integer",JSONPath=".spec.order",description="the position of the Environment in the promotion pipeline"
+//+kubebuilder:printcolumn:name="Description",type="string",JSONPath=".spec.description",description="the description of the Environment"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// Environment is the Schema for the environments API.
+// Its name is the one ClusterEnvironments' environmentName and ServiceClaims'
+// environmentTag refer to.
+type Environment struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   EnvironmentSpec   `json:"spec,omitempty"`
+	Status EnvironmentStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// EnvironmentList contains a list of Environment
+type EnvironmentList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Environment `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Environment{}, &EnvironmentList{})
+}
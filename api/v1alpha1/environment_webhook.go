@@ -0,0 +1,80 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// log is for logging in this package.
+var environmentlog = logf.Log.WithName("environment-resource")
+
+type environmentValidator struct{}
+
+var _ admission.CustomValidator = &environmentValidator{}
+
+func (r *Environment) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		WithValidator(&environmentValidator{}).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-primaza-io-v1alpha1-environment,mutating=false,failurePolicy=fail,sideEffects=None,groups=primaza.io,resources=environments,verbs=create;update,versions=v1alpha1,name=venvironment.kb.io,admissionReviewVersions=v1
+
+// Validate checks that the default constraints are valid
+func (s *EnvironmentSpec) Validate() field.ErrorList {
+	return validateEnvironmentConstraints(field.NewPath("spec", "defaultConstraints"), s.DefaultConstraints)
+}
+
+func (v *environmentValidator) validate(obj runtime.Object) error {
+	r, ok := obj.(*Environment)
+	if !ok {
+		err := fmt.Errorf("Object is not an Environment")
+		environmentlog.Error(err, "Attempted to validate non-Environment resource", "gvk", obj.GetObjectKind().GroupVersionKind())
+		return err
+	}
+
+	environmentlog.Info("validate", "name", r.Name)
+	if errs := r.Spec.Validate(); len(errs) > 0 {
+		return apierrors.NewInvalid(GroupVersion.WithKind("Environment").GroupKind(), r.Name, errs)
+	}
+	return nil
+}
+
+// ValidateCreate implements admission.CustomValidator
+func (v *environmentValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, v.validate(obj)
+}
+
+// ValidateUpdate implements admission.CustomValidator
+func (v *environmentValidator) ValidateUpdate(ctx context.Context, oldObj runtime.Object, newObj runtime.Object) (admission.Warnings, error) {
+	return nil, v.validate(newObj)
+}
+
+// ValidateDelete implements admission.CustomValidator
+func (v *environmentValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil // no validation
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+var _ = Describe("Environment webhook", func() {
+	newEnvironment := func(constraints ...string) *Environment {
+		return &Environment{
+			ObjectMeta: v1.ObjectMeta{Name: "stage", Namespace: "primaza-system"},
+			Spec:       EnvironmentSpec{Order: 1, DefaultConstraints: constraints},
+		}
+	}
+
+	DescribeTable("Validation",
+		func(env *Environment, expected []v1.StatusCause) {
+			v := environmentValidator{}
+			_, err := v.ValidateCreate(context.Background(), env)
+			obtained := causes(err)
+			for i := range obtained {
+				obtained[i].Message = ""
+			}
+			Expect(obtained).To(Equal(expected))
+
+			_, err = v.ValidateUpdate(context.Background(), newEnvironment(), env)
+			Expect(causes(err)).To(HaveLen(len(expected)))
+		},
+		Entry("no default constraints", newEnvironment(), nil),
+		Entry("valid default constraints", newEnvironment("stage", "!label:gpu"), nil),
+		Entry("invalid default constraints", newEnvironment("stage", "stage &&"),
+			[]v1.StatusCause{cause(field.ErrorTypeInvalid, "spec.defaultConstraints[1]")}),
+	)
+})
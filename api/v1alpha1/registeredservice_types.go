@@ -22,6 +22,16 @@ import (
 
 type RegisteredServiceState string
 
+type RegisteredServiceSharingMode string
+
+const (
+	// RegisteredServiceSharingModeExclusive allows a single ServiceClaim at a time
+	RegisteredServiceSharingModeExclusive RegisteredServiceSharingMode = "Exclusive"
+	// RegisteredServiceSharingModeShared allows more ServiceClaims at a time,
+	// up to the RegisteredService's MaxClaims
+	RegisteredServiceSharingModeShared RegisteredServiceSharingMode = "Shared"
+)
+
 const (
 	RegisteredServiceStateAvailable   RegisteredServiceState = "Available"
 	RegisteredServiceStateClaimed     RegisteredServiceState = "Claimed"
@@ -75,6 +85,11 @@ type RegisteredServiceSpec struct {
 	// +optional
 	HealthCheck *HealthCheck `json:"healthcheck,omitempty"`
 
+	// CredentialIssuer defines how to issue per-claim credentials for the
+	// service.
+	// +optional
+	CredentialIssuer *CredentialIssuer `json:"credentialIssuer,omitempty"`
+
 	// SLA defines the support level for this service.
 	// +optional
 	SLA string `json:"sla,omitempty"`
@@ -87,6 +102,30 @@ type RegisteredServiceSpec struct {
 	// ServiceEndpointDefinition defines a set of attributes sufficient for a
 	// client to establish a connection to the service.
 	ServiceEndpointDefinition []ServiceEndpointDefinitionItem `json:"serviceEndpointDefinition"`
+
+	// SharingMode defines whether the service can be claimed by more than
+	// one ServiceClaim at a time.
+	// +optional
+	//+kubebuilder:validation:Enum=Exclusive;Shared
+	//+kubebuilder:default:=Exclusive
+	SharingMode RegisteredServiceSharingMode `json:"sharingMode,omitempty"`
+
+	// MaxClaims defines the maximum number of ServiceClaims that can claim a
+	// Shared service at the same time. Zero means unlimited.
+	// It is ignored for Exclusive services.
+	// +optional
+	//+kubebuilder:validation:Minimum=0
+	MaxClaims int `json:"maxClaims,omitempty"`
+
+	// RequiresApproval defines whether ServiceClaims need the approval of an
+	// authorized user before claiming the service.
+	// +optional
+	RequiresApproval bool `json:"requiresApproval,omitempty"`
+
+	// ReservedFor is the ID of the ServiceClaim the service has been
+	// provisioned for. Only that ServiceClaim can claim the service.
+	// +optional
+	ReservedFor string `json:"reservedFor,omitempty"`
 }
 
 func (s RegisteredServiceSpec) GetEnvironmentConstraints() []string {
@@ -97,6 +136,12 @@ func (s RegisteredServiceSpec) GetEnvironmentConstraints() []string {
 	return nil
 }
 
+// IsShared returns true if the service can be claimed by more than one
+// ServiceClaim at a time
+func (s RegisteredServiceSpec) IsShared() bool {
+	return s.SharingMode == RegisteredServiceSharingModeShared
+}
+
 // RegisteredServiceStatus defines the observed state of RegisteredService.
 type RegisteredServiceStatus struct {
 	// State describes the current state of the service.
@@ -104,6 +149,14 @@ type RegisteredServiceStatus struct {
 	//+kubebuilder:validation:Enum=Available;Claimed;Unknown;Unreachable
 	//+kubebuilder:default:=Unknown
 	State RegisteredServiceState `json:"state,omitempty"`
+
+	// LastClaimTime is the last time the service has been claimed.
+	// +optional
+	LastClaimTime *metav1.Time `json:"lastClaimTime,omitempty"`
+
+	// Claims lists the IDs of the ServiceClaims holding the service.
+	// +optional
+	Claims []string `json:"claims,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -132,3 +185,12 @@ type RegisteredServiceList struct {
 func init() {
 	SchemeBuilder.Register(&RegisteredService{}, &RegisteredServiceList{})
 }
+
+// HasCapacity returns true if the service can be claimed by one more
+// ServiceClaim
+func (rs *RegisteredService) HasCapacity() bool {
+	if !rs.Spec.IsShared() {
+		return len(rs.Status.Claims) == 0
+	}
+	return rs.Spec.MaxClaims == 0 || len(rs.Status.Claims) < rs.Spec.MaxClaims
+}
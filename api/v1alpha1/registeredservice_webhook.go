@@ -0,0 +1,176 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/primaza/primaza/pkg/primaza/constants"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// log is for logging in this package.
+var registeredservicelog = logf.Log.WithName("registeredservice-resource")
+
+type registeredServiceWebhook struct {
+	client client.Client
+}
+
+var _ admission.CustomValidator = &registeredServiceWebhook{}
+var _ admission.CustomDefaulter = &registeredServiceWebhook{}
+
+func (r *RegisteredService) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	w := &registeredServiceWebhook{client: mgr.GetClient()}
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		WithValidator(w).
+		WithDefaulter(w).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/mutate-primaza-io-v1alpha1-registeredservice,mutating=true,failurePolicy=fail,sideEffects=None,groups=primaza.io,resources=registeredservices,verbs=create;update,versions=v1alpha1,name=mregisteredservice.kb.io,admissionReviewVersions=v1
+//+kubebuilder:webhook:path=/validate-primaza-io-v1alpha1-registeredservice,mutating=false,failurePolicy=fail,sideEffects=None,groups=primaza.io,resources=registeredservices,verbs=create;update,versions=v1alpha1,name=vregisteredservice.kb.io,admissionReviewVersions=v1
+
+// Default implements admission.CustomDefaulter
+func (w *registeredServiceWebhook) Default(ctx context.Context, obj runtime.Object) error {
+	r, ok := obj.(*RegisteredService)
+	if !ok {
+		return fmt.Errorf("Object is not a Registered Service")
+	}
+
+	registeredservicelog.Info("default", "name", r.Name)
+	if r.Spec.SharingMode == "" {
+		r.Spec.SharingMode = RegisteredServiceSharingModeExclusive
+	}
+	if r.Spec.Constraints == nil {
+		constraints, err := w.defaultConstraints(ctx, r)
+		if err != nil {
+			return err
+		}
+		if len(constraints) > 0 {
+			r.Spec.Constraints = &RegisteredServiceConstraints{Environments: constraints}
+		}
+	}
+	return nil
+}
+
+// defaultConstraints returns the default constraints of the Environment of
+// the ClusterEnvironment the RegisteredService has been discovered in, if any
+func (w *registeredServiceWebhook) defaultConstraints(ctx context.Context, r *RegisteredService) ([]string, error) {
+	cen, ok := r.GetAnnotations()[constants.ClusterEnvironmentAnnotation]
+	if !ok || w.client == nil {
+		return nil, nil
+	}
+
+	ce := ClusterEnvironment{}
+	if err := w.client.Get(ctx, types.NamespacedName{Namespace: r.Namespace, Name: cen}, &ce); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	env := Environment{}
+	if err := w.client.Get(ctx, types.NamespacedName{Namespace: r.Namespace, Name: ce.Spec.EnvironmentName}, &env); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	return env.Spec.DefaultConstraints, nil
+}
+
+// Validate checks that the ServiceClassIdentity is not empty, and that the
+// Service Endpoint Definition items are well-formed and have unique names
+func (s *RegisteredServiceSpec) Validate() field.ErrorList {
+	errs := field.ErrorList{}
+	specPath := field.NewPath("spec")
+	if len(s.ServiceClassIdentity) == 0 {
+		errs = append(errs, field.Required(specPath.Child("serviceClassIdentity"), "service class identity must be defined"))
+	}
+
+	names := map[string]struct{}{}
+	for i, sed := range s.ServiceEndpointDefinition {
+		path := specPath.Child("serviceEndpointDefinition").Index(i)
+		if sed.Name == "" {
+			errs = append(errs, field.Required(path.Child("name"), "name must be defined"))
+		} else if _, found := names[sed.Name]; found {
+			errs = append(errs, field.Duplicate(path.Child("name"), sed.Name))
+		}
+		names[sed.Name] = struct{}{}
+
+		if sed.Value != "" && sed.ValueFromSecret != nil {
+			errs = append(errs, field.Invalid(path, sed.Name, "value and valueFromSecret are mutually exclusive"))
+		}
+		if r := sed.ValueFromSecret; r != nil {
+			if r.Name == "" {
+				errs = append(errs, field.Required(path.Child("valueFromSecret", "name"), "secret name must be defined"))
+			}
+			if r.Key == "" {
+				errs = append(errs, field.Required(path.Child("valueFromSecret", "key"), "secret key must be defined"))
+			}
+		}
+	}
+
+	errs = append(errs, validateEnvironmentConstraints(specPath.Child("constraints", "environments"), s.GetEnvironmentConstraints())...)
+	return errs
+}
+
+func (w *registeredServiceWebhook) validate(r *RegisteredService) error {
+	if errs := r.Spec.Validate(); len(errs) > 0 {
+		return apierrors.NewInvalid(GroupVersion.WithKind("RegisteredService").GroupKind(), r.Name, errs)
+	}
+	return nil
+}
+
+// ValidateCreate implements admission.CustomValidator
+func (w *registeredServiceWebhook) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	r, ok := obj.(*RegisteredService)
+	if !ok {
+		err := fmt.Errorf("Object is not a Registered Service")
+		registeredservicelog.Error(err, "Attempted to validate non-RegisteredService resource", "gvk", obj.GetObjectKind().GroupVersionKind())
+		return nil, err
+	}
+
+	registeredservicelog.Info("validate create", "name", r.Name)
+	return nil, w.validate(r)
+}
+
+// ValidateUpdate implements admission.CustomValidator
+func (w *registeredServiceWebhook) ValidateUpdate(ctx context.Context, oldObj runtime.Object, newObj runtime.Object) (admission.Warnings, error) {
+	r, ok := newObj.(*RegisteredService)
+	if !ok {
+		err := fmt.Errorf("Object is not a Registered Service")
+		registeredservicelog.Error(err, "Attempted to validate non-RegisteredService resource", "gvk", newObj.GetObjectKind().GroupVersionKind())
+		return nil, err
+	}
+
+	registeredservicelog.Info("validate update", "name", r.Name)
+
+	// finalizers need to be removed from RegisteredServices
+	// created before this validation was introduced
+	if !r.DeletionTimestamp.IsZero() {
+		return nil, nil
+	}
+	return nil, w.validate(r)
+}
+
+// ValidateDelete implements admission.CustomValidator
+func (w *registeredServiceWebhook) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil // no validation
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/primaza/primaza/pkg/primaza/constants"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("RegisteredService webhook", func() {
+	newRegisteredService := func(sci []ServiceClassIdentityItem, sed ...ServiceEndpointDefinitionItem) *RegisteredService {
+		return &RegisteredService{
+			ObjectMeta: v1.ObjectMeta{Name: "rs", Namespace: "primaza-system"},
+			Spec: RegisteredServiceSpec{
+				ServiceClassIdentity:      sci,
+				ServiceEndpointDefinition: sed,
+			},
+		}
+	}
+	sci := []ServiceClassIdentityItem{{Name: "type", Value: "psql"}}
+	secretRef := &ServiceEndpointDefinitionSecretRef{Name: "secret", Key: "password"}
+
+	DescribeTable("Creation validation",
+		func(rs *RegisteredService, expected []v1.StatusCause) {
+			w := registeredServiceWebhook{}
+			_, err := w.ValidateCreate(context.Background(), rs)
+
+			obtained := causes(err)
+			for i := range obtained {
+				obtained[i].Message = ""
+			}
+			Expect(obtained).To(Equal(expected))
+		},
+		Entry("valid registered service",
+			newRegisteredService(sci,
+				ServiceEndpointDefinitionItem{Name: "host", Value: "localhost"},
+				ServiceEndpointDefinitionItem{Name: "password", ValueFromSecret: secretRef}),
+			nil),
+		Entry("empty service class identity",
+			newRegisteredService(nil, ServiceEndpointDefinitionItem{Name: "host", Value: "localhost"}),
+			[]v1.StatusCause{cause(field.ErrorTypeRequired, "spec.serviceClassIdentity")}),
+		Entry("value and value from secret",
+			newRegisteredService(sci, ServiceEndpointDefinitionItem{Name: "password", Value: "secret", ValueFromSecret: secretRef}),
+			[]v1.StatusCause{cause(field.ErrorTypeInvalid, "spec.serviceEndpointDefinition[0]")}),
+		Entry("incomplete secret reference",
+			newRegisteredService(sci, ServiceEndpointDefinitionItem{Name: "password", ValueFromSecret: &ServiceEndpointDefinitionSecretRef{}}),
+			[]v1.StatusCause{
+				cause(field.ErrorTypeRequired, "spec.serviceEndpointDefinition[0].valueFromSecret.name"),
+				cause(field.ErrorTypeRequired, "spec.serviceEndpointDefinition[0].valueFromSecret.key"),
+			}),
+		Entry("duplicate and missing names",
+			newRegisteredService(sci,
+				ServiceEndpointDefinitionItem{Name: "host", Value: "localhost"},
+				ServiceEndpointDefinitionItem{Name: "host", Value: "127.0.0.1"},
+				ServiceEndpointDefinitionItem{Value: "5432"}),
+			[]v1.StatusCause{
+				cause(field.ErrorTypeDuplicate, "spec.serviceEndpointDefinition[1].name"),
+				cause(field.ErrorTypeRequired, "spec.serviceEndpointDefinition[2].name"),
+			}),
+		Entry("invalid environment constraints",
+			func() *RegisteredService {
+				rs := newRegisteredService(sci, ServiceEndpointDefinitionItem{Name: "host", Value: "localhost"})
+				rs.Spec.Constraints = &RegisteredServiceConstraints{Environments: []string{"prod-* && label:gpu", "(stage"}}
+				return rs
+			}(),
+			[]v1.StatusCause{cause(field.ErrorTypeInvalid, "spec.constraints.environments[1]")}),
+	)
+
+	It("should default the sharing mode", func() {
+		rs := newRegisteredService(sci)
+		w := registeredServiceWebhook{}
+		Expect(w.Default(context.Background(), rs)).To(Succeed())
+		Expect(rs.Spec.SharingMode).To(Equal(RegisteredServiceSharingModeExclusive))
+	})
+
+	Context("default constraints", func() {
+		var w registeredServiceWebhook
+		BeforeEach(func() {
+			scheme, err := SchemeBuilder.Build()
+			Expect(err).NotTo(HaveOccurred())
+			ce := &ClusterEnvironment{
+				ObjectMeta: v1.ObjectMeta{Name: "worker", Namespace: "primaza-system"},
+				Spec:       ClusterEnvironmentSpec{EnvironmentName: "stage"},
+			}
+			env := &Environment{
+				ObjectMeta: v1.ObjectMeta{Name: "stage", Namespace: "primaza-system"},
+				Spec:       EnvironmentSpec{DefaultConstraints: []string{"stage"}},
+			}
+			w = registeredServiceWebhook{
+				client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(ce, env).Build(),
+			}
+		})
+
+		It("should apply the environment's default constraints to discovered services", func() {
+			rs := newRegisteredService(sci)
+			rs.Annotations = map[string]string{constants.ClusterEnvironmentAnnotation: "worker"}
+			Expect(w.Default(context.Background(), rs)).To(Succeed())
+			Expect(rs.Spec.Constraints).To(Equal(&RegisteredServiceConstraints{Environments: []string{"stage"}}))
+		})
+
+		It("should not override the service's constraints", func() {
+			rs := newRegisteredService(sci)
+			rs.Annotations = map[string]string{constants.ClusterEnvironmentAnnotation: "worker"}
+			rs.Spec.Constraints = &RegisteredServiceConstraints{Environments: []string{"!prod"}}
+			Expect(w.Default(context.Background(), rs)).To(Succeed())
+			Expect(rs.Spec.Constraints.Environments).To(Equal([]string{"!prod"}))
+		})
+
+		It("should leave services not discovered in a ClusterEnvironment unconstrained", func() {
+			rs := newRegisteredService(sci)
+			Expect(w.Default(context.Background(), rs)).To(Succeed())
+			Expect(rs.Spec.Constraints).To(BeNil())
+		})
+	})
+})
@@ -25,6 +25,10 @@ const (
 	// projected the secret into the Workload.
 	ServiceBindingBoundCondition = "Bound"
 
+	// ServiceBindingSpecCompliantCondition means the projected secret contains
+	// the entries required by the Service Binding specification.
+	ServiceBindingSpecCompliantCondition = "SpecCompliant"
+
 	ServiceBindingStateReady     ServiceBindingState = "Ready"
 	ServiceBindingStateMalformed ServiceBindingState = "Malformed"
 )
@@ -52,7 +56,25 @@ type ServiceBindingSpec struct {
 	// Envs declares environment variables based on the ServiceEndpointDefinitionSecret to be
 	// projected into the application
 	// +optional
-	Envs []Environment `json:"envs,omitempty"`
+	Envs []EnvironmentVariable `json:"envs,omitempty"`
+
+	// Type is the type of the service, projected into the binding's `type`
+	// entry. If set, it overrides the `type` entry of the
+	// ServiceEndpointDefinitionSecret.
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// Provider is the provider of the service, projected into the binding's
+	// `provider` entry. If set, it overrides the `provider` entry of the
+	// ServiceEndpointDefinitionSecret.
+	// +optional
+	Provider string `json:"provider,omitempty"`
+
+	// RolloutOnSecretChange triggers a rollout of the bound workloads
+	// whenever the data of the ServiceEndpointDefinitionSecret changes,
+	// by updating an annotation in the workloads' pod template.
+	// +optional
+	RolloutOnSecretChange bool `json:"rolloutOnSecretChange,omitempty"`
 }
 
 // ServiceBindingStatus defines the observed state of ServiceBinding.
@@ -40,6 +40,11 @@ type ServiceCatalogService struct {
 	// ServiceEndpointDefinitionKeys defines a set of keys listing the
 	// information this service provides to a workload.
 	ServiceEndpointDefinitionKeys []string `json:"serviceEndpointDefinitionKeys"`
+
+	// GrantedBy is the tenant owning the service, if the service has been
+	// granted by another tenant through a ServiceGrant
+	// +optional
+	GrantedBy string `json:"grantedBy,omitempty"`
 }
 
 // ServiceCatalogSpec defines the desired state of ServiceCatalog
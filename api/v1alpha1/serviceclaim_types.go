@@ -17,6 +17,9 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"time"
+
+	"github.com/primaza/primaza/pkg/primaza/constants"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -28,6 +31,16 @@ const (
 	ServiceClaimStatePending   ServiceClaimState = "Pending"
 	ServiceClaimStateResolved  ServiceClaimState = "Resolved"
 	ServiceClaimStateInvalid   ServiceClaimState = "Invalid"
+	// ServiceClaimStateAwaitingApproval means the ServiceClaim has reserved
+	// a RegisteredService that requires approval, and waits for the decision
+	ServiceClaimStateAwaitingApproval ServiceClaimState = "AwaitingApproval"
+	// ServiceClaimStateRejected means the ServiceClaim has not been approved
+	// to claim the RegisteredService that requires approval
+	ServiceClaimStateRejected ServiceClaimState = "Rejected"
+
+	// ServiceClaimConditionExpiring reports whether a ServiceClaim with a TTL
+	// or an expiration time is about to expire
+	ServiceClaimConditionExpiring ServiceClaimState = "Expiring"
 )
 
 // ServiceClaimSpec defines the desired state of ServiceClaim
@@ -50,7 +63,179 @@ type ServiceClaimSpec struct {
 	Target *ServiceClaimTarget `json:"target,omitempty"`
 	// Envs allows projecting Service Endpoint Definition's data as Environment Variables in the Pod
 	// +optional
-	Envs []Environment `json:"envs,omitempty"`
+	Envs []EnvironmentVariable `json:"envs,omitempty"`
+	// SelectionPolicy defines how to choose among the RegisteredServices
+	// matching the ServiceClaim
+	// +optional
+	SelectionPolicy *ServiceClaimSelectionPolicy `json:"selectionPolicy,omitempty"`
+	// RolloutOnSecretChange triggers a rollout of the bound workloads
+	// whenever the Service Endpoint Definition changes, e.g. because
+	// the service's credentials have been rotated
+	// +optional
+	RolloutOnSecretChange bool `json:"rolloutOnSecretChange,omitempty"`
+	// Parameters are used to render the provisioning template of the
+	// ServiceClass provisioning a new service, when no RegisteredService
+	// matches the ServiceClaim
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+	// FailoverPolicy enables the failover of the resolved ServiceClaim to
+	// another matching RegisteredService when the claimed one becomes
+	// Unreachable
+	// +optional
+	FailoverPolicy *ServiceClaimFailoverPolicy `json:"failoverPolicy,omitempty"`
+	// TTL is how long the ServiceClaim lives after its creation, or after the
+	// last renewal of its lease recorded by the `primaza.io/lease-renewed-at`
+	// annotation. Expired ServiceClaims are deleted
+	// +optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+	// ExpiresAt is when the ServiceClaim expires, regardless of any lease
+	// renewal. Expired ServiceClaims are deleted
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+}
+
+// ServiceClaimFailoverPolicy defines how a resolved ServiceClaim fails over
+// to another RegisteredService when the claimed one becomes Unreachable
+type ServiceClaimFailoverPolicy struct {
+	// GracePeriod is how long the claimed RegisteredService may stay
+	// Unreachable before the ServiceClaim fails over
+	// +optional
+	// +kubebuilder:default:="5m"
+	GracePeriod metav1.Duration `json:"gracePeriod,omitempty"`
+}
+
+// DefaultFailoverGracePeriod is the grace period of failover policies not
+// defining one
+const DefaultFailoverGracePeriod = 5 * time.Minute
+
+// GetGracePeriod returns the failover grace period
+func (p ServiceClaimFailoverPolicy) GetGracePeriod() time.Duration {
+	if p.GracePeriod.Duration == 0 {
+		return DefaultFailoverGracePeriod
+	}
+	return p.GracePeriod.Duration
+}
+
+type ServiceClaimSelectionStrategy string
+
+const (
+	// ServiceClaimSelectionStrategyName chooses the RegisteredService whose
+	// name comes first in lexicographic order
+	ServiceClaimSelectionStrategyName ServiceClaimSelectionStrategy = "Name"
+	// ServiceClaimSelectionStrategyRanked chooses the RegisteredService with
+	// the highest rank, as defined by the `primaza.io/rank` annotation
+	ServiceClaimSelectionStrategyRanked ServiceClaimSelectionStrategy = "Ranked"
+	// ServiceClaimSelectionStrategyLeastRecentlyClaimed chooses the
+	// RegisteredService that has been claimed least recently
+	ServiceClaimSelectionStrategyLeastRecentlyClaimed ServiceClaimSelectionStrategy = "LeastRecentlyClaimed"
+)
+
+// ServiceClaimSelectionPolicy defines how to choose among the
+// RegisteredServices matching a ServiceClaim.
+// Available RegisteredServices are always preferred over Unknown ones,
+// then RegisteredServices with the preferred SLA are preferred over the
+// others. Remaining ties are broken using the Strategy and then the name.
+type ServiceClaimSelectionPolicy struct {
+	// PreferredSLA makes RegisteredServices with the given SLA preferred
+	// +optional
+	PreferredSLA string `json:"preferredSLA,omitempty"`
+	// AllowUnknown allows claiming RegisteredServices whose state is Unknown
+	// +optional
+	AllowUnknown bool `json:"allowUnknown,omitempty"`
+	// Strategy defines how to choose among equally preferred RegisteredServices
+	// +optional
+	//+kubebuilder:validation:Enum=Name;Ranked;LeastRecentlyClaimed
+	//+kubebuilder:default:=Name
+	Strategy ServiceClaimSelectionStrategy `json:"strategy,omitempty"`
+}
+
+// ServiceClaimSelection records the RegisteredService chosen for a
+// ServiceClaim along with the rejected candidates
+type ServiceClaimSelection struct {
+	// Selected is the name of the chosen RegisteredService
+	// +optional
+	Selected string `json:"selected,omitempty"`
+	// Rejected lists the RegisteredServices matching the ServiceClaim's
+	// ServiceClassIdentity that were not chosen
+	// +optional
+	Rejected []ServiceClaimRejectedCandidate `json:"rejected,omitempty"`
+}
+
+// ServiceClaimRejectedCandidate is a RegisteredService that was not chosen
+// for a ServiceClaim
+type ServiceClaimRejectedCandidate struct {
+	// Name of the RegisteredService
+	Name string `json:"name"`
+	// Reason why the RegisteredService was not chosen
+	Reason string `json:"reason"`
+}
+
+// ServiceClaimRejectionReason identifies why a RegisteredService can not
+// resolve a ServiceClaim
+type ServiceClaimRejectionReason string
+
+const (
+	// ServiceClaimRejectionIdentityMismatch is used when the
+	// RegisteredService's ServiceClassIdentity does not include some of the
+	// ServiceClaim's ServiceClassIdentity items
+	ServiceClaimRejectionIdentityMismatch ServiceClaimRejectionReason = "IdentityMismatch"
+	// ServiceClaimRejectionMissingKeys is used when the RegisteredService
+	// does not provide some of the requested Service Endpoint Definition keys
+	ServiceClaimRejectionMissingKeys ServiceClaimRejectionReason = "MissingServiceEndpointDefinitionKeys"
+	// ServiceClaimRejectionEnvironmentConstraint is used when the
+	// RegisteredService's environment constraints are not satisfied by the
+	// ServiceClaim's environment
+	ServiceClaimRejectionEnvironmentConstraint ServiceClaimRejectionReason = "EnvironmentConstraint"
+	// ServiceClaimRejectionClaimed is used when the RegisteredService can
+	// not accept more claims
+	ServiceClaimRejectionClaimed ServiceClaimRejectionReason = "Claimed"
+	// ServiceClaimRejectionUnreachable is used when the RegisteredService is
+	// Unreachable
+	ServiceClaimRejectionUnreachable ServiceClaimRejectionReason = "Unreachable"
+	// ServiceClaimRejectionNotClaimable is used when the RegisteredService's
+	// state does not allow it to be claimed, e.g. it is Unknown
+	ServiceClaimRejectionNotClaimable ServiceClaimRejectionReason = "NotClaimable"
+	// ServiceClaimRejectionReserved is used when the RegisteredService has
+	// been provisioned for another ServiceClaim
+	ServiceClaimRejectionReserved ServiceClaimRejectionReason = "Reserved"
+)
+
+// ServiceClaimExplanation explains why a ServiceClaim is not resolved
+type ServiceClaimExplanation struct {
+	// Environment is the environment the ServiceClaim targets
+	// +optional
+	Environment string `json:"environment,omitempty"`
+	// Candidates lists the RegisteredServices in the ServiceClaim's
+	// namespace, along with the reasons why they can not resolve it
+	// +optional
+	Candidates []ServiceClaimCandidate `json:"candidates,omitempty"`
+	// Truncated is true when some RegisteredServices have been left out of
+	// Candidates
+	// +optional
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// ServiceClaimCandidate is a RegisteredService evaluated for a ServiceClaim
+type ServiceClaimCandidate struct {
+	// Name of the RegisteredService
+	Name string `json:"name"`
+	// Rejections lists why the RegisteredService can not resolve the
+	// ServiceClaim
+	// +optional
+	Rejections []ServiceClaimRejection `json:"rejections,omitempty"`
+}
+
+// ServiceClaimRejection is a reason why a RegisteredService can not resolve
+// a ServiceClaim
+type ServiceClaimRejection struct {
+	// Reason identifies the rejection
+	Reason ServiceClaimRejectionReason `json:"reason"`
+	// Message describes the rejection
+	Message string `json:"message"`
+	// Items lists the ServiceClassIdentity items or Service Endpoint
+	// Definition keys causing the rejection, if any
+	// +optional
+	Items []string `json:"items,omitempty"`
 }
 
 // The Service Claim target.
@@ -66,6 +251,20 @@ type ServiceClaimTarget struct {
 	ApplicationClusterContext *ServiceClaimApplicationClusterContext `json:"applicationClusterContext,omitempty"`
 }
 
+// EnvironmentName returns the environment targeted: the environment tag,
+// or the environment of the ClusterEnvironment looked up in the given map,
+// by ClusterEnvironment name
+func (t *ServiceClaimTarget) EnvironmentName(environments map[string]string) string {
+	switch {
+	case t == nil:
+		return ""
+	case t.ApplicationClusterContext != nil:
+		return environments[t.ApplicationClusterContext.ClusterEnvironmentName]
+	default:
+		return t.EnvironmentTag
+	}
+}
+
 type ServiceClaimApplicationClusterContext struct {
 	ClusterEnvironmentName string `json:"clusterEnvironmentName"`
 	Namespace              string `json:"namespace"`
@@ -74,7 +273,7 @@ type ServiceClaimApplicationClusterContext struct {
 // ServiceClaimStatus defines the observed state of ServiceClaim
 type ServiceClaimStatus struct {
 	// The state of the ServiceClaim observed
-	//+kubebuilder:validation:Enum=Pending;Resolved;Invalid
+	//+kubebuilder:validation:Enum=Pending;Resolved;Invalid;AwaitingApproval;Rejected
 	//+kubebuilder:default:=Pending
 	State ServiceClaimState `json:"state"`
 	// Unique ID For the ServiceClaim
@@ -83,6 +282,129 @@ type ServiceClaimStatus struct {
 	RegisteredService *corev1.ObjectReference `json:"registeredService,omitempty"`
 	// The status of the service binding along with reason and type
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Selection records the outcome of the RegisteredService selection
+	// +optional
+	Selection *ServiceClaimSelection `json:"selection,omitempty"`
+	// Bindings reports the outcome of pushing the ServiceBinding fulfilling
+	// the ServiceClaim into each targeted application namespace
+	// +optional
+	Bindings []ServiceClaimBinding `json:"bindings,omitempty"`
+	// BindingsRefresh schedules the next refresh of the state of the
+	// bindings of a Resolved ServiceClaim, while they are not Ready
+	// +optional
+	BindingsRefresh *ServiceClaimBindingsRefresh `json:"bindingsRefresh,omitempty"`
+	// Provisioning records the service provisioning requested because no
+	// RegisteredService matched the ServiceClaim
+	// +optional
+	Provisioning *ServiceClaimProvisioning `json:"provisioning,omitempty"`
+	// UnreachableSince is when the claimed RegisteredService has been
+	// observed Unreachable first
+	// +optional
+	UnreachableSince *metav1.Time `json:"unreachableSince,omitempty"`
+	// Failovers records the most recent failovers of the ServiceClaim to
+	// another RegisteredService, oldest first
+	// +optional
+	Failovers []ServiceClaimFailover `json:"failovers,omitempty"`
+	// Explanation explains why a Pending ServiceClaim is not resolved
+	// +optional
+	Explanation *ServiceClaimExplanation `json:"explanation,omitempty"`
+	// ValidationErrors lists the causes of the rejection of an Invalid
+	// ServiceClaim
+	// +optional
+	ValidationErrors []metav1.StatusCause `json:"validationErrors,omitempty"`
+	// ExpirationTime is when the ServiceClaim expires, if it has a TTL or an
+	// expiration time
+	// +optional
+	ExpirationTime *metav1.Time `json:"expirationTime,omitempty"`
+	// Approval records the decision taken on the ServiceClaim, when the
+	// claimed RegisteredService requires approval
+	// +optional
+	Approval *ServiceClaimApprovalRecord `json:"approval,omitempty"`
+}
+
+// ServiceClaimApprovalRecord records the decision taken on a ServiceClaim
+// against a RegisteredService that requires approval
+type ServiceClaimApprovalRecord struct {
+	// Decision is the decision taken on the ServiceClaim
+	Decision ServiceClaimApprovalDecision `json:"decision"`
+	// Approver is the user who took the decision
+	// +optional
+	Approver string `json:"approver,omitempty"`
+	// Time is when the decision has been taken
+	// +optional
+	Time *metav1.Time `json:"time,omitempty"`
+	// Reason explains the decision
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+// ServiceClaimFailover records the failover of a ServiceClaim from an
+// Unreachable RegisteredService to another one
+type ServiceClaimFailover struct {
+	// Time is when the failover happened
+	Time metav1.Time `json:"time"`
+	// From is the name of the Unreachable RegisteredService
+	From string `json:"from"`
+	// To is the name of the RegisteredService the ServiceClaim failed over to
+	To string `json:"to"`
+}
+
+// ServiceClaimBindingsRefresh schedules the refresh of the state of the
+// ServiceBindings pushed for a ServiceClaim
+type ServiceClaimBindingsRefresh struct {
+	// Attempts is the number of refreshes since the ServiceBindings have
+	// been pushed
+	Attempts int32 `json:"attempts"`
+	// Time is when the state of the ServiceBindings will be refreshed
+	Time metav1.Time `json:"time"`
+}
+
+// ServiceClaimProvisioning records the ServiceProvisioning created for a
+// ServiceClaim
+type ServiceClaimProvisioning struct {
+	// ServiceClass is the name of the ServiceClass provisioning the service
+	ServiceClass string `json:"serviceClass"`
+	// ClusterEnvironment is the name of the ClusterEnvironment in which the
+	// service is provisioned
+	ClusterEnvironment string `json:"clusterEnvironment"`
+	// Namespace is the service namespace in which the service is provisioned
+	Namespace string `json:"namespace"`
+	// Name is the name of the ServiceProvisioning
+	Name string `json:"name"`
+	// State is the state of the ServiceProvisioning
+	// +optional
+	State ServiceProvisioningState `json:"state,omitempty"`
+	// Message describes the state of the ServiceProvisioning
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Failures is the number of failed attempts at provisioning the service
+	// +optional
+	Failures int32 `json:"failures,omitempty"`
+	// RetryTime is when the service provisioning will be attempted again,
+	// after a failure
+	// +optional
+	RetryTime *metav1.Time `json:"retryTime,omitempty"`
+}
+
+// ServiceClaimBinding reports the state of the ServiceBinding pushed into an
+// application namespace for a ServiceClaim
+type ServiceClaimBinding struct {
+	// ClusterEnvironment is the name of the ClusterEnvironment the
+	// application namespace belongs to
+	ClusterEnvironment string `json:"clusterEnvironment"`
+	// Namespace is the application namespace
+	Namespace string `json:"namespace"`
+	// State is the state of the ServiceBinding, as reported by the
+	// Application Agent
+	// +optional
+	State ServiceBindingState `json:"state,omitempty"`
+	// Workloads is the list of workloads the ServiceBinding is bound to
+	// +optional
+	Workloads []BoundWorkload `json:"workloads,omitempty"`
+	// LastError is the error encountered while pushing the ServiceBinding,
+	// if any
+	// +optional
+	LastError string `json:"lastError,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -115,3 +437,43 @@ func init() {
 func (sc *ServiceClaim) HasDeletionTimestamp() bool {
 	return !sc.DeletionTimestamp.IsZero()
 }
+
+// LeaseStart returns when the current lease of the ServiceClaim started,
+// i.e. the last renewal recorded by the `primaza.io/lease-renewed-at`
+// annotation or, if none is valid, the creation of the ServiceClaim
+func (sc *ServiceClaim) LeaseStart() time.Time {
+	start := sc.CreationTimestamp.Time
+	if v, ok := sc.Annotations[constants.ServiceClaimLeaseRenewedAtAnnotation]; ok {
+		if t, err := time.Parse(time.RFC3339, v); err == nil && t.After(start) {
+			start = t
+		}
+	}
+	return start
+}
+
+// ExpirationTime returns when the ServiceClaim expires, or nil if it has
+// neither a TTL nor an expiration time. When both are defined, the
+// ServiceClaim expires at the earliest
+func (sc *ServiceClaim) ExpirationTime() *time.Time {
+	var expiry *time.Time
+	if sc.Spec.TTL != nil {
+		t := sc.LeaseStart().Add(sc.Spec.TTL.Duration)
+		expiry = &t
+	}
+	if sc.Spec.ExpiresAt != nil && (expiry == nil || sc.Spec.ExpiresAt.Time.Before(*expiry)) {
+		t := sc.Spec.ExpiresAt.Time
+		expiry = &t
+	}
+	return expiry
+}
+
+// BindingsReady returns true if the ServiceBindings have been pushed into
+// every application namespace without errors and are Ready
+func (s ServiceClaimStatus) BindingsReady() bool {
+	for _, b := range s.Bindings {
+		if b.LastError != "" || b.State != ServiceBindingStateReady {
+			return false
+		}
+	}
+	return true
+}
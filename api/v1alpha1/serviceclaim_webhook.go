@@ -0,0 +1,355 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/primaza/primaza/pkg/primaza/constants"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// log is for logging in this package.
+var serviceclaimlog = logf.Log.WithName("serviceclaim-resource")
+
+// serviceClaimValidator validates ServiceClaims against the ServiceCatalog
+// visible from their namespace.
+//
+// In Primaza's control plane, the ServiceCatalog of the targeted environment
+// is used, and ServiceClasses able to provision the claimed service are
+// considered too. In application namespaces, the ServiceCatalogs pushed by
+// Primaza are used and the target, set by the Application Agent, is optional.
+type serviceClaimValidator struct {
+	client client.Client
+	agent  bool
+}
+
+var _ admission.CustomValidator = &serviceClaimValidator{}
+
+// SetupWebhookWithManager sets up the ServiceClaim webhook in Primaza's
+// control plane
+func (r *ServiceClaim) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		WithValidator(&serviceClaimValidator{
+			client: mgr.GetClient(),
+		}).
+		Complete()
+}
+
+// SetupAgentWebhookWithManager sets up the ServiceClaim webhook in the
+// Application Agent
+func (r *ServiceClaim) SetupAgentWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		WithValidator(&serviceClaimValidator{
+			client: mgr.GetClient(),
+			agent:  true,
+		}).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-primaza-io-v1alpha1-serviceclaim,mutating=false,failurePolicy=fail,sideEffects=None,groups=primaza.io,resources=serviceclaims,verbs=create;update,versions=v1alpha1,name=vserviceclaim.kb.io,admissionReviewVersions=v1
+
+// ValidateTarget checks the ServiceClaim's target is consistent.
+// The target is required unless it is going to be set by the Application
+// Agent.
+func (s *ServiceClaimSpec) ValidateTarget(required bool) field.ErrorList {
+	errs := field.ErrorList{}
+	path := field.NewPath("spec", "target")
+	switch {
+	case s.Target == nil || (s.Target.EnvironmentTag == "" && s.Target.ApplicationClusterContext == nil):
+		if required {
+			errs = append(errs, field.Required(path, "one of environmentTag and applicationClusterContext must be defined"))
+		}
+	case s.Target.EnvironmentTag != "" && s.Target.ApplicationClusterContext != nil:
+		errs = append(errs, field.Invalid(path, s.Target, "environmentTag and applicationClusterContext are mutually exclusive"))
+	case s.Target.ApplicationClusterContext != nil:
+		acc := path.Child("applicationClusterContext")
+		if s.Target.ApplicationClusterContext.ClusterEnvironmentName == "" {
+			errs = append(errs, field.Required(acc.Child("clusterEnvironmentName"), "cluster environment name must be defined"))
+		}
+		if s.Target.ApplicationClusterContext.Namespace == "" {
+			errs = append(errs, field.Required(acc.Child("namespace"), "namespace must be defined"))
+		}
+	}
+	return errs
+}
+
+// ValidateLease checks that the TTL is positive and that the lease renewal
+// annotation, if any, is a valid RFC3339 time
+func (r *ServiceClaim) ValidateLease() field.ErrorList {
+	errs := field.ErrorList{}
+	if r.Spec.TTL != nil && r.Spec.TTL.Duration <= 0 {
+		errs = append(errs, field.Invalid(field.NewPath("spec", "ttl"), r.Spec.TTL.Duration.String(), "ttl must be positive"))
+	}
+	if v, ok := r.Annotations[constants.ServiceClaimLeaseRenewedAtAnnotation]; ok {
+		if _, err := time.Parse(time.RFC3339, v); err != nil {
+			p := field.NewPath("metadata", "annotations").Key(constants.ServiceClaimLeaseRenewedAtAnnotation)
+			errs = append(errs, field.Invalid(p, v, "lease renewal time must be in RFC3339 format"))
+		}
+	}
+	return errs
+}
+
+// ValidateAgainstCatalog checks that at least one of the given services
+// matches the ServiceClaim's ServiceClassIdentity and provides all the
+// requested Service Endpoint Definition keys
+func (s *ServiceClaimSpec) ValidateAgainstCatalog(services []ServiceCatalogService) field.ErrorList {
+	errs := field.ErrorList{}
+	sciPath := field.NewPath("spec", "serviceClassIdentity")
+	if len(s.ServiceClassIdentity) == 0 {
+		return append(errs, field.Required(sciPath, "service class identity must be defined"))
+	}
+
+	matching := []ServiceCatalogService{}
+	for _, svc := range services {
+		if includesServiceClassIdentity(svc.ServiceClassIdentity, s.ServiceClassIdentity) {
+			matching = append(matching, svc)
+		}
+	}
+	if len(matching) == 0 {
+		return append(errs, field.Invalid(sciPath, s.ServiceClassIdentity, "no service in the catalog matches the service class identity"))
+	}
+
+	keysPath := field.NewPath("spec", "serviceEndpointDefinitionKeys")
+	for i, k := range s.ServiceEndpointDefinitionKeys {
+		if !slices.ContainsFunc(matching, func(svc ServiceCatalogService) bool {
+			return slices.Contains(svc.ServiceEndpointDefinitionKeys, k)
+		}) {
+			errs = append(errs, field.NotFound(keysPath.Index(i), k))
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	if !slices.ContainsFunc(matching, func(svc ServiceCatalogService) bool {
+		for _, k := range s.ServiceEndpointDefinitionKeys {
+			if !slices.Contains(svc.ServiceEndpointDefinitionKeys, k) {
+				return false
+			}
+		}
+		return true
+	}) {
+		errs = append(errs, field.Invalid(keysPath, s.ServiceEndpointDefinitionKeys, "no single service in the catalog provides all the keys"))
+	}
+	return errs
+}
+
+// includesServiceClassIdentity checks whether all the items are included in
+// the service class identity
+func includesServiceClassIdentity(identity, items []ServiceClassIdentityItem) bool {
+	for _, i := range items {
+		if !slices.Contains(identity, i) {
+			return false
+		}
+	}
+	return true
+}
+
+// visibleServices returns the services the ServiceClaim can be validated
+// against
+func (v *serviceClaimValidator) visibleServices(ctx context.Context, r *ServiceClaim) ([]ServiceCatalogService, *field.Error, error) {
+	catalogs := []ServiceCatalog{}
+	if v.agent {
+		scl := ServiceCatalogList{}
+		if err := v.client.List(ctx, &scl, client.InNamespace(r.Namespace)); err != nil {
+			return nil, nil, err
+		}
+		if len(scl.Items) == 0 {
+			return nil, field.Invalid(field.NewPath("metadata", "namespace"), r.Namespace, "no service catalog found in namespace"), nil
+		}
+		catalogs = scl.Items
+	} else {
+		env, ferr, err := v.environment(ctx, r)
+		if ferr != nil || err != nil {
+			return nil, ferr, err
+		}
+
+		sc := ServiceCatalog{}
+		if err := v.client.Get(ctx, types.NamespacedName{Namespace: r.Namespace, Name: env}, &sc); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, field.Invalid(field.NewPath("spec", "target"), env, fmt.Sprintf("no service catalog found for environment %s", env)), nil
+			}
+			return nil, nil, err
+		}
+		catalogs = append(catalogs, sc)
+	}
+
+	services := []ServiceCatalogService{}
+	for _, c := range catalogs {
+		services = append(services, c.Spec.Services...)
+		for _, s := range c.Spec.ClaimedByLabels {
+			services = append(services, s.ServiceCatalogService)
+		}
+	}
+
+	if !v.agent {
+		// services not registered yet can still be provisioned
+		scl := ServiceClassList{}
+		if err := v.client.List(ctx, &scl, client.InNamespace(r.Namespace)); err != nil {
+			return nil, nil, err
+		}
+		for _, c := range scl.Items {
+			if c.Spec.Provisioning != nil {
+				services = append(services, ServiceCatalogService{
+					Name:                          c.Name,
+					ServiceClassIdentity:          c.Spec.ServiceClassIdentity,
+					ServiceEndpointDefinitionKeys: c.Spec.GetServiceEndpointDefinitionKeys(),
+				})
+			}
+		}
+	}
+	return services, nil, nil
+}
+
+// environment returns the environment targeted by the ServiceClaim
+func (v *serviceClaimValidator) environment(ctx context.Context, r *ServiceClaim) (string, *field.Error, error) {
+	acc := r.Spec.Target.ApplicationClusterContext
+	if acc == nil {
+		return r.Spec.Target.EnvironmentTag, nil, nil
+	}
+
+	ce := ClusterEnvironment{}
+	if err := v.client.Get(ctx, types.NamespacedName{Namespace: r.Namespace, Name: acc.ClusterEnvironmentName}, &ce); err != nil {
+		if apierrors.IsNotFound(err) {
+			path := field.NewPath("spec", "target", "applicationClusterContext", "clusterEnvironmentName")
+			return "", field.NotFound(path, acc.ClusterEnvironmentName), nil
+		}
+		return "", nil, err
+	}
+	return ce.Spec.EnvironmentName, nil, nil
+}
+
+func (v *serviceClaimValidator) invalid(r *ServiceClaim, errs field.ErrorList) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(GroupVersion.WithKind("ServiceClaim").GroupKind(), r.Name, errs)
+}
+
+// ValidateCreate implements admission.CustomValidator
+func (v *serviceClaimValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	r, ok := obj.(*ServiceClaim)
+	if !ok {
+		err := fmt.Errorf("Object is not a Service Claim")
+		serviceclaimlog.Error(err, "Attempted to validate non-ServiceClaim resource", "gvk", obj.GetObjectKind().GroupVersionKind())
+		return nil, err
+	}
+
+	serviceclaimlog.Info("validate create", "name", r.Name, "namespace", r.Namespace)
+	errs := r.Spec.ValidateTarget(!v.agent)
+	errs = append(errs, r.ValidateLease()...)
+	if len(errs) > 0 {
+		return nil, v.invalid(r, errs)
+	}
+
+	services, ferr, err := v.visibleServices(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	if ferr != nil {
+		return nil, v.invalid(r, field.ErrorList{ferr})
+	}
+	if errs := r.Spec.ValidateAgainstCatalog(services); len(errs) > 0 {
+		return nil, v.invalid(r, errs)
+	}
+
+	if v.agent {
+		return nil, nil
+	}
+	return nil, v.checkClaimQuotas(ctx, r)
+}
+
+// checkClaimQuotas rejects the ServiceClaim if it exceeds one of the
+// ClaimQuotas of its namespace
+func (v *serviceClaimValidator) checkClaimQuotas(ctx context.Context, r *ServiceClaim) error {
+	cql := ClaimQuotaList{}
+	if err := v.client.List(ctx, &cql, client.InNamespace(r.Namespace)); err != nil || len(cql.Items) == 0 {
+		return err
+	}
+
+	env, ferr, err := v.environment(ctx, r)
+	if err != nil {
+		return err
+	}
+	if ferr != nil {
+		return v.invalid(r, field.ErrorList{ferr})
+	}
+	scl := ServiceClaimList{}
+	if err := v.client.List(ctx, &scl, client.InNamespace(r.Namespace)); err != nil {
+		return err
+	}
+	cel := ClusterEnvironmentList{}
+	if err := v.client.List(ctx, &cel, client.InNamespace(r.Namespace)); err != nil {
+		return err
+	}
+	environments := map[string]string{}
+	for _, ce := range cel.Items {
+		environments[ce.Name] = ce.Spec.EnvironmentName
+	}
+
+	for _, q := range cql.Items {
+		if !q.Matches(*r, env) {
+			continue
+		}
+		if used := len(q.CountedClaims(scl.Items, environments)); used >= q.Spec.Hard {
+			err := fmt.Errorf("exceeded claim quota %s: requested 1, used %d, limited %d", q.Name, used, q.Spec.Hard)
+			return apierrors.NewForbidden(GroupVersion.WithResource("serviceclaims").GroupResource(), r.Name, err)
+		}
+	}
+	return nil
+}
+
+// ValidateUpdate implements admission.CustomValidator
+func (v *serviceClaimValidator) ValidateUpdate(ctx context.Context, oldObj runtime.Object, newObj runtime.Object) (admission.Warnings, error) {
+	newClaim, ok := newObj.(*ServiceClaim)
+	if !ok {
+		err := fmt.Errorf("Object is not a Service Claim")
+		serviceclaimlog.Error(err, "Attempted to validate non-ServiceClaim resource", "gvk", newObj.GetObjectKind().GroupVersionKind())
+		return nil, err
+	}
+
+	serviceclaimlog.Info("validate update", "name", newClaim.Name, "namespace", newClaim.Namespace)
+
+	oldClaim, ok := oldObj.(*ServiceClaim)
+	if !ok {
+		return nil, fmt.Errorf("Old object is not a ServiceClaim")
+	}
+
+	if !reflect.DeepEqual(oldClaim.Spec, newClaim.Spec) {
+		return nil, v.invalid(newClaim, field.ErrorList{field.Forbidden(field.NewPath("spec"), "spec is immutable")})
+	}
+	return nil, v.invalid(newClaim, newClaim.ValidateLease())
+}
+
+// ValidateDelete implements admission.CustomValidator
+func (v *serviceClaimValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil // no validation
+}
@@ -0,0 +1,271 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/primaza/primaza/pkg/primaza/constants"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newServiceClaim(namespace string, target *ServiceClaimTarget, keys ...string) ServiceClaim {
+	return ServiceClaim{
+		ObjectMeta: v1.ObjectMeta{Name: "claim", Namespace: namespace},
+		Spec: ServiceClaimSpec{
+			ServiceClassIdentity:          []ServiceClassIdentityItem{{Name: "type", Value: "psql"}},
+			ServiceEndpointDefinitionKeys: keys,
+			Target:                        target,
+		},
+	}
+}
+
+func newServiceCatalog(name, namespace string, services ...ServiceCatalogService) *ServiceCatalog {
+	return &ServiceCatalog{
+		ObjectMeta: v1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       ServiceCatalogSpec{Services: services},
+	}
+}
+
+func causes(err error) []v1.StatusCause {
+	if err == nil {
+		return nil
+	}
+	Expect(apierrors.IsInvalid(err)).To(BeTrue())
+	return err.(apierrors.APIStatus).Status().Details.Causes
+}
+
+func cause(t field.ErrorType, path string) v1.StatusCause {
+	return v1.StatusCause{Type: v1.CauseType(t), Field: path}
+}
+
+var _ = Describe("ServiceClaim webhook", func() {
+	psql := ServiceCatalogService{
+		Name:                          "psql",
+		ServiceClassIdentity:          []ServiceClassIdentityItem{{Name: "type", Value: "psql"}, {Name: "provider", Value: "aws"}},
+		ServiceEndpointDefinitionKeys: []string{"host", "port"},
+	}
+	psqlUser := ServiceCatalogService{
+		Name:                          "psql-user",
+		ServiceClassIdentity:          []ServiceClassIdentityItem{{Name: "type", Value: "psql"}},
+		ServiceEndpointDefinitionKeys: []string{"user"},
+	}
+	ce := &ClusterEnvironment{
+		ObjectMeta: v1.ObjectMeta{Name: "worker", Namespace: "primaza-system"},
+		Spec:       ClusterEnvironmentSpec{EnvironmentName: "dev"},
+	}
+	provisioner := &ServiceClass{
+		ObjectMeta: v1.ObjectMeta{Name: "provisioner", Namespace: "primaza-system"},
+		Spec: ServiceClassSpec{
+			ServiceClassIdentity: []ServiceClassIdentityItem{{Name: "type", Value: "psql"}},
+			Resource: ServiceClassResource{
+				ServiceEndpointDefinitionMappings: ServiceEndpointDefinitionMappings{
+					ResourceFields: []ServiceClassResourceFieldMapping{{Name: "database", JsonPath: ".spec.database"}},
+				},
+			},
+			Provisioning: &ServiceClassProvisioning{Template: "kind: Database"},
+		},
+	}
+	envTarget := &ServiceClaimTarget{EnvironmentTag: "dev"}
+
+	newValidator := func(agent bool, objs ...client.Object) serviceClaimValidator {
+		scheme, err := SchemeBuilder.Build()
+		Expect(err).NotTo(HaveOccurred())
+		return serviceClaimValidator{
+			client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+			agent:  agent,
+		}
+	}
+
+	DescribeTable("Creation validation in the control plane",
+		func(sclaim ServiceClaim, expected []v1.StatusCause) {
+			validator := newValidator(false,
+				newServiceCatalog("dev", "primaza-system", psql, psqlUser),
+				ce,
+				provisioner)
+			_, err := validator.ValidateCreate(context.Background(), &sclaim)
+
+			obtained := causes(err)
+			for i := range obtained {
+				obtained[i].Message = ""
+			}
+			Expect(obtained).To(Equal(expected))
+		},
+		Entry("matching environment tag claim",
+			newServiceClaim("primaza-system", envTarget, "host", "port"), nil),
+		Entry("matching cluster environment claim",
+			newServiceClaim("primaza-system", &ServiceClaimTarget{
+				ApplicationClusterContext: &ServiceClaimApplicationClusterContext{ClusterEnvironmentName: "worker", Namespace: "applications"},
+			}, "host"), nil),
+		Entry("matching provisioning service class",
+			newServiceClaim("primaza-system", envTarget, "database"), nil),
+		Entry("missing target",
+			newServiceClaim("primaza-system", nil, "host"),
+			[]v1.StatusCause{cause(field.ErrorTypeRequired, "spec.target")}),
+		Entry("inconsistent target",
+			newServiceClaim("primaza-system", &ServiceClaimTarget{
+				EnvironmentTag:            "dev",
+				ApplicationClusterContext: &ServiceClaimApplicationClusterContext{ClusterEnvironmentName: "worker", Namespace: "applications"},
+			}, "host"),
+			[]v1.StatusCause{cause(field.ErrorTypeInvalid, "spec.target")}),
+		Entry("incomplete application cluster context",
+			newServiceClaim("primaza-system", &ServiceClaimTarget{
+				ApplicationClusterContext: &ServiceClaimApplicationClusterContext{},
+			}, "host"),
+			[]v1.StatusCause{
+				cause(field.ErrorTypeRequired, "spec.target.applicationClusterContext.clusterEnvironmentName"),
+				cause(field.ErrorTypeRequired, "spec.target.applicationClusterContext.namespace"),
+			}),
+		Entry("unknown cluster environment",
+			newServiceClaim("primaza-system", &ServiceClaimTarget{
+				ApplicationClusterContext: &ServiceClaimApplicationClusterContext{ClusterEnvironmentName: "other", Namespace: "applications"},
+			}, "host"),
+			[]v1.StatusCause{cause(field.ErrorTypeNotFound, "spec.target.applicationClusterContext.clusterEnvironmentName")}),
+		Entry("no catalog for environment",
+			newServiceClaim("primaza-system", &ServiceClaimTarget{EnvironmentTag: "prod"}, "host"),
+			[]v1.StatusCause{cause(field.ErrorTypeInvalid, "spec.target")}),
+		Entry("unknown service class identity",
+			func() ServiceClaim {
+				sc := newServiceClaim("primaza-system", envTarget, "host")
+				sc.Spec.ServiceClassIdentity = []ServiceClassIdentityItem{{Name: "type", Value: "mysql"}}
+				return sc
+			}(),
+			[]v1.StatusCause{cause(field.ErrorTypeInvalid, "spec.serviceClassIdentity")}),
+		Entry("unknown keys",
+			newServiceClaim("primaza-system", envTarget, "host", "url"),
+			[]v1.StatusCause{cause(field.ErrorTypeNotFound, "spec.serviceEndpointDefinitionKeys[1]")}),
+		Entry("keys spread over services",
+			newServiceClaim("primaza-system", envTarget, "host", "user"),
+			[]v1.StatusCause{cause(field.ErrorTypeInvalid, "spec.serviceEndpointDefinitionKeys")}),
+		Entry("claim with a TTL",
+			func() ServiceClaim {
+				sc := newServiceClaim("primaza-system", envTarget, "host")
+				sc.Spec.TTL = &v1.Duration{Duration: time.Hour}
+				return sc
+			}(), nil),
+		Entry("non positive TTL",
+			func() ServiceClaim {
+				sc := newServiceClaim("primaza-system", envTarget, "host")
+				sc.Spec.TTL = &v1.Duration{Duration: -time.Hour}
+				return sc
+			}(),
+			[]v1.StatusCause{cause(field.ErrorTypeInvalid, "spec.ttl")}),
+	)
+
+	DescribeTable("Creation validation in the application agent",
+		func(objs []client.Object, sclaim ServiceClaim, expected []v1.StatusCause) {
+			validator := newValidator(true, objs...)
+			_, err := validator.ValidateCreate(context.Background(), &sclaim)
+
+			obtained := causes(err)
+			for i := range obtained {
+				obtained[i].Message = ""
+			}
+			Expect(obtained).To(Equal(expected))
+		},
+		Entry("matching claim without target",
+			[]client.Object{newServiceCatalog("dev", "applications", psql)},
+			newServiceClaim("applications", nil, "host"), nil),
+		Entry("provisioning service classes are not visible",
+			[]client.Object{newServiceCatalog("dev", "applications", psql), provisioner},
+			newServiceClaim("applications", nil, "database"),
+			[]v1.StatusCause{cause(field.ErrorTypeNotFound, "spec.serviceEndpointDefinitionKeys[0]")}),
+		Entry("no catalog in namespace",
+			[]client.Object{newServiceCatalog("dev", "primaza-system", psql)},
+			newServiceClaim("applications", nil, "host"),
+			[]v1.StatusCause{cause(field.ErrorTypeInvalid, "metadata.namespace")}),
+	)
+
+	It("should reject spec updates", func() {
+		validator := newValidator(false)
+		oldClaim := newServiceClaim("primaza-system", envTarget, "host")
+		newClaim := *oldClaim.DeepCopy()
+		newClaim.Finalizers = []string{"serviceclaims.primaza.io/finalizer"}
+
+		_, err := validator.ValidateUpdate(context.Background(), &oldClaim, &newClaim)
+		Expect(err).NotTo(HaveOccurred())
+
+		newClaim.Spec.ServiceEndpointDefinitionKeys = []string{"port"}
+		_, err = validator.ValidateUpdate(context.Background(), &oldClaim, &newClaim)
+		Expect(causes(err)).To(ConsistOf(HaveField("Field", "spec")))
+	})
+
+	It("should validate lease renewals", func() {
+		validator := newValidator(false)
+		oldClaim := newServiceClaim("primaza-system", envTarget, "host")
+		oldClaim.Spec.TTL = &v1.Duration{Duration: time.Hour}
+		newClaim := *oldClaim.DeepCopy()
+
+		newClaim.Annotations = map[string]string{constants.ServiceClaimLeaseRenewedAtAnnotation: "2023-06-01T10:00:00Z"}
+		_, err := validator.ValidateUpdate(context.Background(), &oldClaim, &newClaim)
+		Expect(err).NotTo(HaveOccurred())
+
+		newClaim.Annotations[constants.ServiceClaimLeaseRenewedAtAnnotation] = "yesterday"
+		_, err = validator.ValidateUpdate(context.Background(), &oldClaim, &newClaim)
+		Expect(causes(err)).To(ConsistOf(HaveField("Field", "metadata.annotations[primaza.io/lease-renewed-at]")))
+	})
+
+	DescribeTable("Creation validation against claim quotas",
+		func(quota ClaimQuota, existing []ServiceClaimState, allowed bool) {
+			objs := []client.Object{newServiceCatalog("dev", "primaza-system", psql, psqlUser), ce, &quota}
+			for i, state := range existing {
+				sc := newServiceClaim("primaza-system", envTarget, "host")
+				sc.Name = fmt.Sprintf("existing-%d", i)
+				sc.Status.State = state
+				objs = append(objs, &sc)
+			}
+			validator := newValidator(false, objs...)
+
+			sclaim := newServiceClaim("primaza-system", envTarget, "host")
+			_, err := validator.ValidateCreate(context.Background(), &sclaim)
+			if allowed {
+				Expect(err).NotTo(HaveOccurred())
+			} else {
+				Expect(apierrors.IsForbidden(err)).To(BeTrue())
+			}
+		},
+		Entry("quota not reached",
+			ClaimQuota{ObjectMeta: v1.ObjectMeta{Name: "quota", Namespace: "primaza-system"}, Spec: ClaimQuotaSpec{Hard: 2}},
+			[]ServiceClaimState{ServiceClaimStateResolved}, true),
+		Entry("quota reached",
+			ClaimQuota{ObjectMeta: v1.ObjectMeta{Name: "quota", Namespace: "primaza-system"}, Spec: ClaimQuotaSpec{Hard: 2}},
+			[]ServiceClaimState{ServiceClaimStateResolved, ServiceClaimStatePending}, false),
+		Entry("rejected claims do not count",
+			ClaimQuota{ObjectMeta: v1.ObjectMeta{Name: "quota", Namespace: "primaza-system"}, Spec: ClaimQuotaSpec{Hard: 2}},
+			[]ServiceClaimState{ServiceClaimStateResolved, ServiceClaimStateRejected}, true),
+		Entry("quota for another environment",
+			ClaimQuota{ObjectMeta: v1.ObjectMeta{Name: "quota", Namespace: "primaza-system"}, Spec: ClaimQuotaSpec{Hard: 1, Environments: []string{"prod"}}},
+			[]ServiceClaimState{ServiceClaimStateResolved}, true),
+		Entry("quota for the environment",
+			ClaimQuota{ObjectMeta: v1.ObjectMeta{Name: "quota", Namespace: "primaza-system"}, Spec: ClaimQuotaSpec{Hard: 1, Environments: []string{"dev"}}},
+			[]ServiceClaimState{ServiceClaimStateResolved}, false),
+		Entry("quota for another service class identity",
+			ClaimQuota{ObjectMeta: v1.ObjectMeta{Name: "quota", Namespace: "primaza-system"}, Spec: ClaimQuotaSpec{
+				Hard:                 1,
+				ServiceClassIdentity: []ServiceClassIdentityItem{{Name: "type", Value: "mysql"}},
+			}},
+			[]ServiceClaimState{ServiceClaimStateResolved}, true),
+	)
+})
@@ -0,0 +1,98 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ServiceClaimApprovalDecision is the decision taken on a ServiceClaim
+// against a protected RegisteredService
+// +kubebuilder:validation:Enum=Approved;Rejected
+type ServiceClaimApprovalDecision string
+
+const (
+	// ServiceClaimApprovalDecisionApproved lets the ServiceClaim claim the
+	// RegisteredService
+	ServiceClaimApprovalDecisionApproved ServiceClaimApprovalDecision = "Approved"
+	// ServiceClaimApprovalDecisionRejected prevents the ServiceClaim from
+	// claiming the RegisteredService
+	ServiceClaimApprovalDecisionRejected ServiceClaimApprovalDecision = "Rejected"
+)
+
+// ServiceClaimApprovalSpec defines the desired state of ServiceClaimApproval
+type ServiceClaimApprovalSpec struct {
+	// ServiceClaimName is the name of the ServiceClaim awaiting approval
+	ServiceClaimName string `json:"serviceClaimName"`
+
+	// ClaimID is the ID of the ServiceClaim awaiting approval
+	ClaimID string `json:"claimID"`
+
+	// RegisteredServiceName is the name of the protected RegisteredService
+	// the ServiceClaim needs approval to claim
+	RegisteredServiceName string `json:"registeredServiceName"`
+
+	// Decision is the decision taken by an authorized user. The
+	// ServiceClaim awaits approval until it is set, and it can not be
+	// changed afterwards
+	// +optional
+	Decision ServiceClaimApprovalDecision `json:"decision,omitempty"`
+
+	// Reason explains the decision
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Approver is the user who took the decision. It is set by Primaza's
+	// admission webhook from the user setting the decision
+	// +optional
+	Approver string `json:"approver,omitempty"`
+
+	// DecisionTime is when the decision has been taken. It is set by
+	// Primaza's admission webhook
+	// +optional
+	DecisionTime *metav1.Time `json:"decisionTime,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:printcolumn:name="ServiceClaim",type="string",JSONPath=".spec.serviceClaimName"
+//+kubebuilder:printcolumn:name="RegisteredService",type="string",JSONPath=".spec.registeredServiceName"
+//+kubebuilder:printcolumn:name="Decision",type="string",JSONPath=".spec.decision"
+//+kubebuilder:printcolumn:name="Approver",type="string",JSONPath=".spec.approver"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// ServiceClaimApproval is the Schema for the serviceclaimapprovals API.
+// It is created by Primaza for each ServiceClaim claiming a RegisteredService
+// that requires approval, and holds the decision of an authorized user.
+type ServiceClaimApproval struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ServiceClaimApprovalSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ServiceClaimApprovalList contains a list of ServiceClaimApproval
+type ServiceClaimApprovalList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ServiceClaimApproval `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ServiceClaimApproval{}, &ServiceClaimApprovalList{})
+}
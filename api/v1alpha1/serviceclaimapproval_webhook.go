@@ -0,0 +1,146 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// log is for logging in this package.
+var serviceclaimapprovallog = logf.Log.WithName("serviceclaimapproval-resource")
+
+type serviceClaimApprovalWebhook struct{}
+
+var _ admission.CustomValidator = &serviceClaimApprovalWebhook{}
+var _ admission.CustomDefaulter = &serviceClaimApprovalWebhook{}
+
+func (r *ServiceClaimApproval) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	w := &serviceClaimApprovalWebhook{}
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		WithValidator(w).
+		WithDefaulter(w).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/mutate-primaza-io-v1alpha1-serviceclaimapproval,mutating=true,failurePolicy=fail,sideEffects=None,groups=primaza.io,resources=serviceclaimapprovals,verbs=create;update,versions=v1alpha1,name=mserviceclaimapproval.kb.io,admissionReviewVersions=v1
+//+kubebuilder:webhook:path=/validate-primaza-io-v1alpha1-serviceclaimapproval,mutating=false,failurePolicy=fail,sideEffects=None,groups=primaza.io,resources=serviceclaimapprovals,verbs=create;update,versions=v1alpha1,name=vserviceclaimapproval.kb.io,admissionReviewVersions=v1
+
+// requestUser returns the name of the user performing the admission request
+func requestUser(ctx context.Context) string {
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return ""
+	}
+	return req.UserInfo.Username
+}
+
+// Default implements admission.CustomDefaulter
+func (w *serviceClaimApprovalWebhook) Default(ctx context.Context, obj runtime.Object) error {
+	r, ok := obj.(*ServiceClaimApproval)
+	if !ok {
+		return fmt.Errorf("Object is not a Service Claim Approval")
+	}
+
+	serviceclaimapprovallog.Info("default", "name", r.Name)
+	// record who took a new decision, and when
+	if r.Spec.Decision != "" && r.Spec.DecisionTime == nil {
+		now := metav1.Now()
+		r.Spec.Approver = requestUser(ctx)
+		r.Spec.DecisionTime = &now
+	}
+	return nil
+}
+
+func (w *serviceClaimApprovalWebhook) invalid(r *ServiceClaimApproval, errs field.ErrorList) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(GroupVersion.WithKind("ServiceClaimApproval").GroupKind(), r.Name, errs)
+}
+
+// validateDecision checks that a new decision is recorded as taken by the
+// user performing the request
+func validateDecision(ctx context.Context, r *ServiceClaimApproval) field.ErrorList {
+	if r.Spec.Decision == "" {
+		return nil
+	}
+	if u := requestUser(ctx); r.Spec.Approver != u {
+		return field.ErrorList{field.Invalid(field.NewPath("spec", "approver"), r.Spec.Approver, fmt.Sprintf("approver must be the user taking the decision, %q", u))}
+	}
+	return nil
+}
+
+// ValidateCreate implements admission.CustomValidator
+func (w *serviceClaimApprovalWebhook) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	r, ok := obj.(*ServiceClaimApproval)
+	if !ok {
+		return nil, fmt.Errorf("Object is not a Service Claim Approval")
+	}
+
+	serviceclaimapprovallog.Info("validate create", "name", r.Name)
+	return nil, w.invalid(r, validateDecision(ctx, r))
+}
+
+// ValidateUpdate implements admission.CustomValidator
+func (w *serviceClaimApprovalWebhook) ValidateUpdate(ctx context.Context, oldObj runtime.Object, newObj runtime.Object) (admission.Warnings, error) {
+	o, ok := oldObj.(*ServiceClaimApproval)
+	if !ok {
+		return nil, fmt.Errorf("Old Object is not a Service Claim Approval")
+	}
+	r, ok := newObj.(*ServiceClaimApproval)
+	if !ok {
+		return nil, fmt.Errorf("New Object is not a Service Claim Approval")
+	}
+
+	serviceclaimapprovallog.Info("validate update", "name", r.Name)
+	errs := field.ErrorList{}
+	specPath := field.NewPath("spec")
+	if o.Spec.ServiceClaimName != r.Spec.ServiceClaimName {
+		errs = append(errs, field.Forbidden(specPath.Child("serviceClaimName"), "serviceClaimName is immutable"))
+	}
+	if o.Spec.ClaimID != r.Spec.ClaimID {
+		errs = append(errs, field.Forbidden(specPath.Child("claimID"), "claimID is immutable"))
+	}
+	if o.Spec.RegisteredServiceName != r.Spec.RegisteredServiceName {
+		errs = append(errs, field.Forbidden(specPath.Child("registeredServiceName"), "registeredServiceName is immutable"))
+	}
+	if o.Spec.Decision != "" {
+		// decisions are final
+		if o.Spec.Decision != r.Spec.Decision || o.Spec.Reason != r.Spec.Reason ||
+			o.Spec.Approver != r.Spec.Approver || !o.Spec.DecisionTime.Equal(r.Spec.DecisionTime) {
+			errs = append(errs, field.Forbidden(specPath.Child("decision"), "decision can not be changed once taken"))
+		}
+	} else {
+		errs = append(errs, validateDecision(ctx, r)...)
+	}
+	return nil, w.invalid(r, errs)
+}
+
+// ValidateDelete implements admission.CustomValidator
+func (w *serviceClaimApprovalWebhook) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil // no validation
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var _ = Describe("ServiceClaimApproval webhook", func() {
+	requestBy := func(user string) context.Context {
+		return admission.NewContextWithRequest(context.Background(), admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{UserInfo: authenticationv1.UserInfo{Username: user}},
+		})
+	}
+	newApproval := func(decision ServiceClaimApprovalDecision) *ServiceClaimApproval {
+		return &ServiceClaimApproval{
+			ObjectMeta: v1.ObjectMeta{Name: "claim", Namespace: "primaza-system"},
+			Spec: ServiceClaimApprovalSpec{
+				ServiceClaimName:      "claim",
+				ClaimID:               "claim-id",
+				RegisteredServiceName: "prod-database",
+				Decision:              decision,
+			},
+		}
+	}
+	w := serviceClaimApprovalWebhook{}
+
+	It("should record the approver", func() {
+		a := newApproval("")
+		Expect(w.Default(requestBy("primaza"), a)).To(Succeed())
+		Expect(a.Spec.Approver).To(BeEmpty())
+		Expect(a.Spec.DecisionTime).To(BeNil())
+
+		a.Spec.Decision = ServiceClaimApprovalDecisionApproved
+		Expect(w.Default(requestBy("alice"), a)).To(Succeed())
+		Expect(a.Spec.Approver).To(Equal("alice"))
+		Expect(a.Spec.DecisionTime).NotTo(BeNil())
+	})
+
+	It("should accept decisions taken by the requesting user", func() {
+		o := newApproval("")
+		_, err := w.ValidateCreate(requestBy("primaza"), o)
+		Expect(err).NotTo(HaveOccurred())
+
+		a := newApproval(ServiceClaimApprovalDecisionRejected)
+		Expect(w.Default(requestBy("alice"), a)).To(Succeed())
+		_, err = w.ValidateUpdate(requestBy("alice"), o, a)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should reject forged approvers", func() {
+		o := newApproval("")
+		a := newApproval(ServiceClaimApprovalDecisionApproved)
+		a.Spec.Approver = "bob"
+		now := v1.Now()
+		a.Spec.DecisionTime = &now
+		Expect(w.Default(requestBy("alice"), a)).To(Succeed())
+
+		_, err := w.ValidateUpdate(requestBy("alice"), o, a)
+		Expect(causes(err)).To(ConsistOf(HaveField("Field", "spec.approver")))
+		_, err = w.ValidateCreate(requestBy("alice"), a)
+		Expect(causes(err)).To(ConsistOf(HaveField("Field", "spec.approver")))
+	})
+
+	It("should reject changes to decisions", func() {
+		o := newApproval(ServiceClaimApprovalDecisionRejected)
+		Expect(w.Default(requestBy("alice"), o)).To(Succeed())
+		a := o.DeepCopy()
+		a.Spec.Decision = ServiceClaimApprovalDecisionApproved
+
+		_, err := w.ValidateUpdate(requestBy("alice"), o, a)
+		Expect(causes(err)).To(ConsistOf(HaveField("Field", "spec.decision")))
+
+		a = o.DeepCopy()
+		a.Spec.RegisteredServiceName = "other-database"
+		_, err = w.ValidateUpdate(requestBy("alice"), o, a)
+		Expect(causes(err)).To(ConsistOf(HaveField("Field", "spec.registeredServiceName")))
+	})
+})
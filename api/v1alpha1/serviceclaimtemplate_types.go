@@ -0,0 +1,205 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ServiceClaimTemplateConditionReady reports whether all the ServiceClaims
+	// generated from a ServiceClaimTemplate are Resolved
+	ServiceClaimTemplateConditionReady = "Ready"
+)
+
+// ServiceClaimTemplateClaim defines the ServiceClaim generated for each
+// environment
+type ServiceClaimTemplateClaim struct {
+	// ServiceClassIdentity defines a set of attributes that are sufficient to
+	// identify a service class.
+	// +required
+	ServiceClassIdentity []ServiceClassIdentityItem `json:"serviceClassIdentity"`
+	// ServiceEndpointDefinitionKeys defines a set of attributes sufficient for
+	// a client to establish a connection to the service.
+	// +required
+	ServiceEndpointDefinitionKeys []string `json:"serviceEndpointDefinitionKeys"`
+	// Rules to match workloads to bind
+	// +required
+	Application ApplicationSelector `json:"application"`
+	// Envs allows projecting Service Endpoint Definition's data as Environment Variables in the Pod
+	// +optional
+	Envs []EnvironmentVariable `json:"envs,omitempty"`
+	// SelectionPolicy defines how to choose among the RegisteredServices
+	// matching the ServiceClaims
+	// +optional
+	SelectionPolicy *ServiceClaimSelectionPolicy `json:"selectionPolicy,omitempty"`
+	// RolloutOnSecretChange triggers a rollout of the bound workloads
+	// whenever the Service Endpoint Definition changes
+	// +optional
+	RolloutOnSecretChange bool `json:"rolloutOnSecretChange,omitempty"`
+	// Parameters are used to render the provisioning template of the
+	// ServiceClass provisioning a new service
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+	// FailoverPolicy enables the failover of the resolved ServiceClaims
+	// +optional
+	FailoverPolicy *ServiceClaimFailoverPolicy `json:"failoverPolicy,omitempty"`
+}
+
+// ServiceClaimTemplateEnvironment defines an environment to generate a
+// ServiceClaim for, along with the overrides of the template
+type ServiceClaimTemplateEnvironment struct {
+	// Name of the environment the generated ServiceClaim targets
+	Name string `json:"name"`
+	// ServiceClassIdentity items replace the template's items with the same
+	// name, or are added to them
+	// +optional
+	ServiceClassIdentity []ServiceClassIdentityItem `json:"serviceClassIdentity,omitempty"`
+	// ServiceEndpointDefinitionKeys replace the template's ones, if defined
+	// +optional
+	ServiceEndpointDefinitionKeys []string `json:"serviceEndpointDefinitionKeys,omitempty"`
+	// Application replaces the template's one, if defined
+	// +optional
+	Application *ApplicationSelector `json:"application,omitempty"`
+	// Envs replace the template's ones, if defined
+	// +optional
+	Envs []EnvironmentVariable `json:"envs,omitempty"`
+	// Parameters are merged with the template's ones, taking precedence
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+// ServiceClaimTemplateSpec defines the desired state of ServiceClaimTemplate
+type ServiceClaimTemplateSpec struct {
+	// Template defines the ServiceClaim generated for each environment
+	// +required
+	Template ServiceClaimTemplateClaim `json:"template"`
+	// Environments lists the environments to generate a ServiceClaim for
+	// +required
+	// +kubebuilder:validation:MinItems=1
+	// +listType=map
+	// +listMapKey=name
+	Environments []ServiceClaimTemplateEnvironment `json:"environments"`
+}
+
+// ServiceClaimTemplateClaimStatus reports the state of a ServiceClaim
+// generated from a ServiceClaimTemplate
+type ServiceClaimTemplateClaimStatus struct {
+	// Environment is the environment the ServiceClaim targets
+	Environment string `json:"environment"`
+	// Name of the ServiceClaim
+	Name string `json:"name"`
+	// State of the ServiceClaim
+	// +optional
+	State ServiceClaimState `json:"state,omitempty"`
+	// Message describes why the ServiceClaim is not Resolved, if it is not
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ServiceClaimTemplateStatus defines the observed state of ServiceClaimTemplate
+type ServiceClaimTemplateStatus struct {
+	// Claims reports the state of the generated ServiceClaims
+	// +optional
+	Claims []ServiceClaimTemplateClaimStatus `json:"claims,omitempty"`
+	// Resolved is the number of Resolved generated ServiceClaims
+	// +optional
+	Resolved int32 `json:"resolved,omitempty"`
+	// Status Conditions
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Resolved",type="integer",JSONPath=".status.resolved",description="the number of Resolved ServiceClaims"
+//+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status",description="whether all the ServiceClaims are Resolved"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// ServiceClaimTemplate is the Schema for the serviceclaimtemplates API
+type ServiceClaimTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ServiceClaimTemplateSpec   `json:"spec,omitempty"`
+	Status ServiceClaimTemplateStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ServiceClaimTemplateList contains a list of ServiceClaimTemplate
+type ServiceClaimTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ServiceClaimTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ServiceClaimTemplate{}, &ServiceClaimTemplateList{})
+}
+
+// ServiceClaimName returns the name of the ServiceClaim generated for the
+// environment
+func (t *ServiceClaimTemplate) ServiceClaimName(environment string) string {
+	return t.Name + "-" + environment
+}
+
+// ServiceClaimSpecFor renders the spec of the ServiceClaim generated for the
+// environment, applying the environment's overrides to the template
+func (t *ServiceClaimTemplate) ServiceClaimSpecFor(env ServiceClaimTemplateEnvironment) ServiceClaimSpec {
+	tc := t.Spec.Template.DeepCopy()
+	spec := ServiceClaimSpec{
+		ServiceClassIdentity:          tc.ServiceClassIdentity,
+		ServiceEndpointDefinitionKeys: tc.ServiceEndpointDefinitionKeys,
+		Application:                   tc.Application,
+		Target:                        &ServiceClaimTarget{EnvironmentTag: env.Name},
+		Envs:                          tc.Envs,
+		SelectionPolicy:               tc.SelectionPolicy,
+		RolloutOnSecretChange:         tc.RolloutOnSecretChange,
+		Parameters:                    tc.Parameters,
+		FailoverPolicy:                tc.FailoverPolicy,
+	}
+
+	for _, o := range env.ServiceClassIdentity {
+		found := false
+		for i, item := range spec.ServiceClassIdentity {
+			if item.Name == o.Name {
+				spec.ServiceClassIdentity[i].Value = o.Value
+				found = true
+			}
+		}
+		if !found {
+			spec.ServiceClassIdentity = append(spec.ServiceClassIdentity, o)
+		}
+	}
+	if len(env.ServiceEndpointDefinitionKeys) > 0 {
+		spec.ServiceEndpointDefinitionKeys = append([]string{}, env.ServiceEndpointDefinitionKeys...)
+	}
+	if env.Application != nil {
+		spec.Application = *env.Application.DeepCopy()
+	}
+	if len(env.Envs) > 0 {
+		spec.Envs = append([]EnvironmentVariable{}, env.Envs...)
+	}
+	if len(env.Parameters) > 0 && spec.Parameters == nil {
+		spec.Parameters = map[string]string{}
+	}
+	for k, v := range env.Parameters {
+		spec.Parameters[k] = v
+	}
+	return spec
+}
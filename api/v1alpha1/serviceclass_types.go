@@ -17,21 +17,68 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"text/template"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+const (
+	// ServiceClassNameCollisionCondition means that some of the resources of
+	// the ServiceClass can not be registered because a RegisteredService with
+	// the same name, but originated by a different resource, already exists.
+	ServiceClassNameCollisionCondition = "NameCollision"
+)
+
 type ServiceEndpointDefinitionMappings struct {
 	ResourceFields  []ServiceClassResourceFieldMapping  `json:"resourceFields,omitempty"`
 	SecretRefFields []ServiceClassSecretRefFieldMapping `json:"secretRefFields,omitempty"`
+	// +optional
+	ConfigMapRefFields []ServiceClassConfigMapRefFieldMapping `json:"configMapRefFields,omitempty"`
+	// +optional
+	ObjectRefFields []ServiceClassObjectRefFieldMapping `json:"objectRefFields,omitempty"`
 }
 
+// +kubebuilder:validation:Enum=Base64
+type ServiceClassMappingDecoding string
+
+const (
+	// ServiceClassMappingDecodingBase64 base64-decodes the mapped value
+	ServiceClassMappingDecodingBase64 ServiceClassMappingDecoding = "Base64"
+)
+
+// +kubebuilder:validation:XValidation:rule="has(self.jsonPath) != has(self.template)",message="exactly one of jsonPath and template must be defined"
 type ServiceClassResourceFieldMapping struct {
 	// Name of the data referred to
 	Name string `json:"name"`
 
 	// JsonPath defines where data lives in the service resource.  This query
-	// must resolve to a single value (e.g. not an array of values).
-	JsonPath string `json:"jsonPath"`
+	// must resolve to a single value (e.g. not an array of values), unless a
+	// Separator is defined. It is mutually exclusive with Template.
+	// +optional
+	JsonPath string `json:"jsonPath,omitempty"`
+
+	// Template is a Go template evaluated over the service resource to
+	// compose the value, e.g. `jdbc:postgresql://{{.status.host}}:{{.spec.port}}`.
+	// Besides Go templates' builtins, the `jsonPath`, `default`, `b64enc`,
+	// `b64dec`, and `join` functions are available.
+	// It is mutually exclusive with JsonPath.
+	// +optional
+	Template string `json:"template,omitempty"`
+
+	// Default is the value to use when the JsonPath does not match any
+	// value in the service resource, or the Template evaluates to an empty
+	// string
+	// +optional
+	Default string `json:"default,omitempty"`
+
+	// Separator is used to join the values, when the JsonPath matches more
+	// than one value
+	// +optional
+	Separator string `json:"separator,omitempty"`
+
+	// Decode defines how to decode the mapped value
+	// +optional
+	Decode ServiceClassMappingDecoding `json:"decode,omitempty"`
 
 	// Secret indicates whether or not the mapping data needs to be stored in a secret.
 	// +optional
@@ -52,6 +99,50 @@ type ServiceClassSecretRefFieldMapping struct {
 	SecretKey FieldMapping `json:"secretKey"`
 }
 
+type ServiceClassConfigMapRefFieldMapping struct {
+	// Name of the data referred to
+	Name string `json:"name"`
+
+	// ConfigMapName defines a constant value or a JsonPath used to extract from
+	// resource's specification the name of a linked config map
+	ConfigMapName FieldMapping `json:"configMapName"`
+
+	// ConfigMapKey defines a constant value or a JsonPath used to extract from
+	// resource's specification the Key to be copied from the linked config map
+	ConfigMapKey FieldMapping `json:"configMapKey"`
+
+	// Secret indicates whether or not the mapping data needs to be stored in a secret.
+	// +optional
+	// +kubebuilder:default=true
+	Secret bool `json:"secret"`
+}
+
+type ServiceClassObjectRefFieldMapping struct {
+	// Name of the data referred to
+	Name string `json:"name"`
+
+	// APIVersion of the linked object
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the linked object. The Service Agent is allowed to read
+	// `v1` ConfigMaps, Secrets and Services only.
+	Kind string `json:"kind"`
+
+	// ObjectName defines a constant value or a JsonPath used to extract from
+	// resource's specification the name of the linked object.
+	// The linked object is looked up in the resource's namespace.
+	ObjectName FieldMapping `json:"objectName"`
+
+	// JsonPath defines where data lives in the linked object. This query
+	// must resolve to a single value (e.g. not an array of values).
+	JsonPath string `json:"jsonPath"`
+
+	// Secret indicates whether or not the mapping data needs to be stored in a secret.
+	// +optional
+	// +kubebuilder:default=true
+	Secret bool `json:"secret"`
+}
+
 // +kubebuilder:validation:MaxProperties:=1
 // +kubebuilder:validation:MinProperties:=1
 type FieldMapping struct {
@@ -72,6 +163,33 @@ type ServiceClassResource struct {
 	// ServiceEndpointDefinitionMappings defines how a key-value mapping projected
 	// into services may be constructed.
 	ServiceEndpointDefinitionMappings ServiceEndpointDefinitionMappings `json:"serviceEndpointDefinitionMappings"`
+
+	// LabelSelector restricts the resources converted into Registered
+	// Services to the ones matching the selector
+	// +optional
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+
+	// FieldSelector restricts the resources converted into Registered
+	// Services to the ones matching the selector, e.g. `metadata.name!=test`
+	// +optional
+	FieldSelector string `json:"fieldSelector,omitempty"`
+
+	// Readiness restricts the resources converted into Registered Services
+	// to the ready ones
+	// +optional
+	Readiness *ServiceClassResourceReadiness `json:"readiness,omitempty"`
+}
+
+// ServiceClassResourceReadiness defines when a resource is ready to be
+// converted into a Registered Service
+type ServiceClassResourceReadiness struct {
+	// JsonPath defines where the readiness information lives in the resource,
+	// e.g. `.status.phase`
+	JsonPath string `json:"jsonPath"`
+
+	// Value is the value the JsonPath must resolve to for the resource to
+	// be considered ready, e.g. `Ready`
+	Value string `json:"value"`
 }
 
 // ServiceClassSpec defines the desired state of ServiceClass
@@ -85,6 +203,11 @@ type ServiceClassSpec struct {
 	// +optional
 	HealthCheck *HealthCheck `json:"healthCheck,omitempty"`
 
+	// CredentialIssuer sets the default credential issuer for generated
+	// registered services
+	// +optional
+	CredentialIssuer *CredentialIssuer `json:"credentialIssuer,omitempty"`
+
 	// Resource defines the resource type to be used to convert into Registered
 	// Services
 	Resource ServiceClassResource `json:"resource"`
@@ -93,6 +216,58 @@ type ServiceClassSpec struct {
 	// identify a service class.  A ServiceClaim whose ServiceClassIdentity
 	// field is a subset of a RegisteredService's keys can claim that service.
 	ServiceClassIdentity []ServiceClassIdentityItem `json:"serviceClassIdentity"`
+
+	// Provisioning defines how to provision a new service when a ServiceClaim
+	// matches no RegisteredService
+	// +optional
+	Provisioning *ServiceClassProvisioning `json:"provisioning,omitempty"`
+
+	// RequiresApproval sets whether ServiceClaims need the approval of an
+	// authorized user before claiming the generated registered services
+	// +optional
+	RequiresApproval bool `json:"requiresApproval,omitempty"`
+}
+
+// ServiceReclaimPolicy defines what happens to a provisioned service when the
+// ServiceClaim it has been provisioned for is deleted
+// +kubebuilder:validation:Enum=Retain;Delete
+type ServiceReclaimPolicy string
+
+const (
+	// ServiceReclaimPolicyRetain keeps the provisioned service
+	ServiceReclaimPolicyRetain ServiceReclaimPolicy = "Retain"
+	// ServiceReclaimPolicyDelete deletes the provisioned service
+	ServiceReclaimPolicyDelete ServiceReclaimPolicy = "Delete"
+)
+
+// ServiceClassProvisioning defines how to provision a new service
+type ServiceClassProvisioning struct {
+	// Template is a Go template rendering the manifest of the resource to
+	// create in a service namespace, in YAML or JSON format.
+	// The ServiceClaim's name, namespace, and ID are available as
+	// `.Claim.Name`, `.Claim.Namespace`, and `.Claim.ID`, while the
+	// ServiceClaim's parameters are available as `.Parameters`.
+	Template string `json:"template"`
+
+	// ReclaimPolicy defines what happens to the provisioned resource when
+	// the ServiceClaim is deleted
+	// +optional
+	// +kubebuilder:default:=Delete
+	ReclaimPolicy ServiceReclaimPolicy `json:"reclaimPolicy,omitempty"`
+}
+
+// ParseTemplate parses the provisioning template.
+// Missing parameters are rendered as empty strings.
+func (p ServiceClassProvisioning) ParseTemplate() (*template.Template, error) {
+	return template.New("provisioning").Option("missingkey=zero").Parse(p.Template)
+}
+
+// GetReclaimPolicy returns the reclaim policy, defaulting to Delete
+func (p ServiceClassProvisioning) GetReclaimPolicy() ServiceReclaimPolicy {
+	if p.ReclaimPolicy == "" {
+		return ServiceReclaimPolicyDelete
+	}
+	return p.ReclaimPolicy
 }
 
 func (s ServiceClassSpec) GetEnvironmentConstraints() []string {
@@ -103,6 +278,29 @@ func (s ServiceClassSpec) GetEnvironmentConstraints() []string {
 	return nil
 }
 
+// GetServiceEndpointDefinitionKeys returns the keys of the Service Endpoint
+// Definition of the RegisteredServices generated by the ServiceClass
+func (s ServiceClassSpec) GetServiceEndpointDefinitionKeys() []string {
+	keys := []string{}
+	m := s.Resource.ServiceEndpointDefinitionMappings
+	for _, f := range m.ResourceFields {
+		keys = append(keys, f.Name)
+	}
+	for _, f := range m.SecretRefFields {
+		keys = append(keys, f.Name)
+	}
+	for _, f := range m.ConfigMapRefFields {
+		keys = append(keys, f.Name)
+	}
+	for _, f := range m.ObjectRefFields {
+		keys = append(keys, f.Name)
+	}
+	if s.CredentialIssuer != nil {
+		keys = append(keys, s.CredentialIssuer.Keys...)
+	}
+	return keys
+}
+
 // ServiceClassStatus defines the observed state of ServiceClass
 type ServiceClassStatus struct {
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
@@ -20,8 +20,14 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"slices"
 
+	"github.com/primaza/primaza/pkg/envtag"
+	"github.com/primaza/primaza/pkg/sedtemplate"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/client-go/util/jsonpath"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -33,6 +39,15 @@ import (
 // log is for logging in this package.
 var serviceclasslog = logf.Log.WithName("serviceclass-resource")
 
+// objectRefFieldKinds are the kinds of the objects the Service Agent is
+// allowed to read, and thus of the objects ObjectRefFields can refer to.
+// The Service Agent's permissions are listed in pkg/authz.
+var objectRefFieldKinds = []schema.GroupVersionKind{
+	{Version: "v1", Kind: "ConfigMap"},
+	{Version: "v1", Kind: "Secret"},
+	{Version: "v1", Kind: "Service"},
+}
+
 type serviceClassValidator struct {
 	client client.Client
 }
@@ -57,9 +72,60 @@ func (r *ServiceClassResource) ValidateMapping() field.ErrorList {
 	childPath := field.NewPath("spec", "resource")
 	for i, mapping := range r.ServiceEndpointDefinitionMappings.ResourceFields {
 		path := childPath.Child("serviceEndpointDefinitionMapping").Index(i)
-		j := jsonpath.New("")
-		formatted := fmt.Sprintf("{%v}", mapping.JsonPath)
-		if err := j.Parse(formatted); err != nil {
+		switch {
+		case mapping.JsonPath != "" && mapping.Template != "":
+			errs = append(errs, field.Invalid(path, mapping, "jsonPath and template are mutually exclusive"))
+		case mapping.JsonPath == "" && mapping.Template == "":
+			errs = append(errs, field.Required(path.Child("jsonPath"), "one of jsonPath and template must be defined"))
+		case mapping.Template != "":
+			if _, err := sedtemplate.Parse(mapping.Template); err != nil {
+				errs = append(errs, field.Invalid(path.Child("template"), mapping.Template, fmt.Sprintf("Invalid template: %s", err)))
+			}
+			if mapping.Separator != "" {
+				errs = append(errs, field.Invalid(path.Child("separator"), mapping.Separator, "separator can not be used with template"))
+			}
+		default:
+			j := jsonpath.New("")
+			formatted := fmt.Sprintf("{%v}", mapping.JsonPath)
+			if err := j.Parse(formatted); err != nil {
+				errs = append(errs, field.Invalid(path.Child("jsonPath"), mapping.JsonPath, "Invalid JSONPath"))
+			}
+		}
+		if mapping.Decode != "" && mapping.Decode != ServiceClassMappingDecodingBase64 {
+			errs = append(errs, field.NotSupported(path.Child("decode"), mapping.Decode, []string{string(ServiceClassMappingDecodingBase64)}))
+		}
+		if _, found := names[mapping.Name]; found {
+			errs = append(errs, field.Duplicate(path.Child("name"), mapping.Name))
+		} else {
+			names[mapping.Name] = struct{}{}
+		}
+	}
+
+	mappingsPath := childPath.Child("serviceEndpointDefinitionMappings")
+	for i, mapping := range r.ServiceEndpointDefinitionMappings.ConfigMapRefFields {
+		path := mappingsPath.Child("configMapRefFields").Index(i)
+		errs = append(errs, mapping.ConfigMapName.validate(path.Child("configMapName"))...)
+		errs = append(errs, mapping.ConfigMapKey.validate(path.Child("configMapKey"))...)
+		if _, found := names[mapping.Name]; found {
+			errs = append(errs, field.Duplicate(path.Child("name"), mapping.Name))
+		} else {
+			names[mapping.Name] = struct{}{}
+		}
+	}
+
+	for i, mapping := range r.ServiceEndpointDefinitionMappings.ObjectRefFields {
+		path := mappingsPath.Child("objectRefFields").Index(i)
+		if gv, err := schema.ParseGroupVersion(mapping.APIVersion); err != nil {
+			errs = append(errs, field.Invalid(path.Child("apiVersion"), mapping.APIVersion, "Invalid APIVersion"))
+		} else if gvk := gv.WithKind(mapping.Kind); !slices.Contains(objectRefFieldKinds, gvk) {
+			supported := []string{}
+			for _, k := range objectRefFieldKinds {
+				supported = append(supported, fmt.Sprintf("%s %s", k.GroupVersion(), k.Kind))
+			}
+			errs = append(errs, field.NotSupported(path.Child("kind"), fmt.Sprintf("%s %s", mapping.APIVersion, mapping.Kind), supported))
+		}
+		errs = append(errs, mapping.ObjectName.validate(path.Child("objectName"))...)
+		if err := jsonpath.New("").Parse(fmt.Sprintf("{%v}", mapping.JsonPath)); err != nil {
 			errs = append(errs, field.Invalid(path.Child("jsonPath"), mapping.JsonPath, "Invalid JSONPath"))
 		}
 		if _, found := names[mapping.Name]; found {
@@ -72,6 +138,69 @@ func (r *ServiceClassResource) ValidateMapping() field.ErrorList {
 	return errs
 }
 
+// ValidateFilters validates the filters restricting the resources to be
+// converted into Registered Services
+func (r *ServiceClassResource) ValidateFilters() field.ErrorList {
+	errs := field.ErrorList{}
+	childPath := field.NewPath("spec", "resource")
+	if r.LabelSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(r.LabelSelector); err != nil {
+			errs = append(errs, field.Invalid(childPath.Child("labelSelector"), r.LabelSelector, err.Error()))
+		}
+	}
+	if r.FieldSelector != "" {
+		if _, err := fields.ParseSelector(r.FieldSelector); err != nil {
+			errs = append(errs, field.Invalid(childPath.Child("fieldSelector"), r.FieldSelector, err.Error()))
+		}
+	}
+	if r.Readiness != nil {
+		if err := jsonpath.New("").Parse(fmt.Sprintf("{%v}", r.Readiness.JsonPath)); err != nil {
+			errs = append(errs, field.Invalid(childPath.Child("readiness", "jsonPath"), r.Readiness.JsonPath, "Invalid JSONPath"))
+		}
+	}
+	return errs
+}
+
+// ValidateProvisioning validates the provisioning template
+func (s *ServiceClassSpec) ValidateProvisioning() field.ErrorList {
+	if s.Provisioning == nil {
+		return nil
+	}
+
+	errs := field.ErrorList{}
+	if _, err := s.Provisioning.ParseTemplate(); err != nil {
+		errs = append(errs, field.Invalid(field.NewPath("spec", "provisioning", "template"), s.Provisioning.Template, fmt.Sprintf("Invalid template: %s", err)))
+	}
+	return errs
+}
+
+// ValidateConstraints validates the environment constraints
+func (s *ServiceClassSpec) ValidateConstraints() field.ErrorList {
+	return validateEnvironmentConstraints(field.NewPath("spec", "constraints", "environments"), s.GetEnvironmentConstraints())
+}
+
+// validateEnvironmentConstraints checks that each environment constraint is
+// a valid envtag expression
+func validateEnvironmentConstraints(path *field.Path, constraints []string) field.ErrorList {
+	errs := field.ErrorList{}
+	for i, c := range constraints {
+		if _, err := envtag.Parse(c); err != nil {
+			errs = append(errs, field.Invalid(path.Index(i), c, err.Error()))
+		}
+	}
+	return errs
+}
+
+func (m FieldMapping) validate(path *field.Path) field.ErrorList {
+	if m.JsonPathExpr == nil {
+		return nil
+	}
+	if err := jsonpath.New("").Parse(fmt.Sprintf("{%v}", *m.JsonPathExpr)); err != nil {
+		return field.ErrorList{field.Invalid(path.Child("jsonPath"), *m.JsonPathExpr, "Invalid JSONPath")}
+	}
+	return nil
+}
+
 // ValidateCreate implements admission.CustomValidator
 func (v *serviceClassValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
 	r, ok := obj.(*ServiceClass)
@@ -87,6 +216,9 @@ func (v *serviceClassValidator) ValidateCreate(ctx context.Context, obj runtime.
 		return nil, err
 	}
 	errs = append(errs, r.Spec.Resource.ValidateMapping()...)
+	errs = append(errs, r.Spec.Resource.ValidateFilters()...)
+	errs = append(errs, r.Spec.ValidateProvisioning()...)
+	errs = append(errs, r.Spec.ValidateConstraints()...)
 	return nil, errs.ToAggregate()
 }
 
@@ -143,6 +275,9 @@ func (v *serviceClassValidator) ValidateUpdate(ctx context.Context, oldObj runti
 				"ServiceEndpointDefinitionMapping is immutable"))
 	}
 	errs = append(errs, newClass.Spec.Resource.ValidateMapping()...)
+	errs = append(errs, newClass.Spec.Resource.ValidateFilters()...)
+	errs = append(errs, newClass.Spec.ValidateProvisioning()...)
+	errs = append(errs, newClass.Spec.ValidateConstraints()...)
 	list, err := v.IsDuplicateClass(ctx, *newClass)
 	if err != nil {
 		return nil, err
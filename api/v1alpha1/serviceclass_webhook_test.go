@@ -18,11 +18,15 @@ package v1alpha1
 
 import (
 	"context"
+	"fmt"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/primaza/primaza/pkg/envtag"
+	"github.com/primaza/primaza/pkg/sedtemplate"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
@@ -38,6 +42,30 @@ func newServiceClass(name, namespace string, spec ServiceClassSpec) ServiceClass
 	}
 }
 
+func ptr(s string) *string {
+	return &s
+}
+
+func fieldSelectorParseError(selector string) error {
+	_, err := fields.ParseSelector(selector)
+	return err
+}
+
+func provisioningTemplateParseError(text string) error {
+	_, err := ServiceClassProvisioning{Template: text}.ParseTemplate()
+	return err
+}
+
+func constraintParseError(constraint string) error {
+	_, err := envtag.Parse(constraint)
+	return err
+}
+
+func templateParseError(text string) error {
+	_, err := sedtemplate.Parse(text)
+	return err
+}
+
 var _ = Describe("Webhook tests", func() {
 	var validator serviceClassValidator
 	type validationResult struct {
@@ -115,6 +143,200 @@ var _ = Describe("Webhook tests", func() {
 					field.Duplicate(field.NewPath("spec", "resource", "serviceEndpointDefinitionMapping").Index(1).Child("name"), "x"),
 				}.ToAggregate(),
 			}),
+		Entry("Invalid template",
+			newServiceClass("spam", "eggs",
+				ServiceClassSpec{
+					Resource: ServiceClassResource{
+						APIVersion: "foo.bar/v1",
+						Kind:       "baz",
+						ServiceEndpointDefinitionMappings: ServiceEndpointDefinitionMappings{
+							ResourceFields: []ServiceClassResourceFieldMapping{
+								{
+									Name:     "x",
+									Template: "{{ unknown .spec }}",
+								},
+							},
+						},
+					},
+				},
+			),
+			validationResult{
+				err: field.ErrorList{
+					field.Invalid(field.NewPath("spec", "resource", "serviceEndpointDefinitionMapping").Index(0).Child("template"),
+						"{{ unknown .spec }}", fmt.Sprintf("Invalid template: %s", templateParseError("{{ unknown .spec }}"))),
+				}.ToAggregate(),
+			}),
+		Entry("Invalid reference mappings",
+			newServiceClass("spam", "eggs",
+				ServiceClassSpec{
+					Resource: ServiceClassResource{
+						APIVersion: "foo.bar/v1",
+						Kind:       "baz",
+						ServiceEndpointDefinitionMappings: ServiceEndpointDefinitionMappings{
+							ResourceFields: []ServiceClassResourceFieldMapping{
+								{
+									Name:     "x",
+									JsonPath: ".spec",
+								},
+							},
+							ConfigMapRefFields: []ServiceClassConfigMapRefFieldMapping{
+								{
+									Name:          "y",
+									ConfigMapName: FieldMapping{JsonPathExpr: ptr(".invalid[*")},
+									ConfigMapKey:  FieldMapping{Constant: ptr("y")},
+								},
+							},
+							ObjectRefFields: []ServiceClassObjectRefFieldMapping{
+								{
+									Name:       "x",
+									APIVersion: "v1",
+									Kind:       "Service",
+									ObjectName: FieldMapping{Constant: ptr("svc")},
+									JsonPath:   ".invalid[*",
+								},
+							},
+						},
+					},
+				},
+			),
+			validationResult{
+				err: field.ErrorList{
+					field.Invalid(field.NewPath("spec", "resource", "serviceEndpointDefinitionMappings", "configMapRefFields").Index(0).Child("configMapName", "jsonPath"), ".invalid[*", "Invalid JSONPath"),
+					field.Invalid(field.NewPath("spec", "resource", "serviceEndpointDefinitionMappings", "objectRefFields").Index(0).Child("jsonPath"), ".invalid[*", "Invalid JSONPath"),
+					field.Duplicate(field.NewPath("spec", "resource", "serviceEndpointDefinitionMappings", "objectRefFields").Index(0).Child("name"), "x"),
+				}.ToAggregate(),
+			}),
+		Entry("Unsupported object reference kind",
+			newServiceClass("spam", "eggs",
+				ServiceClassSpec{
+					Resource: ServiceClassResource{
+						APIVersion: "foo.bar/v1",
+						Kind:       "baz",
+						ServiceEndpointDefinitionMappings: ServiceEndpointDefinitionMappings{
+							ObjectRefFields: []ServiceClassObjectRefFieldMapping{
+								{
+									Name:       "x",
+									APIVersion: "apps/v1",
+									Kind:       "Deployment",
+									ObjectName: FieldMapping{Constant: ptr("app")},
+									JsonPath:   ".spec.replicas",
+								},
+							},
+						},
+					},
+				},
+			),
+			validationResult{
+				err: field.ErrorList{
+					field.NotSupported(field.NewPath("spec", "resource", "serviceEndpointDefinitionMappings", "objectRefFields").Index(0).Child("kind"),
+						"apps/v1 Deployment", []string{"v1 ConfigMap", "v1 Secret", "v1 Service"}),
+				}.ToAggregate(),
+			}),
+		Entry("Missing jsonpath and template",
+			newServiceClass("spam", "eggs",
+				ServiceClassSpec{
+					Resource: ServiceClassResource{
+						APIVersion: "foo.bar/v1",
+						Kind:       "baz",
+						ServiceEndpointDefinitionMappings: ServiceEndpointDefinitionMappings{
+							ResourceFields: []ServiceClassResourceFieldMapping{
+								{
+									Name:   "x",
+									Decode: "Hex",
+								},
+							},
+						},
+					},
+				},
+			),
+			validationResult{
+				err: field.ErrorList{
+					field.Required(field.NewPath("spec", "resource", "serviceEndpointDefinitionMapping").Index(0).Child("jsonPath"),
+						"one of jsonPath and template must be defined"),
+					field.NotSupported(field.NewPath("spec", "resource", "serviceEndpointDefinitionMapping").Index(0).Child("decode"),
+						ServiceClassMappingDecoding("Hex"), []string{string(ServiceClassMappingDecodingBase64)}),
+				}.ToAggregate(),
+			}),
+		Entry("Invalid resource filters",
+			newServiceClass("spam", "eggs",
+				ServiceClassSpec{
+					Resource: ServiceClassResource{
+						APIVersion: "foo.bar/v1",
+						Kind:       "baz",
+						ServiceEndpointDefinitionMappings: ServiceEndpointDefinitionMappings{
+							ResourceFields: []ServiceClassResourceFieldMapping{
+								{
+									Name:     "x",
+									JsonPath: ".spec",
+								},
+							},
+						},
+						FieldSelector: "metadata.name",
+						Readiness: &ServiceClassResourceReadiness{
+							JsonPath: ".status.phase[*",
+							Value:    "Ready",
+						},
+					},
+				},
+			),
+			validationResult{
+				err: field.ErrorList{
+					field.Invalid(field.NewPath("spec", "resource", "fieldSelector"), "metadata.name", fieldSelectorParseError("metadata.name").Error()),
+					field.Invalid(field.NewPath("spec", "resource", "readiness", "jsonPath"), ".status.phase[*", "Invalid JSONPath"),
+				}.ToAggregate(),
+			}),
+		Entry("Invalid provisioning template",
+			newServiceClass("spam", "eggs",
+				ServiceClassSpec{
+					Resource: ServiceClassResource{
+						APIVersion: "foo.bar/v1",
+						Kind:       "baz",
+						ServiceEndpointDefinitionMappings: ServiceEndpointDefinitionMappings{
+							ResourceFields: []ServiceClassResourceFieldMapping{
+								{
+									Name:     "x",
+									JsonPath: ".spec",
+								},
+							},
+						},
+					},
+					Provisioning: &ServiceClassProvisioning{
+						Template: "{{ .Claim.Name",
+					},
+				},
+			),
+			validationResult{
+				err: field.ErrorList{
+					field.Invalid(field.NewPath("spec", "provisioning", "template"), "{{ .Claim.Name",
+						fmt.Sprintf("Invalid template: %s", provisioningTemplateParseError("{{ .Claim.Name"))),
+				}.ToAggregate(),
+			}),
+		Entry("Invalid environment constraints",
+			newServiceClass("spam", "eggs",
+				ServiceClassSpec{
+					Resource: ServiceClassResource{
+						APIVersion: "foo.bar/v1",
+						Kind:       "baz",
+						ServiceEndpointDefinitionMappings: ServiceEndpointDefinitionMappings{
+							ResourceFields: []ServiceClassResourceFieldMapping{
+								{
+									Name:     "x",
+									JsonPath: ".spec",
+								},
+							},
+						},
+					},
+					Constraints: &EnvironmentConstraints{
+						Environments: []string{"dev-*", "/prod-(eu/"},
+					},
+				},
+			),
+			validationResult{
+				err: field.ErrorList{
+					field.Invalid(field.NewPath("spec", "constraints", "environments").Index(1), "/prod-(eu/",
+						constraintParseError("/prod-(eu/").Error()),
+				}.ToAggregate(),
+			}),
 	)
 
 	DescribeTable("Update validation failures",
@@ -0,0 +1,104 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"slices"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ServiceGrantSpec defines the desired state of ServiceGrant
+type ServiceGrantSpec struct {
+	// RegisteredServices is the list of names of the granted
+	// RegisteredServices
+	// +optional
+	RegisteredServices []string `json:"registeredServices,omitempty"`
+
+	// ServiceClassIdentity grants the RegisteredServices whose
+	// ServiceClassIdentity includes all the given items
+	// +optional
+	ServiceClassIdentity []ServiceClassIdentityItem `json:"serviceClassIdentity,omitempty"`
+
+	// Tenants is the list of the consumer tenants, identified by the
+	// namespace of their control plane, the RegisteredServices are granted to
+	//+kubebuilder:validation:MinItems=1
+	Tenants []string `json:"tenants"`
+}
+
+// ServiceGrantStatus defines the observed state of ServiceGrant
+type ServiceGrantStatus struct {
+	// RegisteredServices is the list of names of the RegisteredServices
+	// currently granted
+	// +optional
+	RegisteredServices []string `json:"registeredServices,omitempty"`
+
+	// Tenants is the list of the consumer tenants a ServiceGrantReference
+	// has been published to
+	// +optional
+	Tenants []string `json:"tenants,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Tenants",type="string",JSONPath=".spec.tenants",description="the tenants the services are granted to"
+//+kubebuilder:printcolumn:name="Services",type="string",JSONPath=".status.registeredServices",description="the granted registered services"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// ServiceGrant is the Schema for the servicegrants API.
+// It shares some of the RegisteredServices of the tenant with other tenants,
+// whose ServiceClaims can then be resolved by the granted RegisteredServices.
+type ServiceGrant struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ServiceGrantSpec   `json:"spec,omitempty"`
+	Status ServiceGrantStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ServiceGrantList contains a list of ServiceGrant
+type ServiceGrantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ServiceGrant `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ServiceGrant{}, &ServiceGrantList{})
+}
+
+// Grants returns true if the RegisteredService is granted, i.e. it belongs
+// to the grant's tenant and it is either listed by name or it matches the
+// ServiceClassIdentity
+func (g *ServiceGrant) Grants(rs RegisteredService) bool {
+	if rs.Namespace != g.Namespace {
+		return false
+	}
+	if slices.Contains(g.Spec.RegisteredServices, rs.Name) {
+		return true
+	}
+	return len(g.Spec.ServiceClassIdentity) > 0 &&
+		includesServiceClassIdentity(rs.Spec.ServiceClassIdentity, g.Spec.ServiceClassIdentity)
+}
+
+// GrantsTo returns true if the RegisteredServices are granted to the given
+// tenant. A tenant can not be granted its own RegisteredServices.
+func (g *ServiceGrant) GrantsTo(tenant string) bool {
+	return tenant != g.Namespace && slices.Contains(g.Spec.Tenants, tenant)
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ServiceGrantReferenceSpec defines the desired state of ServiceGrantReference
+type ServiceGrantReferenceSpec struct {
+	// Tenant is the tenant owning the ServiceGrant, identified by the
+	// namespace of its control plane
+	Tenant string `json:"tenant"`
+
+	// ServiceGrant is the name of the ServiceGrant
+	ServiceGrant string `json:"serviceGrant"`
+
+	// RegisteredServices is the list of the RegisteredServices currently
+	// granted
+	// +optional
+	RegisteredServices []GrantedRegisteredService `json:"registeredServices,omitempty"`
+}
+
+// GrantedRegisteredService identifies a granted RegisteredService, along
+// with its generation and state, so that changes to the RegisteredService
+// are observed by the consumer tenants
+type GrantedRegisteredService struct {
+	// Name is the name of the RegisteredService
+	Name string `json:"name"`
+
+	// Generation is the generation of the RegisteredService
+	// +optional
+	Generation int64 `json:"generation,omitempty"`
+
+	// State is the state of the RegisteredService
+	// +optional
+	State RegisteredServiceState `json:"state,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:printcolumn:name="Tenant",type="string",JSONPath=".spec.tenant",description="the tenant owning the service grant"
+//+kubebuilder:printcolumn:name="Service Grant",type="string",JSONPath=".spec.serviceGrant",description="the service grant"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// ServiceGrantReference is the Schema for the servicegrantreferences API.
+// It is published by the control plane of the tenant owning a ServiceGrant
+// in the namespace of each consumer tenant, that can then find the
+// RegisteredServices granted to it without reading the ServiceGrants of
+// other tenants.
+type ServiceGrantReference struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ServiceGrantReferenceSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ServiceGrantReferenceList contains a list of ServiceGrantReference
+type ServiceGrantReferenceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ServiceGrantReference `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ServiceGrantReference{}, &ServiceGrantReferenceList{})
+}
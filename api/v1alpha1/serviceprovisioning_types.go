@@ -0,0 +1,103 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+type ServiceProvisioningState string
+
+const (
+	// ServiceProvisioningStatePending means the resource has not been created yet
+	ServiceProvisioningStatePending ServiceProvisioningState = "Pending"
+	// ServiceProvisioningStateProvisioned means the resource has been created
+	ServiceProvisioningStateProvisioned ServiceProvisioningState = "Provisioned"
+	// ServiceProvisioningStateFailed means the resource could not be created
+	ServiceProvisioningStateFailed ServiceProvisioningState = "Failed"
+)
+
+// ServiceProvisioningSpec defines the desired state of ServiceProvisioning
+type ServiceProvisioningSpec struct {
+	// ServiceClassName is the name of the ServiceClass the service is
+	// provisioned from
+	ServiceClassName string `json:"serviceClassName"`
+
+	// ClaimID is the ID of the ServiceClaim the service is provisioned for
+	ClaimID string `json:"claimID"`
+
+	// Manifest is the resource to create in the namespace, rendered from
+	// the ServiceClass' provisioning template
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:EmbeddedResource
+	Manifest runtime.RawExtension `json:"manifest"`
+
+	// ReclaimPolicy defines what happens to the provisioned resource when
+	// the ServiceProvisioning is deleted
+	// +optional
+	// +kubebuilder:default:=Delete
+	ReclaimPolicy ServiceReclaimPolicy `json:"reclaimPolicy,omitempty"`
+}
+
+// ServiceProvisioningStatus defines the observed state of ServiceProvisioning
+type ServiceProvisioningStatus struct {
+	// State is the state of the provisioning
+	//+kubebuilder:validation:Enum=Pending;Provisioned;Failed
+	//+kubebuilder:default:=Pending
+	// +optional
+	State ServiceProvisioningState `json:"state,omitempty"`
+
+	// Resource references the provisioned resource
+	// +optional
+	Resource *corev1.ObjectReference `json:"resource,omitempty"`
+
+	// Message describes the state of the provisioning
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="ServiceClass",type="string",JSONPath=".spec.serviceClassName"
+//+kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// ServiceProvisioning is the Schema for the serviceprovisionings API.
+// It asks the Service Agent to create the resource backing a service
+// requested by a ServiceClaim.
+type ServiceProvisioning struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ServiceProvisioningSpec   `json:"spec,omitempty"`
+	Status ServiceProvisioningStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ServiceProvisioningList contains a list of ServiceProvisioning
+type ServiceProvisioningList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ServiceProvisioning `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ServiceProvisioning{}, &ServiceProvisioningList{})
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// TenantProvisionedCondition reports whether the resources of the
+	// Tenant have been provisioned
+	TenantProvisionedCondition = "Provisioned"
+)
+
+// TenantClaimQuota defines a ClaimQuota of the tenant
+type TenantClaimQuota struct {
+	// Name of the ClaimQuota
+	Name string `json:"name"`
+
+	ClaimQuotaSpec `json:",inline"`
+}
+
+// TenantSpec defines the desired state of Tenant
+type TenantSpec struct {
+	// Environments is the list of environments a ServiceCatalog is created
+	// for in the tenant namespace
+	// +optional
+	Environments []string `json:"environments,omitempty"`
+
+	// ClaimQuotas is the list of ClaimQuotas limiting the ServiceClaims of
+	// the tenant
+	// +optional
+	ClaimQuotas []TenantClaimQuota `json:"claimQuotas,omitempty"`
+
+	// Admins is the list of subjects administering the tenant
+	// +optional
+	Admins []rbacv1.Subject `json:"admins,omitempty"`
+}
+
+// TenantClusterEnvironment reports the state of a ClusterEnvironment of the
+// tenant
+type TenantClusterEnvironment struct {
+	// Name of the ClusterEnvironment
+	Name string `json:"name"`
+
+	// State of the ClusterEnvironment
+	State ClusterEnvironmentState `json:"state,omitempty"`
+}
+
+// TenantStatus defines the observed state of Tenant
+type TenantStatus struct {
+	// Namespace is the namespace of the tenant's control plane
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Health of the tenant, aggregated from the state of its
+	// ClusterEnvironments
+	//+kubebuilder:validation:Enum=Healthy;Degraded;Unhealthy;Unknown
+	// +optional
+	Health TenantHealth `json:"health,omitempty"`
+
+	// ClusterEnvironments reports the state of the ClusterEnvironments of
+	// the tenant
+	// +optional
+	ClusterEnvironments []TenantClusterEnvironment `json:"clusterEnvironments,omitempty"`
+
+	// Status Conditions
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+type TenantHealth string
+
+const (
+	// TenantHealthHealthy is reported when all the ClusterEnvironments are Online
+	TenantHealthHealthy TenantHealth = "Healthy"
+	// TenantHealthDegraded is reported when some ClusterEnvironments are not Online
+	TenantHealthDegraded TenantHealth = "Degraded"
+	// TenantHealthUnhealthy is reported when all the ClusterEnvironments are Offline
+	TenantHealthUnhealthy TenantHealth = "Unhealthy"
+	// TenantHealthUnknown is reported when the tenant has no ClusterEnvironment
+	TenantHealthUnknown TenantHealth = "Unknown"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:name="Namespace",type="string",JSONPath=".status.namespace",description="the namespace of the tenant"
+//+kubebuilder:printcolumn:name="Health",type="string",JSONPath=".status.health",description="the health of the tenant"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// Tenant is the Schema for the tenants API.
+// It provisions a Primaza tenant, whose control plane runs in the namespace
+// named after the Tenant.
+type Tenant struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TenantSpec   `json:"spec,omitempty"`
+	Status TenantStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// TenantList contains a list of Tenant
+type TenantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Tenant `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Tenant{}, &TenantList{})
+}
+
+func (t *Tenant) HasDeletionTimestamp() bool {
+	return !t.DeletionTimestamp.IsZero()
+}
+
+// HealthOf aggregates the state of the tenant's ClusterEnvironments
+func HealthOf(cee []TenantClusterEnvironment) TenantHealth {
+	if len(cee) == 0 {
+		return TenantHealthUnknown
+	}
+
+	online, offline := 0, 0
+	for _, ce := range cee {
+		switch ce.State {
+		case ClusterEnvironmentStateOnline:
+			online++
+		case ClusterEnvironmentStateOffline, "":
+			offline++
+		}
+	}
+	switch {
+	case online == len(cee):
+		return TenantHealthHealthy
+	case offline == len(cee):
+		return TenantHealthUnhealthy
+	default:
+		return TenantHealthDegraded
+	}
+}
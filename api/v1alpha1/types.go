@@ -16,7 +16,10 @@ limitations under the License.
 
 package v1alpha1
 
-import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
 
 // ServiceClassIdentityItem defines an attribute that is necessary to
 // identify a service class.
@@ -57,8 +60,8 @@ type EnvironmentConstraints struct {
 	Environments []string `json:"environments,omitempty"`
 }
 
-// Environment represents a key to Secret data keys and name of the environment variable
-type Environment struct {
+// EnvironmentVariable represents a key to Secret data keys and name of the environment variable
+type EnvironmentVariable struct {
 	// Name of the environment variable
 	Name string `json:"name"`
 
@@ -88,3 +91,59 @@ type HealthCheck struct {
 	// ServiceEndpointDefinition to determine connectivity and access.
 	Container HealthCheckContainer `json:"container"`
 }
+
+// CredentialIssuerContainer defines the container to be used to issue and
+// revoke per-claim credentials for the service.
+// The container is run in a Job with the following environment variables:
+// `PRIMAZA_CREDENTIAL_OPERATION` (`issue` or `revoke`), `PRIMAZA_CLAIM_ID`,
+// `PRIMAZA_CLAIM_NAME`, `PRIMAZA_CLAIM_NAMESPACE` and
+// `PRIMAZA_REGISTERED_SERVICE`.
+// When issuing, the container has to write the issued credentials to the
+// data of the Secret named in the `PRIMAZA_CREDENTIALS_SECRET` environment
+// variable, using the Job's ServiceAccount.
+// When revoking, the issued credentials are mounted as files in the
+// directory named in the `PRIMAZA_CREDENTIALS_PATH` environment variable.
+type CredentialIssuerContainer struct {
+	// Container image with the credential issuer
+	Image string `json:"image"`
+	// Command to execute in the container to issue or revoke the credentials
+	Command []string `json:"command"`
+	// Env defines additional environment variables for the container
+	//+optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+}
+
+// CredentialIssuerWebhook defines the HTTP endpoint to be used to issue and
+// revoke per-claim credentials for the service.
+// The webhook receives a POST request with a JSON body containing the
+// `operation` (`issue` or `revoke`), the `claimID`, `claimName`,
+// `claimNamespace`, `registeredService` and, when revoking, the issued
+// `credentials`.
+// When issuing, the webhook has to reply with a JSON object containing the
+// issued credentials as a JSON object of strings in the `credentials` field.
+type CredentialIssuerWebhook struct {
+	// URL of the webhook
+	URL string `json:"url"`
+	// CABundle is a PEM encoded CA bundle used to validate the webhook's
+	// server certificate. If not set, the system trust roots are used.
+	//+optional
+	CABundle []byte `json:"caBundle,omitempty"`
+}
+
+// CredentialIssuer defines how to issue per-claim credentials for a service.
+// The issued credentials override the ServiceEndpointDefinition values with
+// the same key in the claim's secret, and they are revoked when the claim is
+// deleted.
+// +kubebuilder:validation:XValidation:rule="has(self.container) != has(self.webhook)",message="exactly one among `container` and `webhook` is required"
+type CredentialIssuer struct {
+	// Keys lists the ServiceEndpointDefinition keys whose values are issued
+	// per claim
+	//+kubebuilder:validation:MinItems:=1
+	Keys []string `json:"keys"`
+	// Container defines a container that issues and revokes the credentials
+	//+optional
+	Container *CredentialIssuerContainer `json:"container,omitempty"`
+	// Webhook defines an HTTP endpoint that issues and revokes the credentials
+	//+optional
+	Webhook *CredentialIssuerWebhook `json:"webhook,omitempty"`
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WorkloadResourceMappingContainer defines the mapping for a specific
+// fragment of a workload resource to a Container-like structure.
+type WorkloadResourceMappingContainer struct {
+	// Path is the JSONPath within the workload resource that locates the
+	// list of Container-like structures (e.g. `.spec.template.spec.containers[*]`).
+	// Only fixed-field paths, optionally terminated by `[*]`, are supported.
+	// +required
+	Path string `json:"path"`
+}
+
+// WorkloadResourceMappingTemplate defines the mapping for a specific version
+// of a workload resource to a logical PodTemplateSpec-like structure.
+type WorkloadResourceMappingTemplate struct {
+	// Version is the version of the workload resource that this mapping is
+	// for. The `*` wildcard matches any version not explicitly mapped.
+	// +required
+	Version string `json:"version"`
+
+	// Containers is the collection of mappings to Container-like fragments
+	// of the workload resource. Defaults to mappings appropriate for a
+	// PodSpecable resource.
+	// +optional
+	Containers []WorkloadResourceMappingContainer `json:"containers,omitempty"`
+
+	// Volumes is the JSONPath within the workload resource that locates the
+	// list of volumes. Defaults to `.spec.template.spec.volumes`.
+	// +optional
+	Volumes string `json:"volumes,omitempty"`
+}
+
+// WorkloadResourceMappingSpec defines the desired state of WorkloadResourceMapping
+type WorkloadResourceMappingSpec struct {
+	// Versions is the collection of versions for a given resource, with mappings.
+	// +kubebuilder:validation:MinItems=1
+	Versions []WorkloadResourceMappingTemplate `json:"versions"`
+}
+
+//+kubebuilder:object:root=true
+
+// WorkloadResourceMapping is the Schema for the workloadresourcemappings API.
+// It describes how a workload resource that is not shaped like a PodSpecable
+// resource can be bound. Its name must be in the form `<plural>.<group>`
+// of the workload resource (e.g. `cronjobs.batch`).
+type WorkloadResourceMapping struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec WorkloadResourceMappingSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// WorkloadResourceMappingList contains a list of WorkloadResourceMapping
+type WorkloadResourceMappingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []WorkloadResourceMapping `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&WorkloadResourceMapping{}, &WorkloadResourceMappingList{})
+}
@@ -23,6 +23,7 @@ package v1alpha1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
@@ -62,6 +63,105 @@ func (in *BoundWorkload) DeepCopy() *BoundWorkload {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClaimQuota) DeepCopyInto(out *ClaimQuota) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClaimQuota.
+func (in *ClaimQuota) DeepCopy() *ClaimQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(ClaimQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClaimQuota) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClaimQuotaList) DeepCopyInto(out *ClaimQuotaList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClaimQuota, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClaimQuotaList.
+func (in *ClaimQuotaList) DeepCopy() *ClaimQuotaList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClaimQuotaList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClaimQuotaList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClaimQuotaSpec) DeepCopyInto(out *ClaimQuotaSpec) {
+	*out = *in
+	if in.Environments != nil {
+		in, out := &in.Environments, &out.Environments
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ServiceClassIdentity != nil {
+		in, out := &in.ServiceClassIdentity, &out.ServiceClassIdentity
+		*out = make([]ServiceClassIdentityItem, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClaimQuotaSpec.
+func (in *ClaimQuotaSpec) DeepCopy() *ClaimQuotaSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClaimQuotaSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClaimQuotaStatus) DeepCopyInto(out *ClaimQuotaStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClaimQuotaStatus.
+func (in *ClaimQuotaStatus) DeepCopy() *ClaimQuotaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClaimQuotaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterEnvironment) DeepCopyInto(out *ClusterEnvironment) {
 	*out = *in
@@ -173,9 +273,90 @@ func (in *ClusterEnvironmentStatus) DeepCopy() *ClusterEnvironmentStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CredentialIssuer) DeepCopyInto(out *CredentialIssuer) {
+	*out = *in
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Container != nil {
+		in, out := &in.Container, &out.Container
+		*out = new(CredentialIssuerContainer)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(CredentialIssuerWebhook)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialIssuer.
+func (in *CredentialIssuer) DeepCopy() *CredentialIssuer {
+	if in == nil {
+		return nil
+	}
+	out := new(CredentialIssuer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CredentialIssuerContainer) DeepCopyInto(out *CredentialIssuerContainer) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialIssuerContainer.
+func (in *CredentialIssuerContainer) DeepCopy() *CredentialIssuerContainer {
+	if in == nil {
+		return nil
+	}
+	out := new(CredentialIssuerContainer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CredentialIssuerWebhook) DeepCopyInto(out *CredentialIssuerWebhook) {
+	*out = *in
+	if in.CABundle != nil {
+		in, out := &in.CABundle, &out.CABundle
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialIssuerWebhook.
+func (in *CredentialIssuerWebhook) DeepCopy() *CredentialIssuerWebhook {
+	if in == nil {
+		return nil
+	}
+	out := new(CredentialIssuerWebhook)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Environment) DeepCopyInto(out *Environment) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Environment.
@@ -188,6 +369,14 @@ func (in *Environment) DeepCopy() *Environment {
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Environment) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EnvironmentConstraints) DeepCopyInto(out *EnvironmentConstraints) {
 	*out = *in
@@ -208,6 +397,100 @@ func (in *EnvironmentConstraints) DeepCopy() *EnvironmentConstraints {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvironmentList) DeepCopyInto(out *EnvironmentList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Environment, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvironmentList.
+func (in *EnvironmentList) DeepCopy() *EnvironmentList {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvironmentList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EnvironmentList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvironmentSpec) DeepCopyInto(out *EnvironmentSpec) {
+	*out = *in
+	if in.DefaultConstraints != nil {
+		in, out := &in.DefaultConstraints, &out.DefaultConstraints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvironmentSpec.
+func (in *EnvironmentSpec) DeepCopy() *EnvironmentSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvironmentSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvironmentStatus) DeepCopyInto(out *EnvironmentStatus) {
+	*out = *in
+	if in.ClusterEnvironments != nil {
+		in, out := &in.ClusterEnvironments, &out.ClusterEnvironments
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvironmentStatus.
+func (in *EnvironmentStatus) DeepCopy() *EnvironmentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvironmentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvironmentVariable) DeepCopyInto(out *EnvironmentVariable) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvironmentVariable.
+func (in *EnvironmentVariable) DeepCopy() *EnvironmentVariable {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvironmentVariable)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FieldMapping) DeepCopyInto(out *FieldMapping) {
 	*out = *in
@@ -233,6 +516,21 @@ func (in *FieldMapping) DeepCopy() *FieldMapping {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrantedRegisteredService) DeepCopyInto(out *GrantedRegisteredService) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrantedRegisteredService.
+func (in *GrantedRegisteredService) DeepCopy() *GrantedRegisteredService {
+	if in == nil {
+		return nil
+	}
+	out := new(GrantedRegisteredService)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HealthCheck) DeepCopyInto(out *HealthCheck) {
 	*out = *in
@@ -275,7 +573,7 @@ func (in *RegisteredService) DeepCopyInto(out *RegisteredService) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegisteredService.
@@ -361,6 +659,11 @@ func (in *RegisteredServiceSpec) DeepCopyInto(out *RegisteredServiceSpec) {
 		*out = new(HealthCheck)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.CredentialIssuer != nil {
+		in, out := &in.CredentialIssuer, &out.CredentialIssuer
+		*out = new(CredentialIssuer)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ServiceClassIdentity != nil {
 		in, out := &in.ServiceClassIdentity, &out.ServiceClassIdentity
 		*out = make([]ServiceClassIdentityItem, len(*in))
@@ -388,6 +691,15 @@ func (in *RegisteredServiceSpec) DeepCopy() *RegisteredServiceSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RegisteredServiceStatus) DeepCopyInto(out *RegisteredServiceStatus) {
 	*out = *in
+	if in.LastClaimTime != nil {
+		in, out := &in.LastClaimTime, &out.LastClaimTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Claims != nil {
+		in, out := &in.Claims, &out.Claims
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegisteredServiceStatus.
@@ -465,7 +777,7 @@ func (in *ServiceBindingSpec) DeepCopyInto(out *ServiceBindingSpec) {
 	in.Application.DeepCopyInto(&out.Application)
 	if in.Envs != nil {
 		in, out := &in.Envs, &out.Envs
-		*out = make([]Environment, len(*in))
+		*out = make([]EnvironmentVariable, len(*in))
 		copy(*out, *in)
 	}
 }
@@ -683,31 +995,25 @@ func (in *ServiceClaimApplicationClusterContext) DeepCopy() *ServiceClaimApplica
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ServiceClaimList) DeepCopyInto(out *ServiceClaimList) {
+func (in *ServiceClaimApproval) DeepCopyInto(out *ServiceClaimApproval) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]ServiceClaim, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClaimList.
-func (in *ServiceClaimList) DeepCopy() *ServiceClaimList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClaimApproval.
+func (in *ServiceClaimApproval) DeepCopy() *ServiceClaimApproval {
 	if in == nil {
 		return nil
 	}
-	out := new(ServiceClaimList)
+	out := new(ServiceClaimApproval)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ServiceClaimList) DeepCopyObject() runtime.Object {
+func (in *ServiceClaimApproval) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -715,90 +1021,1211 @@ func (in *ServiceClaimList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ServiceClaimSpec) DeepCopyInto(out *ServiceClaimSpec) {
+func (in *ServiceClaimApprovalList) DeepCopyInto(out *ServiceClaimApprovalList) {
 	*out = *in
-	if in.ServiceClassIdentity != nil {
-		in, out := &in.ServiceClassIdentity, &out.ServiceClassIdentity
-		*out = make([]ServiceClassIdentityItem, len(*in))
-		copy(*out, *in)
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ServiceClaimApproval, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClaimApprovalList.
+func (in *ServiceClaimApprovalList) DeepCopy() *ServiceClaimApprovalList {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceClaimApprovalList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ServiceClaimApprovalList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceClaimApprovalRecord) DeepCopyInto(out *ServiceClaimApprovalRecord) {
+	*out = *in
+	if in.Time != nil {
+		in, out := &in.Time, &out.Time
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClaimApprovalRecord.
+func (in *ServiceClaimApprovalRecord) DeepCopy() *ServiceClaimApprovalRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceClaimApprovalRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceClaimApprovalSpec) DeepCopyInto(out *ServiceClaimApprovalSpec) {
+	*out = *in
+	if in.DecisionTime != nil {
+		in, out := &in.DecisionTime, &out.DecisionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClaimApprovalSpec.
+func (in *ServiceClaimApprovalSpec) DeepCopy() *ServiceClaimApprovalSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceClaimApprovalSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceClaimBinding) DeepCopyInto(out *ServiceClaimBinding) {
+	*out = *in
+	if in.Workloads != nil {
+		in, out := &in.Workloads, &out.Workloads
+		*out = make([]BoundWorkload, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClaimBinding.
+func (in *ServiceClaimBinding) DeepCopy() *ServiceClaimBinding {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceClaimBinding)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceClaimBindingsRefresh) DeepCopyInto(out *ServiceClaimBindingsRefresh) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClaimBindingsRefresh.
+func (in *ServiceClaimBindingsRefresh) DeepCopy() *ServiceClaimBindingsRefresh {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceClaimBindingsRefresh)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceClaimCandidate) DeepCopyInto(out *ServiceClaimCandidate) {
+	*out = *in
+	if in.Rejections != nil {
+		in, out := &in.Rejections, &out.Rejections
+		*out = make([]ServiceClaimRejection, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClaimCandidate.
+func (in *ServiceClaimCandidate) DeepCopy() *ServiceClaimCandidate {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceClaimCandidate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceClaimExplanation) DeepCopyInto(out *ServiceClaimExplanation) {
+	*out = *in
+	if in.Candidates != nil {
+		in, out := &in.Candidates, &out.Candidates
+		*out = make([]ServiceClaimCandidate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClaimExplanation.
+func (in *ServiceClaimExplanation) DeepCopy() *ServiceClaimExplanation {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceClaimExplanation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceClaimFailover) DeepCopyInto(out *ServiceClaimFailover) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClaimFailover.
+func (in *ServiceClaimFailover) DeepCopy() *ServiceClaimFailover {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceClaimFailover)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceClaimFailoverPolicy) DeepCopyInto(out *ServiceClaimFailoverPolicy) {
+	*out = *in
+	out.GracePeriod = in.GracePeriod
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClaimFailoverPolicy.
+func (in *ServiceClaimFailoverPolicy) DeepCopy() *ServiceClaimFailoverPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceClaimFailoverPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceClaimList) DeepCopyInto(out *ServiceClaimList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ServiceClaim, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClaimList.
+func (in *ServiceClaimList) DeepCopy() *ServiceClaimList {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceClaimList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ServiceClaimList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceClaimProvisioning) DeepCopyInto(out *ServiceClaimProvisioning) {
+	*out = *in
+	if in.RetryTime != nil {
+		in, out := &in.RetryTime, &out.RetryTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClaimProvisioning.
+func (in *ServiceClaimProvisioning) DeepCopy() *ServiceClaimProvisioning {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceClaimProvisioning)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceClaimRejectedCandidate) DeepCopyInto(out *ServiceClaimRejectedCandidate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClaimRejectedCandidate.
+func (in *ServiceClaimRejectedCandidate) DeepCopy() *ServiceClaimRejectedCandidate {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceClaimRejectedCandidate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceClaimRejection) DeepCopyInto(out *ServiceClaimRejection) {
+	*out = *in
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClaimRejection.
+func (in *ServiceClaimRejection) DeepCopy() *ServiceClaimRejection {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceClaimRejection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceClaimSelection) DeepCopyInto(out *ServiceClaimSelection) {
+	*out = *in
+	if in.Rejected != nil {
+		in, out := &in.Rejected, &out.Rejected
+		*out = make([]ServiceClaimRejectedCandidate, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClaimSelection.
+func (in *ServiceClaimSelection) DeepCopy() *ServiceClaimSelection {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceClaimSelection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceClaimSelectionPolicy) DeepCopyInto(out *ServiceClaimSelectionPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClaimSelectionPolicy.
+func (in *ServiceClaimSelectionPolicy) DeepCopy() *ServiceClaimSelectionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceClaimSelectionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceClaimSpec) DeepCopyInto(out *ServiceClaimSpec) {
+	*out = *in
+	if in.ServiceClassIdentity != nil {
+		in, out := &in.ServiceClassIdentity, &out.ServiceClassIdentity
+		*out = make([]ServiceClassIdentityItem, len(*in))
+		copy(*out, *in)
+	}
 	if in.ServiceEndpointDefinitionKeys != nil {
 		in, out := &in.ServiceEndpointDefinitionKeys, &out.ServiceEndpointDefinitionKeys
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	in.Application.DeepCopyInto(&out.Application)
-	if in.Target != nil {
-		in, out := &in.Target, &out.Target
-		*out = new(ServiceClaimTarget)
-		(*in).DeepCopyInto(*out)
+	in.Application.DeepCopyInto(&out.Application)
+	if in.Target != nil {
+		in, out := &in.Target, &out.Target
+		*out = new(ServiceClaimTarget)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Envs != nil {
+		in, out := &in.Envs, &out.Envs
+		*out = make([]EnvironmentVariable, len(*in))
+		copy(*out, *in)
+	}
+	if in.SelectionPolicy != nil {
+		in, out := &in.SelectionPolicy, &out.SelectionPolicy
+		*out = new(ServiceClaimSelectionPolicy)
+		**out = **in
+	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.FailoverPolicy != nil {
+		in, out := &in.FailoverPolicy, &out.FailoverPolicy
+		*out = new(ServiceClaimFailoverPolicy)
+		**out = **in
+	}
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClaimSpec.
+func (in *ServiceClaimSpec) DeepCopy() *ServiceClaimSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceClaimSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceClaimStatus) DeepCopyInto(out *ServiceClaimStatus) {
+	*out = *in
+	if in.RegisteredService != nil {
+		in, out := &in.RegisteredService, &out.RegisteredService
+		*out = new(corev1.ObjectReference)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Selection != nil {
+		in, out := &in.Selection, &out.Selection
+		*out = new(ServiceClaimSelection)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Bindings != nil {
+		in, out := &in.Bindings, &out.Bindings
+		*out = make([]ServiceClaimBinding, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.BindingsRefresh != nil {
+		in, out := &in.BindingsRefresh, &out.BindingsRefresh
+		*out = new(ServiceClaimBindingsRefresh)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Provisioning != nil {
+		in, out := &in.Provisioning, &out.Provisioning
+		*out = new(ServiceClaimProvisioning)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UnreachableSince != nil {
+		in, out := &in.UnreachableSince, &out.UnreachableSince
+		*out = (*in).DeepCopy()
+	}
+	if in.Failovers != nil {
+		in, out := &in.Failovers, &out.Failovers
+		*out = make([]ServiceClaimFailover, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Explanation != nil {
+		in, out := &in.Explanation, &out.Explanation
+		*out = new(ServiceClaimExplanation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ValidationErrors != nil {
+		in, out := &in.ValidationErrors, &out.ValidationErrors
+		*out = make([]v1.StatusCause, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExpirationTime != nil {
+		in, out := &in.ExpirationTime, &out.ExpirationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Approval != nil {
+		in, out := &in.Approval, &out.Approval
+		*out = new(ServiceClaimApprovalRecord)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClaimStatus.
+func (in *ServiceClaimStatus) DeepCopy() *ServiceClaimStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceClaimStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceClaimTarget) DeepCopyInto(out *ServiceClaimTarget) {
+	*out = *in
+	if in.ApplicationClusterContext != nil {
+		in, out := &in.ApplicationClusterContext, &out.ApplicationClusterContext
+		*out = new(ServiceClaimApplicationClusterContext)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClaimTarget.
+func (in *ServiceClaimTarget) DeepCopy() *ServiceClaimTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceClaimTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceClaimTemplate) DeepCopyInto(out *ServiceClaimTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClaimTemplate.
+func (in *ServiceClaimTemplate) DeepCopy() *ServiceClaimTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceClaimTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ServiceClaimTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceClaimTemplateClaim) DeepCopyInto(out *ServiceClaimTemplateClaim) {
+	*out = *in
+	if in.ServiceClassIdentity != nil {
+		in, out := &in.ServiceClassIdentity, &out.ServiceClassIdentity
+		*out = make([]ServiceClassIdentityItem, len(*in))
+		copy(*out, *in)
+	}
+	if in.ServiceEndpointDefinitionKeys != nil {
+		in, out := &in.ServiceEndpointDefinitionKeys, &out.ServiceEndpointDefinitionKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Application.DeepCopyInto(&out.Application)
+	if in.Envs != nil {
+		in, out := &in.Envs, &out.Envs
+		*out = make([]EnvironmentVariable, len(*in))
+		copy(*out, *in)
+	}
+	if in.SelectionPolicy != nil {
+		in, out := &in.SelectionPolicy, &out.SelectionPolicy
+		*out = new(ServiceClaimSelectionPolicy)
+		**out = **in
+	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.FailoverPolicy != nil {
+		in, out := &in.FailoverPolicy, &out.FailoverPolicy
+		*out = new(ServiceClaimFailoverPolicy)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClaimTemplateClaim.
+func (in *ServiceClaimTemplateClaim) DeepCopy() *ServiceClaimTemplateClaim {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceClaimTemplateClaim)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceClaimTemplateClaimStatus) DeepCopyInto(out *ServiceClaimTemplateClaimStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClaimTemplateClaimStatus.
+func (in *ServiceClaimTemplateClaimStatus) DeepCopy() *ServiceClaimTemplateClaimStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceClaimTemplateClaimStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceClaimTemplateEnvironment) DeepCopyInto(out *ServiceClaimTemplateEnvironment) {
+	*out = *in
+	if in.ServiceClassIdentity != nil {
+		in, out := &in.ServiceClassIdentity, &out.ServiceClassIdentity
+		*out = make([]ServiceClassIdentityItem, len(*in))
+		copy(*out, *in)
+	}
+	if in.ServiceEndpointDefinitionKeys != nil {
+		in, out := &in.ServiceEndpointDefinitionKeys, &out.ServiceEndpointDefinitionKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Application != nil {
+		in, out := &in.Application, &out.Application
+		*out = new(ApplicationSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Envs != nil {
+		in, out := &in.Envs, &out.Envs
+		*out = make([]EnvironmentVariable, len(*in))
+		copy(*out, *in)
+	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClaimTemplateEnvironment.
+func (in *ServiceClaimTemplateEnvironment) DeepCopy() *ServiceClaimTemplateEnvironment {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceClaimTemplateEnvironment)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceClaimTemplateList) DeepCopyInto(out *ServiceClaimTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ServiceClaimTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClaimTemplateList.
+func (in *ServiceClaimTemplateList) DeepCopy() *ServiceClaimTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceClaimTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ServiceClaimTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceClaimTemplateSpec) DeepCopyInto(out *ServiceClaimTemplateSpec) {
+	*out = *in
+	in.Template.DeepCopyInto(&out.Template)
+	if in.Environments != nil {
+		in, out := &in.Environments, &out.Environments
+		*out = make([]ServiceClaimTemplateEnvironment, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClaimTemplateSpec.
+func (in *ServiceClaimTemplateSpec) DeepCopy() *ServiceClaimTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceClaimTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceClaimTemplateStatus) DeepCopyInto(out *ServiceClaimTemplateStatus) {
+	*out = *in
+	if in.Claims != nil {
+		in, out := &in.Claims, &out.Claims
+		*out = make([]ServiceClaimTemplateClaimStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClaimTemplateStatus.
+func (in *ServiceClaimTemplateStatus) DeepCopy() *ServiceClaimTemplateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceClaimTemplateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceClass) DeepCopyInto(out *ServiceClass) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClass.
+func (in *ServiceClass) DeepCopy() *ServiceClass {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceClass)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ServiceClass) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceClassConfigMapRefFieldMapping) DeepCopyInto(out *ServiceClassConfigMapRefFieldMapping) {
+	*out = *in
+	in.ConfigMapName.DeepCopyInto(&out.ConfigMapName)
+	in.ConfigMapKey.DeepCopyInto(&out.ConfigMapKey)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClassConfigMapRefFieldMapping.
+func (in *ServiceClassConfigMapRefFieldMapping) DeepCopy() *ServiceClassConfigMapRefFieldMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceClassConfigMapRefFieldMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceClassIdentityItem) DeepCopyInto(out *ServiceClassIdentityItem) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClassIdentityItem.
+func (in *ServiceClassIdentityItem) DeepCopy() *ServiceClassIdentityItem {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceClassIdentityItem)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceClassList) DeepCopyInto(out *ServiceClassList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ServiceClass, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClassList.
+func (in *ServiceClassList) DeepCopy() *ServiceClassList {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceClassList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ServiceClassList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceClassObjectRefFieldMapping) DeepCopyInto(out *ServiceClassObjectRefFieldMapping) {
+	*out = *in
+	in.ObjectName.DeepCopyInto(&out.ObjectName)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClassObjectRefFieldMapping.
+func (in *ServiceClassObjectRefFieldMapping) DeepCopy() *ServiceClassObjectRefFieldMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceClassObjectRefFieldMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceClassProvisioning) DeepCopyInto(out *ServiceClassProvisioning) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClassProvisioning.
+func (in *ServiceClassProvisioning) DeepCopy() *ServiceClassProvisioning {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceClassProvisioning)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceClassResource) DeepCopyInto(out *ServiceClassResource) {
+	*out = *in
+	in.ServiceEndpointDefinitionMappings.DeepCopyInto(&out.ServiceEndpointDefinitionMappings)
+	if in.LabelSelector != nil {
+		in, out := &in.LabelSelector, &out.LabelSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Readiness != nil {
+		in, out := &in.Readiness, &out.Readiness
+		*out = new(ServiceClassResourceReadiness)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClassResource.
+func (in *ServiceClassResource) DeepCopy() *ServiceClassResource {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceClassResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceClassResourceFieldMapping) DeepCopyInto(out *ServiceClassResourceFieldMapping) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClassResourceFieldMapping.
+func (in *ServiceClassResourceFieldMapping) DeepCopy() *ServiceClassResourceFieldMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceClassResourceFieldMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceClassResourceReadiness) DeepCopyInto(out *ServiceClassResourceReadiness) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClassResourceReadiness.
+func (in *ServiceClassResourceReadiness) DeepCopy() *ServiceClassResourceReadiness {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceClassResourceReadiness)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceClassSecretRefFieldMapping) DeepCopyInto(out *ServiceClassSecretRefFieldMapping) {
+	*out = *in
+	in.SecretName.DeepCopyInto(&out.SecretName)
+	in.SecretKey.DeepCopyInto(&out.SecretKey)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClassSecretRefFieldMapping.
+func (in *ServiceClassSecretRefFieldMapping) DeepCopy() *ServiceClassSecretRefFieldMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceClassSecretRefFieldMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceClassSpec) DeepCopyInto(out *ServiceClassSpec) {
+	*out = *in
+	if in.Constraints != nil {
+		in, out := &in.Constraints, &out.Constraints
+		*out = new(EnvironmentConstraints)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(HealthCheck)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CredentialIssuer != nil {
+		in, out := &in.CredentialIssuer, &out.CredentialIssuer
+		*out = new(CredentialIssuer)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Resource.DeepCopyInto(&out.Resource)
+	if in.ServiceClassIdentity != nil {
+		in, out := &in.ServiceClassIdentity, &out.ServiceClassIdentity
+		*out = make([]ServiceClassIdentityItem, len(*in))
+		copy(*out, *in)
+	}
+	if in.Provisioning != nil {
+		in, out := &in.Provisioning, &out.Provisioning
+		*out = new(ServiceClassProvisioning)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClassSpec.
+func (in *ServiceClassSpec) DeepCopy() *ServiceClassSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceClassSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceClassStatus) DeepCopyInto(out *ServiceClassStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClassStatus.
+func (in *ServiceClassStatus) DeepCopy() *ServiceClassStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceClassStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceEndpointDefinitionItem) DeepCopyInto(out *ServiceEndpointDefinitionItem) {
+	*out = *in
+	if in.ValueFromSecret != nil {
+		in, out := &in.ValueFromSecret, &out.ValueFromSecret
+		*out = new(ServiceEndpointDefinitionSecretRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceEndpointDefinitionItem.
+func (in *ServiceEndpointDefinitionItem) DeepCopy() *ServiceEndpointDefinitionItem {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceEndpointDefinitionItem)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceEndpointDefinitionMappings) DeepCopyInto(out *ServiceEndpointDefinitionMappings) {
+	*out = *in
+	if in.ResourceFields != nil {
+		in, out := &in.ResourceFields, &out.ResourceFields
+		*out = make([]ServiceClassResourceFieldMapping, len(*in))
+		copy(*out, *in)
+	}
+	if in.SecretRefFields != nil {
+		in, out := &in.SecretRefFields, &out.SecretRefFields
+		*out = make([]ServiceClassSecretRefFieldMapping, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ConfigMapRefFields != nil {
+		in, out := &in.ConfigMapRefFields, &out.ConfigMapRefFields
+		*out = make([]ServiceClassConfigMapRefFieldMapping, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ObjectRefFields != nil {
+		in, out := &in.ObjectRefFields, &out.ObjectRefFields
+		*out = make([]ServiceClassObjectRefFieldMapping, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceEndpointDefinitionMappings.
+func (in *ServiceEndpointDefinitionMappings) DeepCopy() *ServiceEndpointDefinitionMappings {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceEndpointDefinitionMappings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceEndpointDefinitionSecretRef) DeepCopyInto(out *ServiceEndpointDefinitionSecretRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceEndpointDefinitionSecretRef.
+func (in *ServiceEndpointDefinitionSecretRef) DeepCopy() *ServiceEndpointDefinitionSecretRef {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceEndpointDefinitionSecretRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceGrant) DeepCopyInto(out *ServiceGrant) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceGrant.
+func (in *ServiceGrant) DeepCopy() *ServiceGrant {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceGrant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ServiceGrant) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceGrantList) DeepCopyInto(out *ServiceGrantList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ServiceGrant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceGrantList.
+func (in *ServiceGrantList) DeepCopy() *ServiceGrantList {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceGrantList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ServiceGrantList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceGrantReference) DeepCopyInto(out *ServiceGrantReference) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceGrantReference.
+func (in *ServiceGrantReference) DeepCopy() *ServiceGrantReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceGrantReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ServiceGrantReference) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceGrantReferenceList) DeepCopyInto(out *ServiceGrantReferenceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ServiceGrantReference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceGrantReferenceList.
+func (in *ServiceGrantReferenceList) DeepCopy() *ServiceGrantReferenceList {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceGrantReferenceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ServiceGrantReferenceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
 	}
-	if in.Envs != nil {
-		in, out := &in.Envs, &out.Envs
-		*out = make([]Environment, len(*in))
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceGrantReferenceSpec) DeepCopyInto(out *ServiceGrantReferenceSpec) {
+	*out = *in
+	if in.RegisteredServices != nil {
+		in, out := &in.RegisteredServices, &out.RegisteredServices
+		*out = make([]GrantedRegisteredService, len(*in))
 		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClaimSpec.
-func (in *ServiceClaimSpec) DeepCopy() *ServiceClaimSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceGrantReferenceSpec.
+func (in *ServiceGrantReferenceSpec) DeepCopy() *ServiceGrantReferenceSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ServiceClaimSpec)
+	out := new(ServiceGrantReferenceSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ServiceClaimStatus) DeepCopyInto(out *ServiceClaimStatus) {
+func (in *ServiceGrantSpec) DeepCopyInto(out *ServiceGrantSpec) {
 	*out = *in
-	if in.RegisteredService != nil {
-		in, out := &in.RegisteredService, &out.RegisteredService
-		*out = new(corev1.ObjectReference)
-		**out = **in
+	if in.RegisteredServices != nil {
+		in, out := &in.RegisteredServices, &out.RegisteredServices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.ServiceClassIdentity != nil {
+		in, out := &in.ServiceClassIdentity, &out.ServiceClassIdentity
+		*out = make([]ServiceClassIdentityItem, len(*in))
+		copy(*out, *in)
+	}
+	if in.Tenants != nil {
+		in, out := &in.Tenants, &out.Tenants
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClaimStatus.
-func (in *ServiceClaimStatus) DeepCopy() *ServiceClaimStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceGrantSpec.
+func (in *ServiceGrantSpec) DeepCopy() *ServiceGrantSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ServiceClaimStatus)
+	out := new(ServiceGrantSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ServiceClaimTarget) DeepCopyInto(out *ServiceClaimTarget) {
+func (in *ServiceGrantStatus) DeepCopyInto(out *ServiceGrantStatus) {
 	*out = *in
-	if in.ApplicationClusterContext != nil {
-		in, out := &in.ApplicationClusterContext, &out.ApplicationClusterContext
-		*out = new(ServiceClaimApplicationClusterContext)
-		**out = **in
+	if in.RegisteredServices != nil {
+		in, out := &in.RegisteredServices, &out.RegisteredServices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Tenants != nil {
+		in, out := &in.Tenants, &out.Tenants
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClaimTarget.
-func (in *ServiceClaimTarget) DeepCopy() *ServiceClaimTarget {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceGrantStatus.
+func (in *ServiceGrantStatus) DeepCopy() *ServiceGrantStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ServiceClaimTarget)
+	out := new(ServiceGrantStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ServiceClass) DeepCopyInto(out *ServiceClass) {
+func (in *ServiceProvisioning) DeepCopyInto(out *ServiceProvisioning) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -806,18 +2233,18 @@ func (in *ServiceClass) DeepCopyInto(out *ServiceClass) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClass.
-func (in *ServiceClass) DeepCopy() *ServiceClass {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceProvisioning.
+func (in *ServiceProvisioning) DeepCopy() *ServiceProvisioning {
 	if in == nil {
 		return nil
 	}
-	out := new(ServiceClass)
+	out := new(ServiceProvisioning)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ServiceClass) DeepCopyObject() runtime.Object {
+func (in *ServiceProvisioning) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -825,46 +2252,94 @@ func (in *ServiceClass) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ServiceClassIdentityItem) DeepCopyInto(out *ServiceClassIdentityItem) {
+func (in *ServiceProvisioningList) DeepCopyInto(out *ServiceProvisioningList) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ServiceProvisioning, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClassIdentityItem.
-func (in *ServiceClassIdentityItem) DeepCopy() *ServiceClassIdentityItem {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceProvisioningList.
+func (in *ServiceProvisioningList) DeepCopy() *ServiceProvisioningList {
 	if in == nil {
 		return nil
 	}
-	out := new(ServiceClassIdentityItem)
+	out := new(ServiceProvisioningList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ServiceProvisioningList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ServiceClassList) DeepCopyInto(out *ServiceClassList) {
+func (in *ServiceProvisioningSpec) DeepCopyInto(out *ServiceProvisioningSpec) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]ServiceClass, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	in.Manifest.DeepCopyInto(&out.Manifest)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceProvisioningSpec.
+func (in *ServiceProvisioningSpec) DeepCopy() *ServiceProvisioningSpec {
+	if in == nil {
+		return nil
 	}
+	out := new(ServiceProvisioningSpec)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClassList.
-func (in *ServiceClassList) DeepCopy() *ServiceClassList {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceProvisioningStatus) DeepCopyInto(out *ServiceProvisioningStatus) {
+	*out = *in
+	if in.Resource != nil {
+		in, out := &in.Resource, &out.Resource
+		*out = new(corev1.ObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceProvisioningStatus.
+func (in *ServiceProvisioningStatus) DeepCopy() *ServiceProvisioningStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ServiceClassList)
+	out := new(ServiceProvisioningStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Tenant) DeepCopyInto(out *Tenant) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Tenant.
+func (in *Tenant) DeepCopy() *Tenant {
+	if in == nil {
+		return nil
+	}
+	out := new(Tenant)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ServiceClassList) DeepCopyObject() runtime.Object {
+func (in *Tenant) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -872,87 +2347,108 @@ func (in *ServiceClassList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ServiceClassResource) DeepCopyInto(out *ServiceClassResource) {
+func (in *TenantClaimQuota) DeepCopyInto(out *TenantClaimQuota) {
 	*out = *in
-	in.ServiceEndpointDefinitionMappings.DeepCopyInto(&out.ServiceEndpointDefinitionMappings)
+	in.ClaimQuotaSpec.DeepCopyInto(&out.ClaimQuotaSpec)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClassResource.
-func (in *ServiceClassResource) DeepCopy() *ServiceClassResource {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantClaimQuota.
+func (in *TenantClaimQuota) DeepCopy() *TenantClaimQuota {
 	if in == nil {
 		return nil
 	}
-	out := new(ServiceClassResource)
+	out := new(TenantClaimQuota)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ServiceClassResourceFieldMapping) DeepCopyInto(out *ServiceClassResourceFieldMapping) {
+func (in *TenantClusterEnvironment) DeepCopyInto(out *TenantClusterEnvironment) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClassResourceFieldMapping.
-func (in *ServiceClassResourceFieldMapping) DeepCopy() *ServiceClassResourceFieldMapping {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantClusterEnvironment.
+func (in *TenantClusterEnvironment) DeepCopy() *TenantClusterEnvironment {
 	if in == nil {
 		return nil
 	}
-	out := new(ServiceClassResourceFieldMapping)
+	out := new(TenantClusterEnvironment)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ServiceClassSecretRefFieldMapping) DeepCopyInto(out *ServiceClassSecretRefFieldMapping) {
+func (in *TenantList) DeepCopyInto(out *TenantList) {
 	*out = *in
-	in.SecretName.DeepCopyInto(&out.SecretName)
-	in.SecretKey.DeepCopyInto(&out.SecretKey)
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Tenant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClassSecretRefFieldMapping.
-func (in *ServiceClassSecretRefFieldMapping) DeepCopy() *ServiceClassSecretRefFieldMapping {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantList.
+func (in *TenantList) DeepCopy() *TenantList {
 	if in == nil {
 		return nil
 	}
-	out := new(ServiceClassSecretRefFieldMapping)
+	out := new(TenantList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TenantList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ServiceClassSpec) DeepCopyInto(out *ServiceClassSpec) {
+func (in *TenantSpec) DeepCopyInto(out *TenantSpec) {
 	*out = *in
-	if in.Constraints != nil {
-		in, out := &in.Constraints, &out.Constraints
-		*out = new(EnvironmentConstraints)
-		(*in).DeepCopyInto(*out)
+	if in.Environments != nil {
+		in, out := &in.Environments, &out.Environments
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.HealthCheck != nil {
-		in, out := &in.HealthCheck, &out.HealthCheck
-		*out = new(HealthCheck)
-		(*in).DeepCopyInto(*out)
+	if in.ClaimQuotas != nil {
+		in, out := &in.ClaimQuotas, &out.ClaimQuotas
+		*out = make([]TenantClaimQuota, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	in.Resource.DeepCopyInto(&out.Resource)
-	if in.ServiceClassIdentity != nil {
-		in, out := &in.ServiceClassIdentity, &out.ServiceClassIdentity
-		*out = make([]ServiceClassIdentityItem, len(*in))
+	if in.Admins != nil {
+		in, out := &in.Admins, &out.Admins
+		*out = make([]rbacv1.Subject, len(*in))
 		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClassSpec.
-func (in *ServiceClassSpec) DeepCopy() *ServiceClassSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantSpec.
+func (in *TenantSpec) DeepCopy() *TenantSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ServiceClassSpec)
+	out := new(TenantSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ServiceClassStatus) DeepCopyInto(out *ServiceClassStatus) {
+func (in *TenantStatus) DeepCopyInto(out *TenantStatus) {
 	*out = *in
+	if in.ClusterEnvironments != nil {
+		in, out := &in.ClusterEnvironments, &out.ClusterEnvironments
+		*out = make([]TenantClusterEnvironment, len(*in))
+		copy(*out, *in)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -962,74 +2458,127 @@ func (in *ServiceClassStatus) DeepCopyInto(out *ServiceClassStatus) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClassStatus.
-func (in *ServiceClassStatus) DeepCopy() *ServiceClassStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantStatus.
+func (in *TenantStatus) DeepCopy() *TenantStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ServiceClassStatus)
+	out := new(TenantStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ServiceEndpointDefinitionItem) DeepCopyInto(out *ServiceEndpointDefinitionItem) {
+func (in *WorkloadResourceMapping) DeepCopyInto(out *WorkloadResourceMapping) {
 	*out = *in
-	if in.ValueFromSecret != nil {
-		in, out := &in.ValueFromSecret, &out.ValueFromSecret
-		*out = new(ServiceEndpointDefinitionSecretRef)
-		**out = **in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadResourceMapping.
+func (in *WorkloadResourceMapping) DeepCopy() *WorkloadResourceMapping {
+	if in == nil {
+		return nil
 	}
+	out := new(WorkloadResourceMapping)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceEndpointDefinitionItem.
-func (in *ServiceEndpointDefinitionItem) DeepCopy() *ServiceEndpointDefinitionItem {
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *WorkloadResourceMapping) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadResourceMappingContainer) DeepCopyInto(out *WorkloadResourceMappingContainer) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadResourceMappingContainer.
+func (in *WorkloadResourceMappingContainer) DeepCopy() *WorkloadResourceMappingContainer {
 	if in == nil {
 		return nil
 	}
-	out := new(ServiceEndpointDefinitionItem)
+	out := new(WorkloadResourceMappingContainer)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ServiceEndpointDefinitionMappings) DeepCopyInto(out *ServiceEndpointDefinitionMappings) {
+func (in *WorkloadResourceMappingList) DeepCopyInto(out *WorkloadResourceMappingList) {
 	*out = *in
-	if in.ResourceFields != nil {
-		in, out := &in.ResourceFields, &out.ResourceFields
-		*out = make([]ServiceClassResourceFieldMapping, len(*in))
-		copy(*out, *in)
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]WorkloadResourceMapping, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.SecretRefFields != nil {
-		in, out := &in.SecretRefFields, &out.SecretRefFields
-		*out = make([]ServiceClassSecretRefFieldMapping, len(*in))
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadResourceMappingList.
+func (in *WorkloadResourceMappingList) DeepCopy() *WorkloadResourceMappingList {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadResourceMappingList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *WorkloadResourceMappingList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadResourceMappingSpec) DeepCopyInto(out *WorkloadResourceMappingSpec) {
+	*out = *in
+	if in.Versions != nil {
+		in, out := &in.Versions, &out.Versions
+		*out = make([]WorkloadResourceMappingTemplate, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceEndpointDefinitionMappings.
-func (in *ServiceEndpointDefinitionMappings) DeepCopy() *ServiceEndpointDefinitionMappings {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadResourceMappingSpec.
+func (in *WorkloadResourceMappingSpec) DeepCopy() *WorkloadResourceMappingSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ServiceEndpointDefinitionMappings)
+	out := new(WorkloadResourceMappingSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ServiceEndpointDefinitionSecretRef) DeepCopyInto(out *ServiceEndpointDefinitionSecretRef) {
+func (in *WorkloadResourceMappingTemplate) DeepCopyInto(out *WorkloadResourceMappingTemplate) {
 	*out = *in
+	if in.Containers != nil {
+		in, out := &in.Containers, &out.Containers
+		*out = make([]WorkloadResourceMappingContainer, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceEndpointDefinitionSecretRef.
-func (in *ServiceEndpointDefinitionSecretRef) DeepCopy() *ServiceEndpointDefinitionSecretRef {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadResourceMappingTemplate.
+func (in *WorkloadResourceMappingTemplate) DeepCopy() *WorkloadResourceMappingTemplate {
 	if in == nil {
 		return nil
 	}
-	out := new(ServiceEndpointDefinitionSecretRef)
+	out := new(WorkloadResourceMappingTemplate)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -39,6 +39,7 @@ import (
 
 const (
 	EnvWatchNamespace          = "WATCH_NAMESPACE"
+	EnvEnableWebhooks          = "ENABLE_WEBHOOKS"
 	EnvSynchronizationStrategy = "SYNCHRONIZATION_STRATEGY"
 )
 
@@ -127,6 +128,14 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "ServiceCatalog")
 		os.Exit(1)
 	}
+	// serving webhooks requires certificates and a webhook configuration
+	// in the worker cluster, so the webhook is opt-in
+	if os.Getenv(EnvEnableWebhooks) == "true" {
+		if err = (&primazaiov1alpha1.ServiceClaim{}).SetupAgentWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "ServiceClaim")
+			os.Exit(1)
+		}
+	}
 	agentApplicationController := controllers.NewAgentApplicationReconciler(mgr)
 	if err = agentApplicationController.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Agent Service")
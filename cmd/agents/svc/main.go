@@ -118,6 +118,12 @@ func main() {
 		os.Exit(1)
 	}
 
+	serviceProvisioningController := svc.NewServiceProvisioningReconciler(mgr)
+	if err = serviceProvisioningController.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ServiceProvisioning")
+		os.Exit(1)
+	}
+
 	agentServiceController := svc.NewAgentServiceReconciler(mgr)
 	if err = agentServiceController.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Agent Service")
@@ -56,6 +56,7 @@ const (
 	EnvSvcAgentManifest            = "AGENT_SVC_MANIFEST"
 	EnvAppAgentConfigManifest      = "AGENT_APP_CONFIG_MANIFEST"
 	EnvSvcAgentConfigManifest      = "AGENT_SVC_CONFIG_MANIFEST"
+	EnvManageTenants               = "MANAGE_TENANTS"
 )
 
 var (
@@ -138,6 +139,10 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "ServiceClaim")
 		os.Exit(1)
 	}
+	if err := mgr.AddMetricsExtraHandler(controllers.ServiceClaimExplanationPath, serviceClaimController.ExplanationHandler()); err != nil {
+		setupLog.Error(err, "unable to set up ServiceClaim explanation endpoint")
+		os.Exit(1)
+	}
 	if err = (&controllers.ServiceClassReconciler{
 		Client: mgr.GetClient(),
 		Scheme: mgr.GetScheme(),
@@ -149,6 +154,26 @@ func main() {
 		setupLog.Error(err, "unable to create webhook", "webhook", "ServiceClass")
 		os.Exit(1)
 	}
+	if err = (&primazaiov1alpha1.ServiceClaim{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "ServiceClaim")
+		os.Exit(1)
+	}
+	if err = (&primazaiov1alpha1.ClusterEnvironment{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "ClusterEnvironment")
+		os.Exit(1)
+	}
+	if err = (&primazaiov1alpha1.RegisteredService{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "RegisteredService")
+		os.Exit(1)
+	}
+	if err = (&primazaiov1alpha1.Environment{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "Environment")
+		os.Exit(1)
+	}
+	if err = (&primazaiov1alpha1.ServiceClaimApproval{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "ServiceClaimApproval")
+		os.Exit(1)
+	}
 	if err = (&controllers.RegisteredServiceReconciler{
 		Client: mgr.GetClient(),
 		Scheme: mgr.GetScheme(),
@@ -157,13 +182,52 @@ func main() {
 		os.Exit(1)
 	}
 
-	if err = (&controllers.ServiceCatalogReconciler{
+	if err = (&controllers.ServiceClaimTemplateReconciler{
 		Client: mgr.GetClient(),
 		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ServiceClaimTemplate")
+		os.Exit(1)
+	}
+	if err = (&controllers.ClaimQuotaReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ClaimQuota")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.ServiceGrantReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ServiceGrant")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.ServiceCatalogReconciler{
+		Client:    mgr.GetClient(),
+		Scheme:    mgr.GetScheme(),
+		APIReader: mgr.GetAPIReader(),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ServiceCatalog")
 		os.Exit(1)
 	}
+
+	// Tenants are managed by a single control plane, that is granted the
+	// cluster-wide permissions to provision them
+	if cfg.ManageTenants {
+		if err = (&controllers.TenantReconciler{
+			Client:                mgr.GetClient(),
+			Scheme:                mgr.GetScheme(),
+			APIReader:             mgr.GetAPIReader(),
+			ControlPlaneNamespace: cfg.WatchNamespace,
+			ClusterEnvironment:    cerConfig,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "Tenant")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -234,6 +298,7 @@ type config struct {
 	SvcAgentManifest       string
 	AppAgentConfigManifest string
 	SvcAgentConfigManifest string
+	ManageTenants          bool
 }
 
 func getConfig(log logr.Logger) (*config, error) {
@@ -283,6 +348,7 @@ func getConfig(log logr.Logger) (*config, error) {
 		SvcAgentManifest:       ss,
 		AppAgentConfigManifest: acm,
 		SvcAgentConfigManifest: scm,
+		ManageTenants:          os.Getenv(EnvManageTenants) == "true",
 	}, nil
 }
 
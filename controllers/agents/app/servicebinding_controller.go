@@ -43,6 +43,8 @@ const (
 	conditionGetSecretFailureReason = "ErrorFetchSecret"
 	conditionBindingSuccessful      = "Successful"
 	conditionBindingFailure         = "Binding Failure"
+	conditionSpecCompliant          = "Compliant"
+	conditionMissingTypeEntry       = "MissingTypeEntry"
 )
 
 // ServiceBindingReconciler reconciles a ServiceBinding object
@@ -211,6 +213,8 @@ func (r *ServiceBindingReconciler) PrepareBinding(
 ) error {
 	l := log.FromContext(ctx)
 
+	checkServiceBindingSpecEntries(serviceBinding, psSecret)
+
 	f := false
 	p := int32(0444)
 	volumeName := serviceBinding.Name
@@ -251,13 +255,13 @@ func (r *ServiceBindingReconciler) prepareContainerWithMounts(
 	l := log.FromContext(ctx)
 	l.Info("Prepare application mounting")
 
-	containersPaths := [][]string{}
-	volumesPath := []string{"spec", "template", "spec", "volumes"}
+	mapping, err := r.getWorkloadMapping(ctx, application)
+	if err != nil {
+		return err
+	}
+	containersPaths := mapping.containers
+	volumesPath := mapping.volumes
 
-	containersPaths = append(containersPaths,
-		[]string{"spec", "template", "spec", "containers"},
-		[]string{"spec", "template", "spec", "initContainers"},
-	)
 	l.Info("referencing the volume in an unstructured object")
 	volumes, found, err := unstructured.NestedSlice(application.Object, volumesPath...)
 	if err != nil {
@@ -313,6 +317,13 @@ func (r *ServiceBindingReconciler) prepareContainerWithMounts(
 		l.Info("application object after setting the updated containers", "Application", application)
 	}
 
+	if ok, err := setSecretHashAnnotation(&application, *mapping, *sb, psSecret); err != nil {
+		l.Error(err, "unable to set the secret hash annotation in the application's pod template")
+		return err
+	} else if ok {
+		l.Info("secret hash annotation set in the application's pod template")
+	}
+
 	l.Info("updating the application with updated volumes and volumeMounts")
 	if err := r.Update(ctx, &application); err != nil {
 		l.Error(err, "unable to update the application", "application", application)
@@ -477,9 +488,15 @@ func (r *ServiceBindingReconciler) getApplication(ctx context.Context,
 			},
 		}
 
+		selector, err := metav1.LabelSelectorAsSelector(sb.Spec.Application.Selector)
+		if err != nil {
+			l.Error(err, "unable to parse Application label selector", "selector", sb.Spec.Application.Selector)
+			return []unstructured.Unstructured{}, err
+		}
+
 		l.Info("retrieving the application objects", "Application", applicationList)
 		opts := &client.ListOptions{
-			LabelSelector: labels.Set(sb.Spec.Application.Selector.MatchLabels).AsSelector(),
+			LabelSelector: selector,
 			Namespace:     sb.Namespace,
 		}
 
@@ -628,13 +645,13 @@ func (r *ServiceBindingReconciler) removeVolumeMountAndEnvironment(ctx context.C
 	l := log.FromContext(ctx)
 	l.Info("Prepare removing application mounting")
 
-	containersPaths := [][]string{}
-	volumesPath := []string{"spec", "template", "spec", "volumes"}
+	mapping, err := r.getWorkloadMapping(ctx, application)
+	if err != nil {
+		return err
+	}
+	containersPaths := mapping.containers
+	volumesPath := mapping.volumes
 
-	containersPaths = append(containersPaths,
-		[]string{"spec", "template", "spec", "containers"},
-		[]string{"spec", "template", "spec", "initContainers"},
-	)
 	l.Info("referencing the volume in an unstructured object")
 	volumes, found, err := unstructured.NestedSlice(application.Object, volumesPath...)
 	if err != nil {
@@ -682,6 +699,11 @@ func (r *ServiceBindingReconciler) removeVolumeMountAndEnvironment(ctx context.C
 		}
 	}
 
+	if err := removeSecretHashAnnotation(&application, *mapping, sb); err != nil {
+		l.Error(err, "unable to remove the secret hash annotation from the application's pod template")
+		return err
+	}
+
 	l.Info("updating the application with updated volumes and volumeMounts")
 	if err := r.Update(ctx, &application); err != nil {
 		l.Error(err, "unable to update the application", "application", application)
@@ -709,18 +731,20 @@ func (r *ServiceBindingReconciler) unbindApplications(ctx context.Context,
 	return nil
 }
 
+// verifyApplicationSatisfiesServiceBindingSpec checks whether the given
+// workload is selected by the ServiceBinding's application reference.
+// Selectors are evaluated with Kubernetes label selector semantics, so
+// all MatchLabels and MatchExpressions requirements have to be satisfied.
 func verifyApplicationSatisfiesServiceBindingSpec(obj *unstructured.Unstructured, sb primazaiov1alpha1.ServiceBinding) bool {
 	switch {
-	case sb.Spec.Application.Name == obj.GetName():
-		return true
+	case sb.Spec.Application.Name != "":
+		return sb.Spec.Application.Name == obj.GetName()
 	case sb.Spec.Application.Selector != nil:
-		// TODO: handle sb.Spec.Application.Selector.ByLabels.MatchExpressions
-		for label, value := range obj.GetLabels() {
-			if val, ok := sb.Spec.Application.Selector.MatchLabels[label]; ok && value == val {
-				return true
-			}
+		selector, err := metav1.LabelSelectorAsSelector(sb.Spec.Application.Selector)
+		if err != nil {
+			return false
 		}
-		return false
+		return selector.Matches(labels.Set(obj.GetLabels()))
 	default:
 		return false
 	}
@@ -132,23 +132,24 @@ func (r *ServiceBindingReconciler) prepareAddFunc(ctx context.Context, synced *a
 
 		l := log.FromContext(ctx).WithValues("service binding", serviceBinding.Name)
 
+		var sb primazaiov1alpha1.ServiceBinding
+		k := types.NamespacedName{Namespace: serviceBinding.Namespace, Name: serviceBinding.Name}
+		if err := r.Get(ctx, k, &sb, &client.GetOptions{}); err != nil {
+			l.Error(err, "Informer AddEventHandler: retrieving ServiceBinding", "service binding", k)
+			return
+		}
+
 		applicationResource := obj.(*unstructured.Unstructured)
-		if !verifyApplicationSatisfiesServiceBindingSpec(applicationResource, serviceBinding) {
+		if !verifyApplicationSatisfiesServiceBindingSpec(applicationResource, sb) {
 			return
 		}
 		l.Info("application resource", "application", applicationResource.GetName())
 
-		psSecret, err := r.GetSecret(ctx, serviceBinding, *applicationResource)
+		psSecret, err := r.GetSecret(ctx, sb, *applicationResource)
 		if err != nil {
 			l.Error(err, "Informer AddEventHandler: Error retrieving secret")
 			return
 		}
-		var sb primazaiov1alpha1.ServiceBinding
-		k := types.NamespacedName{Namespace: serviceBinding.Namespace, Name: serviceBinding.Name}
-		if err := r.Get(ctx, k, &sb, &client.GetOptions{}); err != nil {
-			l.Error(err, "Informer AddEventHandler: retrieving ServiceBinding", "service binding", k)
-			return
-		}
 
 		if err := r.PrepareBinding(ctx, &sb, psSecret, *applicationResource); err != nil {
 			l.Error(err, "Informer AddEventHandler: Error preparing binding")
@@ -166,30 +167,64 @@ func (r *ServiceBindingReconciler) prepareUpdateFunc(ctx context.Context, synced
 		l := log.FromContext(ctx).WithValues("service binding", serviceBinding.Name)
 		l.Info("watched resource updated")
 
+		var sb primazaiov1alpha1.ServiceBinding
+		k := types.NamespacedName{Namespace: serviceBinding.Namespace, Name: serviceBinding.Name}
+		if err := r.Get(ctx, k, &sb, &client.GetOptions{}); err != nil {
+			l.Error(err, "Informer UpdateEventHandler: retrieving ServiceBinding", "service binding", k)
+			return
+		}
+
 		applicationResource := future.(*unstructured.Unstructured)
 
 		l.Info("application resource", "application", applicationResource.GetName())
-		if !verifyApplicationSatisfiesServiceBindingSpec(applicationResource, serviceBinding) {
+		if !verifyApplicationSatisfiesServiceBindingSpec(applicationResource, sb) {
+			// the workload may have been relabeled and not be selected anymore
+			if err := r.unbindUnselectedApplication(ctx, sb, *applicationResource); err != nil {
+				l.Error(err, "Informer UpdateEventHandler: Error unbinding application no more selected",
+					"application", applicationResource.GetName())
+			}
 			return
 		}
-		psSecret, err := r.GetSecret(ctx, serviceBinding, *applicationResource)
+		psSecret, err := r.GetSecret(ctx, sb, *applicationResource)
 		if err != nil {
-			l.Error(err, "Informer AddEventHandler: Error retrieving secret")
+			l.Error(err, "Informer UpdateEventHandler: Error retrieving secret")
 			return
 		}
 
-		var sb primazaiov1alpha1.ServiceBinding
-		k := types.NamespacedName{Namespace: serviceBinding.Namespace, Name: serviceBinding.Name}
-		if err := r.Get(ctx, k, &sb, &client.GetOptions{}); err != nil {
-			l.Error(err, "Informer AddEventHandler: retrieving ServiceBinding", "service binding", k)
+		if err := r.PrepareBinding(ctx, &sb, psSecret, *applicationResource); err != nil {
+			l.Error(err, "Informer UpdateEventHandler: Error preparing binding")
 			return
 		}
+	}
+}
 
-		if err := r.PrepareBinding(ctx, &sb, psSecret, *applicationResource); err != nil {
-			l.Error(err, "Informer AddEventHandler: Error preparing binding")
-			return
+// unbindUnselectedApplication removes the binding from a workload that is
+// listed among the ServiceBinding's connections but is not selected anymore
+// by the ServiceBinding's application reference
+func (r *ServiceBindingReconciler) unbindUnselectedApplication(
+	ctx context.Context,
+	sb primazaiov1alpha1.ServiceBinding,
+	application unstructured.Unstructured,
+) error {
+	if !slices.ContainsFunc(
+		sb.Status.Connections,
+		func(w primazaiov1alpha1.BoundWorkload) bool { return w.Name == application.GetName() }) {
+		return nil
+	}
+
+	if err := r.unbindApplications(ctx, sb, application); err != nil {
+		return err
+	}
+
+	cc := []primazaiov1alpha1.BoundWorkload{}
+	for _, b := range sb.Status.Connections {
+		if b.Name != application.GetName() {
+			cc = append(cc, b)
 		}
 	}
+	sb.Status.Connections = cc
+
+	return r.Status().Update(ctx, &sb)
 }
 
 func (r *ServiceBindingReconciler) prepareDeleteFunc(ctx context.Context, synced *atomic.Bool, serviceBinding primazaiov1alpha1.ServiceBinding) func(obj interface{}) {
@@ -0,0 +1,179 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	primazaiov1alpha1 "github.com/primaza/primaza/api/v1alpha1"
+	"github.com/primaza/primaza/pkg/primaza/constants"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// workloadMapping locates the Container-like structures and the volumes
+// within a workload resource
+type workloadMapping struct {
+	containers [][]string
+	volumes    []string
+}
+
+// defaultWorkloadMapping is the mapping for PodSpecable workload resources
+var defaultWorkloadMapping = workloadMapping{
+	containers: [][]string{
+		{"spec", "template", "spec", "containers"},
+		{"spec", "template", "spec", "initContainers"},
+	},
+	volumes: []string{"spec", "template", "spec", "volumes"},
+}
+
+// builtinWorkloadMappings are the mappings for well-known workload resources
+// that are not PodSpecable. They can be overridden with a WorkloadResourceMapping
+var builtinWorkloadMappings = map[schema.GroupKind]workloadMapping{
+	{Group: "batch", Kind: "CronJob"}: {
+		containers: [][]string{
+			{"spec", "jobTemplate", "spec", "template", "spec", "containers"},
+			{"spec", "jobTemplate", "spec", "template", "spec", "initContainers"},
+		},
+		volumes: []string{"spec", "jobTemplate", "spec", "template", "spec", "volumes"},
+	},
+}
+
+// getWorkloadMapping returns the mapping to use for binding the given
+// application. WorkloadResourceMappings defined in the application's
+// namespace take precedence over built-in mappings
+func (r *ServiceBindingReconciler) getWorkloadMapping(ctx context.Context, application unstructured.Unstructured) (*workloadMapping, error) {
+	l := log.FromContext(ctx)
+
+	gvk := application.GroupVersionKind()
+	rm, err := r.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		l.Error(err, "error on creating mapping", "gvk", gvk)
+		return nil, err
+	}
+
+	wrm := primazaiov1alpha1.WorkloadResourceMapping{}
+	k := client.ObjectKey{Namespace: application.GetNamespace(), Name: workloadResourceMappingName(rm.Resource)}
+	if err := r.Get(ctx, k, &wrm); err != nil {
+		if !apierrors.IsNotFound(err) {
+			l.Error(err, "unable to retrieve WorkloadResourceMapping", "workload resource mapping", k)
+			return nil, err
+		}
+
+		if m, ok := builtinWorkloadMappings[gvk.GroupKind()]; ok {
+			return &m, nil
+		}
+		return &defaultWorkloadMapping, nil
+	}
+
+	l.Info("using workload resource mapping", "workload resource mapping", k)
+	return workloadMappingForVersion(wrm, gvk.Version)
+}
+
+// workloadResourceMappingName returns the name a WorkloadResourceMapping
+// for the given resource is expected to have, i.e. `<plural>.<group>`
+func workloadResourceMappingName(resource schema.GroupVersionResource) string {
+	if resource.Group == "" {
+		return resource.Resource
+	}
+	return resource.Resource + "." + resource.Group
+}
+
+// workloadMappingForVersion builds the mapping for the given version from the
+// WorkloadResourceMapping. An explicitly mapped version takes precedence over
+// the `*` wildcard
+func workloadMappingForVersion(wrm primazaiov1alpha1.WorkloadResourceMapping, version string) (*workloadMapping, error) {
+	var template *primazaiov1alpha1.WorkloadResourceMappingTemplate
+	for i, t := range wrm.Spec.Versions {
+		if t.Version == version {
+			template = &wrm.Spec.Versions[i]
+			break
+		}
+		if t.Version == "*" {
+			template = &wrm.Spec.Versions[i]
+		}
+	}
+	if template == nil {
+		return nil, fmt.Errorf("workload resource mapping %s has no mapping for version %s", wrm.Name, version)
+	}
+
+	m := workloadMapping{
+		containers: defaultWorkloadMapping.containers,
+		volumes:    defaultWorkloadMapping.volumes,
+	}
+	if len(template.Containers) != 0 {
+		m.containers = [][]string{}
+		for _, c := range template.Containers {
+			p, err := parseWorkloadMappingPath(c.Path)
+			if err != nil {
+				return nil, err
+			}
+			m.containers = append(m.containers, p)
+		}
+	}
+	if template.Volumes != "" {
+		p, err := parseWorkloadMappingPath(template.Volumes)
+		if err != nil {
+			return nil, err
+		}
+		m.volumes = p
+	}
+	return &m, nil
+}
+
+// parseWorkloadMappingPath converts a fixed-field JSONPath, optionally
+// terminated by `[*]`, into the list of fields to traverse
+func parseWorkloadMappingPath(path string) ([]string, error) {
+	p := strings.TrimSuffix(strings.TrimSpace(path), "[*]")
+	if !strings.HasPrefix(p, ".") || strings.ContainsAny(p, "[]*") {
+		return nil, fmt.Errorf("unsupported workload resource mapping path %q", path)
+	}
+
+	ff := strings.Split(strings.TrimPrefix(p, "."), ".")
+	for _, f := range ff {
+		if f == "" {
+			return nil, fmt.Errorf("unsupported workload resource mapping path %q", path)
+		}
+	}
+	return ff, nil
+}
+
+// checkServiceBindingSpecEntries reports in the ServiceBinding's
+// SpecCompliant condition whether the secret contains the `type` entry
+// required by the Service Binding specification. The entries are projected
+// into the secret by the control plane: the agent only reads the secret.
+func checkServiceBindingSpecEntries(sb *primazaiov1alpha1.ServiceBinding, secret *v1.Secret) {
+	c := metav1.Condition{
+		Type:   primazaiov1alpha1.ServiceBindingSpecCompliantCondition,
+		Status: metav1.ConditionTrue,
+		Reason: conditionSpecCompliant,
+	}
+	if len(secret.Data[constants.ServiceBindingTypeKey]) == 0 {
+		c.Status = metav1.ConditionFalse
+		c.Reason = conditionMissingTypeEntry
+		c.Message = fmt.Sprintf("secret %s has no %s entry", secret.Name, constants.ServiceBindingTypeKey)
+	}
+	meta.SetStatusCondition(&sb.Status.Conditions, c)
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"reflect"
+	"testing"
+
+	primazaiov1alpha1 "github.com/primaza/primaza/api/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_WorkloadMappingForVersion(t *testing.T) {
+	type test struct {
+		name    string
+		spec    primazaiov1alpha1.WorkloadResourceMappingSpec
+		version string
+		want    *workloadMapping
+	}
+
+	tt := []test{
+		{
+			name: "defaults",
+			spec: primazaiov1alpha1.WorkloadResourceMappingSpec{
+				Versions: []primazaiov1alpha1.WorkloadResourceMappingTemplate{{Version: "*"}},
+			},
+			version: "v1",
+			want:    &defaultWorkloadMapping,
+		},
+		{
+			name: "explicit version wins over wildcard",
+			spec: primazaiov1alpha1.WorkloadResourceMappingSpec{
+				Versions: []primazaiov1alpha1.WorkloadResourceMappingTemplate{
+					{Version: "v1", Volumes: ".spec.volumes", Containers: []primazaiov1alpha1.WorkloadResourceMappingContainer{{Path: ".spec.containers[*]"}}},
+					{Version: "*", Volumes: ".spec.other.volumes"},
+				},
+			},
+			version: "v1",
+			want: &workloadMapping{
+				containers: [][]string{{"spec", "containers"}},
+				volumes:    []string{"spec", "volumes"},
+			},
+		},
+		{
+			name: "unmapped version",
+			spec: primazaiov1alpha1.WorkloadResourceMappingSpec{
+				Versions: []primazaiov1alpha1.WorkloadResourceMappingTemplate{{Version: "v1"}},
+			},
+			version: "v2",
+			want:    nil,
+		},
+		{
+			name: "unsupported path",
+			spec: primazaiov1alpha1.WorkloadResourceMappingSpec{
+				Versions: []primazaiov1alpha1.WorkloadResourceMappingTemplate{{Version: "*", Volumes: ".spec.templates[0].volumes"}},
+			},
+			version: "v1",
+			want:    nil,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			wrm := primazaiov1alpha1.WorkloadResourceMapping{Spec: tc.spec}
+			got, err := workloadMappingForVersion(wrm, tc.version)
+			if tc.want == nil {
+				if err == nil {
+					t.Errorf("expected error, got mapping %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func Test_CheckServiceBindingSpecEntries(t *testing.T) {
+	type test struct {
+		name          string
+		data          map[string][]byte
+		wantCompliant metav1.ConditionStatus
+	}
+
+	tt := []test{
+		{
+			name:          "type entry",
+			data:          map[string][]byte{"type": []byte("postgres"), "provider": []byte("aws")},
+			wantCompliant: metav1.ConditionTrue,
+		},
+		{
+			name:          "missing type",
+			data:          map[string][]byte{"host": []byte("db")},
+			wantCompliant: metav1.ConditionFalse,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			sb := primazaiov1alpha1.ServiceBinding{Spec: primazaiov1alpha1.ServiceBindingSpec{Type: "postgres"}}
+			secret := v1.Secret{Data: tc.data}
+			checkServiceBindingSpecEntries(&sb, &secret)
+			if !reflect.DeepEqual(secret.Data, tc.data) {
+				t.Errorf("expected secret data to be left untouched, got %v", secret.Data)
+			}
+			c := meta.FindStatusCondition(sb.Status.Conditions, primazaiov1alpha1.ServiceBindingSpecCompliantCondition)
+			if c == nil || c.Status != tc.wantCompliant {
+				t.Errorf("expected condition status %v, got %v", tc.wantCompliant, c)
+			}
+		})
+	}
+}
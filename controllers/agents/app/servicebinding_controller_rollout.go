@@ -0,0 +1,108 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"sort"
+
+	primazaiov1alpha1 "github.com/primaza/primaza/api/v1alpha1"
+	"github.com/primaza/primaza/pkg/primaza/constants"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// podTemplateAnnotationsPath returns the path to the annotations of the pod
+// template holding the mapped volumes. It returns false if the volumes are
+// not part of a pod template's spec
+func (m workloadMapping) podTemplateAnnotationsPath() ([]string, bool) {
+	n := len(m.volumes)
+	if n < 2 || m.volumes[n-2] != "spec" || m.volumes[n-1] != "volumes" {
+		return nil, false
+	}
+	return append(slices.Clone(m.volumes[:n-2]), "metadata", "annotations"), true
+}
+
+// secretDataHash returns a hash of the secret's data that does not depend on
+// the order of the entries
+func secretDataHash(secret *v1.Secret) string {
+	kk := make([]string, 0, len(secret.Data))
+	for k := range secret.Data {
+		kk = append(kk, k)
+	}
+	sort.Strings(kk)
+
+	h := sha256.New()
+	for _, k := range kk {
+		h.Write([]byte(k))
+		h.Write([]byte{0})
+		h.Write(secret.Data[k])
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// setSecretHashAnnotation sets the hash of the secret's data in the
+// application's pod template, so that the application is rolled out when
+// the secret's data changes. Nothing is done if the ServiceBinding does not
+// enable RolloutOnSecretChange or if the pod template can not be located
+func setSecretHashAnnotation(
+	application *unstructured.Unstructured,
+	mapping workloadMapping,
+	sb primazaiov1alpha1.ServiceBinding,
+	secret *v1.Secret,
+) (bool, error) {
+	p, ok := mapping.podTemplateAnnotationsPath()
+	if !sb.Spec.RolloutOnSecretChange || !ok {
+		return false, nil
+	}
+
+	aa, _, err := unstructured.NestedStringMap(application.Object, p...)
+	if err != nil {
+		return false, err
+	}
+	if aa == nil {
+		aa = map[string]string{}
+	}
+	aa[constants.SecretHashAnnotationPrefix+sb.Name] = secretDataHash(secret)
+	return true, unstructured.SetNestedStringMap(application.Object, aa, p...)
+}
+
+// removeSecretHashAnnotation removes the annotation set by
+// setSecretHashAnnotation from the application's pod template, if any
+func removeSecretHashAnnotation(
+	application *unstructured.Unstructured,
+	mapping workloadMapping,
+	sb primazaiov1alpha1.ServiceBinding,
+) error {
+	p, ok := mapping.podTemplateAnnotationsPath()
+	if !ok {
+		return nil
+	}
+
+	aa, found, err := unstructured.NestedStringMap(application.Object, p...)
+	if err != nil || !found {
+		return err
+	}
+	if _, ok := aa[constants.SecretHashAnnotationPrefix+sb.Name]; !ok {
+		return nil
+	}
+	delete(aa, constants.SecretHashAnnotationPrefix+sb.Name)
+	return unstructured.SetNestedStringMap(application.Object, aa, p...)
+}
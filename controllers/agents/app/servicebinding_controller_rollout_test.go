@@ -0,0 +1,91 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"reflect"
+	"testing"
+
+	primazaiov1alpha1 "github.com/primaza/primaza/api/v1alpha1"
+	"github.com/primaza/primaza/pkg/primaza/constants"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func Test_SecretHashAnnotation(t *testing.T) {
+	annotationsPath := []string{"spec", "template", "metadata", "annotations"}
+	annotation := constants.SecretHashAnnotationPrefix + "sb"
+	newBinding := func(rollout bool) primazaiov1alpha1.ServiceBinding {
+		return primazaiov1alpha1.ServiceBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "sb"},
+			Spec:       primazaiov1alpha1.ServiceBindingSpec{RolloutOnSecretChange: rollout},
+		}
+	}
+	getHash := func(application unstructured.Unstructured) string {
+		aa, _, _ := unstructured.NestedStringMap(application.Object, annotationsPath...)
+		return aa[annotation]
+	}
+
+	application := unstructured.Unstructured{Object: map[string]interface{}{}}
+	secret := &v1.Secret{Data: map[string][]byte{"username": []byte("user"), "password": []byte("old")}}
+
+	if ok, err := setSecretHashAnnotation(&application, defaultWorkloadMapping, newBinding(false), secret); err != nil || ok {
+		t.Fatalf("expected no annotation to be set when rollout is disabled, got %v, %v", ok, err)
+	}
+	if h := getHash(application); h != "" {
+		t.Fatalf("expected no annotation, got %s", h)
+	}
+
+	if _, err := setSecretHashAnnotation(&application, defaultWorkloadMapping, newBinding(true), secret); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	h := getHash(application)
+	if h == "" {
+		t.Fatalf("expected annotation %s to be set", annotation)
+	}
+
+	if _, err := setSecretHashAnnotation(&application, defaultWorkloadMapping, newBinding(true), secret); err != nil || getHash(application) != h {
+		t.Fatalf("expected the annotation not to change when the secret data does not change")
+	}
+
+	secret.Data["password"] = []byte("new")
+	if _, err := setSecretHashAnnotation(&application, defaultWorkloadMapping, newBinding(true), secret); err != nil || getHash(application) == h {
+		t.Fatalf("expected the annotation to change when the secret data changes")
+	}
+
+	if err := removeSecretHashAnnotation(&application, defaultWorkloadMapping, newBinding(true)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if h := getHash(application); h != "" {
+		t.Fatalf("expected annotation to be removed, got %s", h)
+	}
+}
+
+func Test_PodTemplateAnnotationsPath(t *testing.T) {
+	m := builtinWorkloadMappings[schema.GroupKind{Group: "batch", Kind: "CronJob"}]
+	p, ok := m.podTemplateAnnotationsPath()
+	want := []string{"spec", "jobTemplate", "spec", "template", "metadata", "annotations"}
+	if !ok || !reflect.DeepEqual(p, want) {
+		t.Fatalf("expected %v, got %v", want, p)
+	}
+
+	if _, ok := (workloadMapping{volumes: []string{"volumes"}}).podTemplateAnnotationsPath(); ok {
+		t.Fatalf("expected no pod template for volumes outside of a pod spec")
+	}
+}
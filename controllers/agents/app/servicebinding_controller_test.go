@@ -0,0 +1,132 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	primazaiov1alpha1 "github.com/primaza/primaza/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func Test_VerifyApplicationSatisfiesServiceBindingSpec(t *testing.T) {
+	type test struct {
+		name        string
+		application primazaiov1alpha1.ApplicationSelector
+		labels      map[string]string
+		want        bool
+	}
+
+	tt := []test{
+		{
+			name:        "name match",
+			application: primazaiov1alpha1.ApplicationSelector{Name: "app"},
+			want:        true,
+		},
+		{
+			name:        "name mismatch",
+			application: primazaiov1alpha1.ApplicationSelector{Name: "other"},
+			want:        false,
+		},
+		{
+			name: "match labels",
+			application: primazaiov1alpha1.ApplicationSelector{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			},
+			labels: map[string]string{"app": "web", "tier": "frontend"},
+			want:   true,
+		},
+		{
+			name: "match labels mismatch",
+			application: primazaiov1alpha1.ApplicationSelector{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			},
+			labels: map[string]string{"app": "db"},
+			want:   false,
+		},
+		{
+			name: "match expressions in",
+			application: primazaiov1alpha1.ApplicationSelector{
+				Selector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "tier", Operator: metav1.LabelSelectorOpIn, Values: []string{"frontend", "backend"}},
+					},
+				},
+			},
+			labels: map[string]string{"tier": "backend"},
+			want:   true,
+		},
+		{
+			name: "match expressions does not exist",
+			application: primazaiov1alpha1.ApplicationSelector{
+				Selector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "canary", Operator: metav1.LabelSelectorOpDoesNotExist},
+					},
+				},
+			},
+			labels: map[string]string{"canary": "true"},
+			want:   false,
+		},
+		{
+			name: "match labels and expressions",
+			application: primazaiov1alpha1.ApplicationSelector{
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"app": "web"},
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "tier", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"db"}},
+					},
+				},
+			},
+			labels: map[string]string{"app": "web", "tier": "frontend"},
+			want:   true,
+		},
+		{
+			name: "invalid selector",
+			application: primazaiov1alpha1.ApplicationSelector{
+				Selector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "tier", Operator: metav1.LabelSelectorOpIn},
+					},
+				},
+			},
+			labels: map[string]string{"tier": "frontend"},
+			want:   false,
+		},
+		{
+			name:        "no name nor selector",
+			application: primazaiov1alpha1.ApplicationSelector{},
+			want:        false,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := &unstructured.Unstructured{}
+			obj.SetName("app")
+			obj.SetLabels(tc.labels)
+			sb := primazaiov1alpha1.ServiceBinding{
+				Spec: primazaiov1alpha1.ServiceBindingSpec{Application: tc.application},
+			}
+
+			if got := verifyApplicationSatisfiesServiceBindingSpec(obj, sb); got != tc.want {
+				t.Errorf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
@@ -18,8 +18,9 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"os"
-	"strings"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -142,15 +143,31 @@ func (r *ServiceClaimReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return ctrl.Result{}, nil
 	}
 
+	// the control plane deletes expired ServiceClaims: delete the local one
+	// as well, so that it is not pushed again
+	result := ctrl.Result{}
+	if expiry := sclaim.ExpirationTime(); expiry != nil {
+		remaining := time.Until(*expiry)
+		if remaining <= 0 {
+			l.Info("service claim lease expired, deleting the service claim", "expiration-time", expiry)
+			return ctrl.Result{}, client.IgnoreNotFound(r.Delete(ctx, &sclaim))
+		}
+		result.RequeueAfter = remaining
+	}
+
 	sclaimCopy := r.createServiceClaimCopy(sclaim, deployment, remote_namespace)
 	spec := sclaimCopy.Spec
+	renewedAt, renewed := sclaim.Annotations[constants.ServiceClaimLeaseRenewedAtAnnotation]
 
 	l.Info("Wrote service claim", "claim", sclaim.Name, "namespace", sclaim.Namespace)
 	if _, err := controllerutil.CreateOrUpdate(ctx, remote_client, sclaimCopy, func() error {
 		sclaimCopy.Spec = spec
+		if renewed {
+			metav1.SetMetaDataAnnotation(&sclaimCopy.ObjectMeta, constants.ServiceClaimLeaseRenewedAtAnnotation, renewedAt)
+		}
 		return nil
 	}); err != nil {
-		if strings.Contains(err.Error(), "admission webhook \"vserviceclaim.kb.io\" denied the request") {
+		if causes, ok := validationCauses(err); ok {
 			c := metav1.Condition{
 				LastTransitionTime: metav1.Now(),
 				Type:               string(primazaiov1alpha1.ServiceClaimConditionReady),
@@ -160,6 +177,7 @@ func (r *ServiceClaimReconciler) Reconcile(ctx context.Context, req ctrl.Request
 			}
 			meta.SetStatusCondition(&sclaim.Status.Conditions, c)
 			sclaim.Status.State = primazaiov1alpha1.ServiceClaimStateInvalid
+			sclaim.Status.ValidationErrors = causes
 
 			if err := r.Status().Update(ctx, &sclaim); err != nil {
 				l.Error(err, "unable to update the ServiceClaim", "ServiceClaim", sclaim)
@@ -174,7 +192,21 @@ func (r *ServiceClaimReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		}
 	}
 
-	return ctrl.Result{}, nil
+	return result, nil
+}
+
+// validationCauses returns the causes of the rejection of an invalid
+// ServiceClaim
+func validationCauses(err error) ([]metav1.StatusCause, bool) {
+	var status apierrors.APIStatus
+	if !apierrors.IsInvalid(err) || !errors.As(err, &status) {
+		return nil, false
+	}
+
+	if d := status.Status().Details; d != nil {
+		return d.Causes, true
+	}
+	return []metav1.StatusCause{}, true
 }
 
 func (r *ServiceClaimReconciler) createServiceClaimCopy(sclaim primazaiov1alpha1.ServiceClaim, deployment appsv1.Deployment, remote_namespace string) *primazaiov1alpha1.ServiceClaim {
@@ -192,7 +224,8 @@ func (r *ServiceClaimReconciler) createServiceClaimCopy(sclaim primazaiov1alpha1
 }
 
 func (r *ServiceClaimReconciler) deleteExternalResources(ctx context.Context, sclaim *primazaiov1alpha1.ServiceClaim, cli client.Client) error {
-	if err := cli.Delete(ctx, sclaim); err != nil {
+	// the control plane may have already deleted an expired ServiceClaim
+	if err := cli.Delete(ctx, sclaim); client.IgnoreNotFound(err) != nil {
 		return err
 	}
 	return nil
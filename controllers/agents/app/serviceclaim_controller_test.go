@@ -0,0 +1,76 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func Test_ValidationCauses(t *testing.T) {
+	gk := schema.GroupKind{Group: "primaza.io", Kind: "ServiceClaim"}
+	invalid := apierrors.NewInvalid(gk, "claim", field.ErrorList{
+		field.NotFound(field.NewPath("spec", "serviceEndpointDefinitionKeys").Index(0), "url"),
+	})
+
+	tests := []struct {
+		name    string
+		err     error
+		invalid bool
+		causes  []metav1.StatusCause
+	}{
+		{
+			name:    "invalid claim",
+			err:     invalid,
+			invalid: true,
+			causes: []metav1.StatusCause{{
+				Type:    metav1.CauseTypeFieldValueNotFound,
+				Message: `Not found: "url"`,
+				Field:   "spec.serviceEndpointDefinitionKeys[0]",
+			}},
+		},
+		{
+			name:    "wrapped invalid claim",
+			err:     fmt.Errorf("pushing claim: %w", invalid),
+			invalid: true,
+			causes:  invalid.Status().Details.Causes,
+		},
+		{
+			name: "other error",
+			err:  apierrors.NewForbidden(schema.GroupResource{Group: "primaza.io", Resource: "serviceclaims"}, "claim", errors.New("forbidden")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			causes, ok := validationCauses(tt.err)
+			if ok != tt.invalid {
+				t.Fatalf("expected invalid to be %v, got %v", tt.invalid, ok)
+			}
+			if !reflect.DeepEqual(causes, tt.causes) {
+				t.Errorf("expected causes %v, got %v", tt.causes, causes)
+			}
+		})
+	}
+}
@@ -187,8 +187,18 @@ func (r *ServiceClassReconciler) Reconcile(ctx context.Context, req ctrl.Request
 }
 
 func updateRegisteredService(ctx context.Context, target_client client.Client, rs v1alpha1.RegisteredService, secret *v1.Secret) []error {
-	spec := rs.Spec
+	legacy, err := migrateLegacyRegisteredService(ctx, target_client, &rs, secret)
+	if err != nil {
+		return []error{err}
+	}
+
 	reconcileLog := log.FromContext(ctx).WithValues("namespace", rs.Namespace, "name", rs.Name)
+	if err := checkNameCollision(ctx, target_client, rs); err != nil {
+		reconcileLog.Error(err, "Can not write registered service", "service", rs.Name, "namespace", rs.Namespace)
+		return []error{err}
+	}
+
+	spec := rs.Spec
 	op, err := controllerutil.CreateOrUpdate(ctx, target_client, &rs, func() error {
 		rs.Spec = spec
 		return nil
@@ -207,11 +217,41 @@ func updateRegisteredService(ctx context.Context, target_client client.Client, r
 		})
 		errs = append(errs, err)
 	}
+
+	// the registered service has been migrated to its new name, so the
+	// legacy one can be deleted
+	if legacy != nil && err == nil {
+		reconcileLog.Info("Migrated legacy registered service", "legacy", legacy.Name)
+		if err := target_client.Delete(ctx, legacy); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, err)
+		}
+	}
 	return errs
 }
 
 func deleteRegisteredService(ctx context.Context, target_client client.Client, rs v1alpha1.RegisteredService, secret *v1.Secret) []error {
 	reconcileLog := log.FromContext(ctx).WithValues("namespace", rs.Namespace, "name", rs.Name)
+	legacy, err := getLegacyRegisteredService(ctx, target_client, rs)
+	if err != nil {
+		return []error{err}
+	}
+	if legacy != nil {
+		if err := target_client.Delete(ctx, legacy); err != nil && !apierrors.IsNotFound(err) {
+			reconcileLog.Error(err, "Failed to delete legacy registered service", "legacy", legacy.Name)
+			return []error{err}
+		}
+	}
+
+	if err := checkNameCollision(ctx, target_client, rs); err != nil {
+		var collision *nameCollisionError
+		if errors.As(err, &collision) {
+			// the registered service has been generated from another
+			// resource, so we must not delete it
+			return nil
+		}
+		return []error{err}
+	}
+
 	if err := target_client.Delete(ctx, &rs); err != nil {
 		if apierrors.IsNotFound(err) {
 			// we tried to delete an object that doesn't exist, so
@@ -237,14 +277,23 @@ func (r *ServiceClassReconciler) GetResources(ctx context.Context, serviceClass
 		return nil, err
 	}
 
+	opts, err := resourceListOptions(serviceClass.Spec.Resource)
+	if err != nil {
+		return nil, err
+	}
+
 	services, err := r.Interface.Resource(mapping.Resource).
 		Namespace(serviceClass.Namespace).
-		List(ctx, metav1.ListOptions{})
+		List(ctx, opts)
 
 	if err != nil || services == nil {
 		return nil, err
 	}
 
+	if services.Items, err = filterReadyResources(serviceClass.Spec.Resource, services.Items); err != nil {
+		return nil, err
+	}
+
 	return services, nil
 }
 
@@ -322,15 +371,16 @@ func (r *ServiceClassReconciler) HandleRegisteredServices(ctx context.Context, s
 			errorList = append(errorList, errs...)
 		}
 	}
+	setNameCollisionCondition(serviceClass, errorList)
 
 	return errors.Join(errorList...)
 }
 
-func LookupServiceEndpointDescriptor(ctx context.Context, mappings []sed.SEDMapping, service unstructured.Unstructured) ([]v1alpha1.ServiceEndpointDefinitionItem, *v1.Secret, error) {
+func LookupServiceEndpointDescriptor(ctx context.Context, mappings []sed.SEDMapping, secretName string) ([]v1alpha1.ServiceEndpointDefinitionItem, *v1.Secret, error) {
 	var sedMappings []v1alpha1.ServiceEndpointDefinitionItem
 	var errorList []error
 	secret := &v1.Secret{StringData: map[string]string{}}
-	secret.SetName(fmt.Sprintf("%s-descriptor", service.GetName()))
+	secret.SetName(secretName)
 	for _, mapping := range mappings {
 		value, err := mapping.ReadKey(ctx)
 		if err != nil {
@@ -373,7 +423,9 @@ func PrepareRegisteredService(
 	target_namespace string,
 ) (v1alpha1.RegisteredService, *v1.Secret, error) {
 	l := log.FromContext(ctx)
-	sedMappings, secret, err := LookupServiceEndpointDescriptor(ctx, mappings, data)
+	clusterEnvironment := os.Getenv(constants.PrimazaClusterEnvironmentEnvVar)
+	name := registeredServiceName(clusterEnvironment, data)
+	sedMappings, secret, err := LookupServiceEndpointDescriptor(ctx, mappings, descriptorSecretName(name))
 	if err != nil {
 		l.Error(err, "Failed to lookup service endpoint descriptor values",
 			"name", data.GetName(),
@@ -384,23 +436,17 @@ func PrepareRegisteredService(
 
 	rs := v1alpha1.RegisteredService{
 		ObjectMeta: metav1.ObjectMeta{
-			// FIXME(sadlerap): this could cause naming conflicts; we need
-			// to take into account the type of resource somehow.
-			Name:      data.GetName(),
-			Namespace: target_namespace,
-			Annotations: map[string]string{
-				constants.ServiceAPIVersionAnnotation:  serviceClass.Spec.Resource.APIVersion,
-				constants.ServiceKindAnnotation:        serviceClass.Spec.Resource.Kind,
-				constants.ServiceNameAnnotation:        data.GetName(),
-				constants.ServiceNamespaceAnnotation:   data.GetNamespace(),
-				constants.ServiceUIDAnnotation:         string(data.GetUID()),
-				constants.ClusterEnvironmentAnnotation: os.Getenv(constants.PrimazaClusterEnvironmentEnvVar),
-			},
+			Name:        name,
+			Namespace:   target_namespace,
+			Annotations: registeredServiceOrigin(serviceClass, data, clusterEnvironment),
 		},
 		Spec: v1alpha1.RegisteredServiceSpec{
 			ServiceEndpointDefinition: sedMappings,
 			ServiceClassIdentity:      serviceClass.Spec.ServiceClassIdentity,
 			HealthCheck:               serviceClass.Spec.HealthCheck,
+			CredentialIssuer:          serviceClass.Spec.CredentialIssuer,
+			RequiresApproval:          serviceClass.Spec.RequiresApproval,
+			ReservedFor:               data.GetAnnotations()[constants.ServiceProvisioningClaimIDAnnotation],
 		},
 	}
 
@@ -433,6 +479,22 @@ func ServiceEndpointDefinitionMapping(cli client.Client, obj unstructured.Unstru
 		mappings = append(mappings, m)
 	}
 
+	for _, m := range serviceClass.Spec.Resource.ServiceEndpointDefinitionMappings.ConfigMapRefFields {
+		m, err := sed.NewSEDConfigMapRefMapping(serviceClass.GetNamespace(), obj, cli, m)
+		if err != nil {
+			return nil, err
+		}
+		mappings = append(mappings, m)
+	}
+
+	for _, m := range serviceClass.Spec.Resource.ServiceEndpointDefinitionMappings.ObjectRefFields {
+		m, err := sed.NewSEDObjectRefMapping(serviceClass.GetNamespace(), obj, cli, m)
+		if err != nil {
+			return nil, err
+		}
+		mappings = append(mappings, m)
+	}
+
 	return mappings, nil
 }
 
@@ -487,7 +549,15 @@ func (r *ServiceClassReconciler) RunInformer(ctx context.Context, resource schem
 		l.Info("failed creating cluster config")
 		panic(err)
 	}
-	factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(clusterClient, time.Minute, serviceClass.Namespace, nil)
+	opts, err := resourceListOptions(serviceClass.Spec.Resource)
+	if err != nil {
+		return err
+	}
+	tweakListOptions := func(o *metav1.ListOptions) {
+		o.LabelSelector = opts.LabelSelector
+		o.FieldSelector = opts.FieldSelector
+	}
+	factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(clusterClient, time.Minute, serviceClass.Namespace, tweakListOptions)
 	i := factory.ForResource(resource).Informer()
 	ictx, fc := context.WithCancel(ctx)
 
@@ -499,6 +569,9 @@ func (r *ServiceClassReconciler) RunInformer(ctx context.Context, resource schem
 				return
 			}
 			serviceClassResource := obj.(*unstructured.Unstructured)
+			if ready, err := isResourceReady(serviceClass.Spec.Resource, *serviceClassResource); err != nil || !ready {
+				return
+			}
 			if err := r.CreateOrUpdateRegisteredService(ictx, *serviceClassResource, serviceClass); err != nil {
 				return
 			}
@@ -508,6 +581,18 @@ func (r *ServiceClassReconciler) RunInformer(ctx context.Context, resource schem
 				return
 			}
 			serviceClassResource := future.(*unstructured.Unstructured)
+			ready, err := isResourceReady(serviceClass.Spec.Resource, *serviceClassResource)
+			if err != nil {
+				return
+			}
+			if !ready {
+				// the resource is not ready anymore, so its registered
+				// service needs to be withdrawn
+				if err := r.DeleteRegisteredService(ictx, *serviceClassResource, serviceClass); err != nil {
+					return
+				}
+				return
+			}
 			if err := r.CreateOrUpdateRegisteredService(ictx, *serviceClassResource, serviceClass); err != nil {
 				return
 			}
@@ -516,7 +601,14 @@ func (r *ServiceClassReconciler) RunInformer(ctx context.Context, resource schem
 			if !synced.Load() {
 				return
 			}
-			if err := r.DeleteRegisteredService(ictx, serviceClass); err != nil {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			serviceClassResource, ok := obj.(*unstructured.Unstructured)
+			if !ok {
+				return
+			}
+			if err := r.DeleteRegisteredService(ictx, *serviceClassResource, serviceClass); err != nil {
 				return
 			}
 		},
@@ -543,7 +635,6 @@ func (r *ServiceClassReconciler) RunInformer(ctx context.Context, resource schem
 }
 
 func (r *ServiceClassReconciler) CreateOrUpdateRegisteredService(ctx context.Context, obj unstructured.Unstructured, serviceClass v1alpha1.ServiceClass) error {
-	l := log.FromContext(ctx)
 	var mappings []sed.SEDMapping
 	var err error
 
@@ -561,38 +652,33 @@ func (r *ServiceClassReconciler) CreateOrUpdateRegisteredService(ctx context.Con
 	if err != nil {
 		return err
 	}
-	errs := []error{err}
 	var rs v1alpha1.RegisteredService
 	var secret *v1.Secret
 	if rs, secret, err = PrepareRegisteredService(ctx, serviceClass, mappings, obj, target_namespace); err != nil {
 		return err
 	}
-	spec := rs.Spec
-	op, err := controllerutil.CreateOrUpdate(ctx, target_client, &rs, func() error {
-		rs.Spec = spec
-		return nil
-	})
-	if err != nil {
-		l.Error(err, "Failed to create or update registered service")
-		errs = append(errs, err)
-	} else {
-		l.Info("Wrote registered service", "registered service", rs.Name, "namespace", rs.Namespace, "operation", op)
-	}
-	if secret != nil {
-		data := secret.StringData
-		_, err := controllerutil.CreateOrUpdate(ctx, target_client, secret, func() error {
-			secret.StringData = data
 
-			return controllerutil.SetOwnerReference(&rs, secret, target_client.Scheme())
-		})
-		errs = append(errs, err)
+	errs := updateRegisteredService(ctx, target_client, rs, secret)
+	var collision *nameCollisionError
+	if errors.As(errors.Join(errs...), &collision) {
+		errs = append(errs, r.reportNameCollisions(ctx, serviceClass, errs))
 	}
 	return errors.Join(errs...)
 }
 
-func (r *ServiceClassReconciler) DeleteRegisteredService(ctx context.Context, serviceClass v1alpha1.ServiceClass) error {
+// reportNameCollisions sets the NameCollision condition on the ServiceClass
+func (r *ServiceClassReconciler) reportNameCollisions(ctx context.Context, serviceClass v1alpha1.ServiceClass, errs []error) error {
+	sc := v1alpha1.ServiceClass{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(&serviceClass), &sc); err != nil {
+		return err
+	}
+	setNameCollisionCondition(&sc, errs)
+	return r.Client.Status().Update(ctx, &sc)
+}
+
+func (r *ServiceClassReconciler) DeleteRegisteredService(ctx context.Context, obj unstructured.Unstructured, serviceClass v1alpha1.ServiceClass) error {
 	l := log.FromContext(ctx)
-	config, _, err := r.getTargetClient(ctx, serviceClass.Namespace)
+	config, target_namespace, err := r.getTargetClient(ctx, serviceClass.Namespace)
 	if err != nil {
 		return err
 	}
@@ -605,15 +691,15 @@ func (r *ServiceClassReconciler) DeleteRegisteredService(ctx context.Context, se
 	}
 	l.Info("remote cluster", "address", config.Host)
 
+	clusterEnvironment := os.Getenv(constants.PrimazaClusterEnvironmentEnvVar)
 	registeredService := v1alpha1.RegisteredService{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: serviceClass.Name,
+			Name:        registeredServiceName(clusterEnvironment, obj),
+			Namespace:   target_namespace,
+			Annotations: registeredServiceOrigin(serviceClass, obj, clusterEnvironment),
 		},
 	}
-	if err = target_client.Delete(ctx, &registeredService, &client.DeleteOptions{}); !apierrors.IsNotFound(err) {
-		return err
-	}
-	return nil
+	return errors.Join(deleteRegisteredService(ctx, target_client, registeredService, nil)...)
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -0,0 +1,86 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package svc
+
+import (
+	"bytes"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/util/jsonpath"
+
+	"github.com/primaza/primaza/api/v1alpha1"
+)
+
+// resourceListOptions builds the options used to list and watch the
+// resources of a ServiceClass, applying its label and field selectors
+func resourceListOptions(resource v1alpha1.ServiceClassResource) (metav1.ListOptions, error) {
+	opts := metav1.ListOptions{}
+	if resource.LabelSelector != nil {
+		s, err := metav1.LabelSelectorAsSelector(resource.LabelSelector)
+		if err != nil {
+			return opts, err
+		}
+		opts.LabelSelector = s.String()
+	}
+	if resource.FieldSelector != "" {
+		s, err := fields.ParseSelector(resource.FieldSelector)
+		if err != nil {
+			return opts, err
+		}
+		opts.FieldSelector = s.String()
+	}
+	return opts, nil
+}
+
+// isResourceReady checks whether the resource satisfies the ServiceClass'
+// readiness condition. Resources are always ready if no condition is defined.
+func isResourceReady(resource v1alpha1.ServiceClassResource, obj unstructured.Unstructured) (bool, error) {
+	if resource.Readiness == nil {
+		return true, nil
+	}
+
+	j := jsonpath.New("")
+	j.AllowMissingKeys(true)
+	if err := j.Parse(fmt.Sprintf("{%s}", resource.Readiness.JsonPath)); err != nil {
+		return false, err
+	}
+
+	buf := bytes.Buffer{}
+	if err := j.Execute(&buf, obj.Object); err != nil {
+		return false, err
+	}
+	return buf.String() == resource.Readiness.Value, nil
+}
+
+// filterReadyResources returns the resources satisfying the ServiceClass'
+// readiness condition
+func filterReadyResources(resource v1alpha1.ServiceClassResource, objs []unstructured.Unstructured) ([]unstructured.Unstructured, error) {
+	ready := []unstructured.Unstructured{}
+	for _, o := range objs {
+		ok, err := isResourceReady(resource, o)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			ready = append(ready, o)
+		}
+	}
+	return ready, nil
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package svc
+
+import (
+	"testing"
+
+	"github.com/primaza/primaza/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestResourceListOptions(t *testing.T) {
+	resource := v1alpha1.ServiceClassResource{
+		LabelSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{"tier": "backend"},
+		},
+		FieldSelector: "metadata.name!=ignored",
+	}
+
+	opts, err := resourceListOptions(resource)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.LabelSelector != "tier=backend" {
+		t.Errorf("expected label selector %q, got %q", "tier=backend", opts.LabelSelector)
+	}
+	if opts.FieldSelector != "metadata.name!=ignored" {
+		t.Errorf("expected field selector %q, got %q", "metadata.name!=ignored", opts.FieldSelector)
+	}
+}
+
+func TestIsResourceReady(t *testing.T) {
+	obj := func(phase string) unstructured.Unstructured {
+		u := unstructured.Unstructured{Object: map[string]interface{}{}}
+		if phase != "" {
+			u.Object["status"] = map[string]interface{}{"phase": phase}
+		}
+		return u
+	}
+	readiness := &v1alpha1.ServiceClassResourceReadiness{
+		JsonPath: ".status.phase",
+		Value:    "Ready",
+	}
+
+	tests := []struct {
+		name      string
+		readiness *v1alpha1.ServiceClassResourceReadiness
+		obj       unstructured.Unstructured
+		expected  bool
+	}{
+		{
+			name:     "no readiness condition",
+			obj:      obj(""),
+			expected: true,
+		},
+		{
+			name:      "ready resource",
+			readiness: readiness,
+			obj:       obj("Ready"),
+			expected:  true,
+		},
+		{
+			name:      "not ready resource",
+			readiness: readiness,
+			obj:       obj("Provisioning"),
+			expected:  false,
+		},
+		{
+			name:      "missing readiness field",
+			readiness: readiness,
+			obj:       obj(""),
+			expected:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resource := v1alpha1.ServiceClassResource{Readiness: tt.readiness}
+			ready, err := isResourceReady(resource, tt.obj)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ready != tt.expected {
+				t.Errorf("expected ready to be %v, got %v", tt.expected, ready)
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package svc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/primaza/primaza/api/v1alpha1"
+	"github.com/primaza/primaza/pkg/primaza/constants"
+)
+
+// registeredServiceNameHashLength is the number of hex characters of the
+// origin's hash appended to RegisteredServices' names
+const registeredServiceNameHashLength = 10
+
+// registeredServiceName returns the name of the RegisteredService generated
+// for a resource discovered in the given cluster environment.
+//
+// The name is made of the resource's name followed by a hash of the
+// resource's cluster environment, namespace, group, kind, and name, so that
+// resources with the same name from different origins are not mixed up.
+func registeredServiceName(clusterEnvironment string, obj unstructured.Unstructured) string {
+	gvk := obj.GroupVersionKind()
+	origin := strings.Join([]string{clusterEnvironment, obj.GetNamespace(), gvk.Group, gvk.Kind, obj.GetName()}, "/")
+	h := sha256.Sum256([]byte(origin))
+	suffix := hex.EncodeToString(h[:])[:registeredServiceNameHashLength]
+
+	name := obj.GetName()
+	if l := validation.DNS1123SubdomainMaxLength - len(suffix) - 1; len(name) > l {
+		name = strings.TrimRight(name[:l], "-.")
+	}
+	return fmt.Sprintf("%s-%s", name, suffix)
+}
+
+// registeredServiceOrigin returns the annotations identifying the resource a
+// RegisteredService has been generated from
+func registeredServiceOrigin(serviceClass v1alpha1.ServiceClass, obj unstructured.Unstructured, clusterEnvironment string) map[string]string {
+	return map[string]string{
+		constants.ServiceAPIVersionAnnotation:  serviceClass.Spec.Resource.APIVersion,
+		constants.ServiceKindAnnotation:        serviceClass.Spec.Resource.Kind,
+		constants.ServiceNameAnnotation:        obj.GetName(),
+		constants.ServiceNamespaceAnnotation:   obj.GetNamespace(),
+		constants.ServiceUIDAnnotation:         string(obj.GetUID()),
+		constants.ClusterEnvironmentAnnotation: clusterEnvironment,
+	}
+}
+
+// sameOrigin checks whether two RegisteredServices have been generated from
+// the same resource. The resource's UID and version are not taken into
+// account, so that recreated or upgraded resources keep their
+// RegisteredService.
+func sameOrigin(a, b v1alpha1.RegisteredService) bool {
+	for _, k := range []string{
+		constants.ClusterEnvironmentAnnotation,
+		constants.ServiceNamespaceAnnotation,
+		constants.ServiceKindAnnotation,
+		constants.ServiceNameAnnotation,
+	} {
+		if a.GetAnnotations()[k] != b.GetAnnotations()[k] {
+			return false
+		}
+	}
+
+	return originGroup(a) == originGroup(b)
+}
+
+func originGroup(rs v1alpha1.RegisteredService) string {
+	gv, err := schema.ParseGroupVersion(rs.GetAnnotations()[constants.ServiceAPIVersionAnnotation])
+	if err != nil {
+		return rs.GetAnnotations()[constants.ServiceAPIVersionAnnotation]
+	}
+	return gv.Group
+}
+
+// isClaimed checks whether a RegisteredService is held by any ServiceClaim
+func isClaimed(rs v1alpha1.RegisteredService) bool {
+	return rs.Status.State == v1alpha1.RegisteredServiceStateClaimed || len(rs.Status.Claims) > 0
+}
+
+// renameRegisteredService sets the name of the RegisteredService and of its
+// Service Endpoint Definition secret
+func renameRegisteredService(rs *v1alpha1.RegisteredService, secret *v1.Secret, name string) {
+	rs.SetName(name)
+	if secret == nil {
+		return
+	}
+
+	secret.SetName(descriptorSecretName(name))
+	for _, i := range rs.Spec.ServiceEndpointDefinition {
+		if i.ValueFromSecret != nil {
+			i.ValueFromSecret.Name = secret.GetName()
+		}
+	}
+}
+
+func descriptorSecretName(registeredServiceName string) string {
+	return fmt.Sprintf("%s-descriptor", registeredServiceName)
+}
+
+// nameCollisionError is returned when the name of a RegisteredService is
+// already taken by a RegisteredService generated from a different resource
+type nameCollisionError struct {
+	name   string
+	origin string
+}
+
+func (e *nameCollisionError) Error() string {
+	return fmt.Sprintf("registered service %s already exists and has been generated from %s", e.name, e.origin)
+}
+
+func describeOrigin(rs v1alpha1.RegisteredService) string {
+	a := rs.GetAnnotations()
+	return fmt.Sprintf("%s %s/%s in cluster environment %s",
+		a[constants.ServiceKindAnnotation],
+		a[constants.ServiceNamespaceAnnotation],
+		a[constants.ServiceNameAnnotation],
+		a[constants.ClusterEnvironmentAnnotation])
+}
+
+// checkNameCollision returns a nameCollisionError if the RegisteredService's
+// name is already taken by a RegisteredService generated from a different
+// resource
+func checkNameCollision(ctx context.Context, cli client.Client, rs v1alpha1.RegisteredService) error {
+	existing := v1alpha1.RegisteredService{}
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(&rs), &existing); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	if !sameOrigin(existing, rs) {
+		return &nameCollisionError{name: rs.GetName(), origin: describeOrigin(existing)}
+	}
+	return nil
+}
+
+// getLegacyRegisteredService looks for a RegisteredService generated from the
+// same resource but named after the resource only, as done by previous
+// versions of the Service Agent
+func getLegacyRegisteredService(ctx context.Context, cli client.Client, rs v1alpha1.RegisteredService) (*v1alpha1.RegisteredService, error) {
+	legacyName := rs.GetAnnotations()[constants.ServiceNameAnnotation]
+	if legacyName == "" || legacyName == rs.GetName() {
+		return nil, nil
+	}
+
+	legacy := v1alpha1.RegisteredService{}
+	if err := cli.Get(ctx, client.ObjectKey{Namespace: rs.GetNamespace(), Name: legacyName}, &legacy); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if !sameOrigin(legacy, rs) {
+		return nil, nil
+	}
+	return &legacy, nil
+}
+
+// migrateLegacyRegisteredService prepares the migration of a legacy
+// RegisteredService generated from the same resource as rs.
+//
+// While the legacy RegisteredService is claimed, rs is renamed after it, so
+// that the claim is not broken. Otherwise, the legacy RegisteredService is
+// returned so that it can be deleted once rs has been written.
+func migrateLegacyRegisteredService(ctx context.Context, cli client.Client, rs *v1alpha1.RegisteredService, secret *v1.Secret) (*v1alpha1.RegisteredService, error) {
+	legacy, err := getLegacyRegisteredService(ctx, cli, *rs)
+	if err != nil || legacy == nil {
+		return nil, err
+	}
+
+	if isClaimed(*legacy) {
+		log.FromContext(ctx).Info("legacy registered service is claimed, postponing its migration", "registered service", legacy.Name)
+		renameRegisteredService(rs, secret, legacy.Name)
+		return nil, nil
+	}
+	return legacy, nil
+}
+
+// setNameCollisionCondition sets the NameCollision condition on the
+// ServiceClass according to the collisions found in errs
+func setNameCollisionCondition(serviceClass *v1alpha1.ServiceClass, errs []error) {
+	collisions := []string{}
+	for _, err := range errs {
+		var collision *nameCollisionError
+		if errors.As(err, &collision) {
+			collisions = append(collisions, collision.Error())
+		}
+	}
+
+	condition := metav1.Condition{
+		Type:   v1alpha1.ServiceClassNameCollisionCondition,
+		Status: metav1.ConditionFalse,
+		Reason: constants.NoNameCollisionReason,
+	}
+	if len(collisions) > 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = constants.NameCollisionReason
+		condition.Message = strings.Join(collisions, "; ")
+	}
+	meta.SetStatusCondition(&serviceClass.Status.Conditions, condition)
+}
@@ -0,0 +1,196 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package svc
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/primaza/primaza/api/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newResource(apiVersion, kind, namespace, name string) unstructured.Unstructured {
+	u := unstructured.Unstructured{}
+	u.SetAPIVersion(apiVersion)
+	u.SetKind(kind)
+	u.SetNamespace(namespace)
+	u.SetName(name)
+	return u
+}
+
+func newRegisteredService(cluster string, obj unstructured.Unstructured, name string) v1alpha1.RegisteredService {
+	sc := v1alpha1.ServiceClass{
+		Spec: v1alpha1.ServiceClassSpec{
+			Resource: v1alpha1.ServiceClassResource{
+				APIVersion: obj.GetAPIVersion(),
+				Kind:       obj.GetKind(),
+			},
+		},
+	}
+	return v1alpha1.RegisteredService{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "primaza-system",
+			Annotations: registeredServiceOrigin(sc, obj, cluster),
+		},
+	}
+}
+
+func TestRegisteredServiceName(t *testing.T) {
+	db := newResource("db.example.com/v1", "Database", "services", "mydb")
+
+	name := registeredServiceName("worker", db)
+	if !strings.HasPrefix(name, "mydb-") {
+		t.Errorf("expected name %q to start with the resource name", name)
+	}
+	if name != registeredServiceName("worker", db) {
+		t.Errorf("expected name to be deterministic")
+	}
+	if name != registeredServiceName("worker", newResource("db.example.com/v2", "Database", "services", "mydb")) {
+		t.Errorf("expected name not to depend on the resource version")
+	}
+
+	for _, other := range []struct {
+		cluster string
+		obj     unstructured.Unstructured
+	}{
+		{cluster: "worker-2", obj: db},
+		{cluster: "worker", obj: newResource("db.example.com/v1", "Database", "services-2", "mydb")},
+		{cluster: "worker", obj: newResource("db.example.com/v1", "Cache", "services", "mydb")},
+		{cluster: "worker", obj: newResource("cache.example.com/v1", "Database", "services", "mydb")},
+	} {
+		if n := registeredServiceName(other.cluster, other.obj); n == name {
+			t.Errorf("expected different origins to have different names, got %q twice", n)
+		}
+	}
+
+	long := newResource("v1", "Service", "services", strings.Repeat("a", validation.DNS1123SubdomainMaxLength))
+	if n := registeredServiceName("worker", long); len(n) > validation.DNS1123SubdomainMaxLength {
+		t.Errorf("expected name to be truncated, got %d characters", len(n))
+	}
+}
+
+func newFakeClient(t *testing.T, objs ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
+
+func TestUpdateRegisteredServiceNameCollision(t *testing.T) {
+	db := newResource("db.example.com/v1", "Database", "services", "mydb")
+	rs := newRegisteredService("worker", db, registeredServiceName("worker", db))
+	other := newRegisteredService("worker-2", db, rs.Name)
+	cli := newFakeClient(t, &other)
+
+	errs := updateRegisteredService(context.Background(), cli, rs, nil)
+	var collision *nameCollisionError
+	if !errors.As(errors.Join(errs...), &collision) {
+		t.Fatalf("expected a name collision error, got %v", errs)
+	}
+
+	sc := v1alpha1.ServiceClass{}
+	setNameCollisionCondition(&sc, errs)
+	if !meta.IsStatusConditionTrue(sc.Status.Conditions, v1alpha1.ServiceClassNameCollisionCondition) {
+		t.Errorf("expected condition %s to be true", v1alpha1.ServiceClassNameCollisionCondition)
+	}
+
+	// the colliding registered service must not be deleted
+	if errs := deleteRegisteredService(context.Background(), cli, rs, nil); errors.Join(errs...) != nil {
+		t.Fatalf("unexpected error: %v", errs)
+	}
+	if err := cli.Get(context.Background(), client.ObjectKeyFromObject(&other), &v1alpha1.RegisteredService{}); err != nil {
+		t.Errorf("expected colliding registered service to be kept, got %v", err)
+	}
+}
+
+func TestUpdateRegisteredServiceMigration(t *testing.T) {
+	db := newResource("db.example.com/v1", "Database", "services", "mydb")
+	name := registeredServiceName("worker", db)
+
+	tests := []struct {
+		name         string
+		legacyState  v1alpha1.RegisteredServiceState
+		expectedName string
+		legacyExists bool
+	}{
+		{
+			name:         "available legacy registered service is migrated",
+			legacyState:  v1alpha1.RegisteredServiceStateAvailable,
+			expectedName: name,
+			legacyExists: false,
+		},
+		{
+			name:         "claimed legacy registered service is kept",
+			legacyState:  v1alpha1.RegisteredServiceStateClaimed,
+			expectedName: "mydb",
+			legacyExists: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			legacy := newRegisteredService("worker", db, "mydb")
+			legacy.Status.State = tt.legacyState
+			cli := newFakeClient(t, &legacy)
+
+			rs := newRegisteredService("worker", db, name)
+			if errs := updateRegisteredService(context.Background(), cli, rs, nil); errors.Join(errs...) != nil {
+				t.Fatalf("unexpected error: %v", errs)
+			}
+
+			if err := cli.Get(context.Background(), client.ObjectKey{Namespace: rs.Namespace, Name: tt.expectedName}, &v1alpha1.RegisteredService{}); err != nil {
+				t.Errorf("expected registered service %s to exist, got %v", tt.expectedName, err)
+			}
+			err := cli.Get(context.Background(), client.ObjectKeyFromObject(&legacy), &v1alpha1.RegisteredService{})
+			if tt.legacyExists && err != nil {
+				t.Errorf("expected legacy registered service to exist, got %v", err)
+			}
+			if !tt.legacyExists && !apierrors.IsNotFound(err) {
+				t.Errorf("expected legacy registered service to be deleted, got %v", err)
+			}
+		})
+	}
+}
+
+func TestDeleteRegisteredServiceRemovesLegacy(t *testing.T) {
+	db := newResource("db.example.com/v1", "Database", "services", "mydb")
+	legacy := newRegisteredService("worker", db, "mydb")
+	rs := newRegisteredService("worker", db, registeredServiceName("worker", db))
+	cli := newFakeClient(t, &legacy, rs.DeepCopy())
+
+	if errs := deleteRegisteredService(context.Background(), cli, rs, nil); errors.Join(errs...) != nil {
+		t.Fatalf("unexpected error: %v", errs)
+	}
+	for _, n := range []string{legacy.Name, rs.Name} {
+		err := cli.Get(context.Background(), client.ObjectKey{Namespace: rs.Namespace, Name: n}, &v1alpha1.RegisteredService{})
+		if !apierrors.IsNotFound(err) {
+			t.Errorf("expected registered service %s to be deleted, got %v", n, err)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package svc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/primaza/primaza/api/v1alpha1"
+	"github.com/primaza/primaza/pkg/primaza/constants"
+)
+
+const serviceProvisioningFinalizer = "serviceprovisionings.primaza.io/finalizer"
+
+// ServiceProvisioningReconciler reconciles a ServiceProvisioning object
+type ServiceProvisioningReconciler struct {
+	client.Client
+}
+
+func NewServiceProvisioningReconciler(mgr ctrl.Manager) *ServiceProvisioningReconciler {
+	return &ServiceProvisioningReconciler{
+		Client: mgr.GetClient(),
+	}
+}
+
+// Reconcile creates the resource described by the ServiceProvisioning's
+// manifest, and deletes it according to the reclaim policy when the
+// ServiceProvisioning is deleted
+func (r *ServiceProvisioningReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := log.FromContext(ctx).WithValues("namespace", req.Namespace, "name", req.Name)
+	l.Info("Reconciling service provisioning")
+
+	sp := v1alpha1.ServiceProvisioning{}
+	if err := r.Get(ctx, req.NamespacedName, &sp); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !sp.DeletionTimestamp.IsZero() {
+		if !controllerutil.ContainsFinalizer(&sp, serviceProvisioningFinalizer) {
+			return ctrl.Result{}, nil
+		}
+		if err := r.reclaim(ctx, sp); err != nil {
+			l.Error(err, "Failed to reclaim provisioned resource")
+			return ctrl.Result{}, err
+		}
+		controllerutil.RemoveFinalizer(&sp, serviceProvisioningFinalizer)
+		return ctrl.Result{}, r.Update(ctx, &sp)
+	}
+
+	if controllerutil.AddFinalizer(&sp, serviceProvisioningFinalizer) {
+		if err := r.Update(ctx, &sp); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	resource, err := r.provision(ctx, sp)
+	if err != nil {
+		l.Error(err, "Failed to provision resource")
+		sp.Status.State = v1alpha1.ServiceProvisioningStateFailed
+		sp.Status.Message = err.Error()
+	} else {
+		l.Info("Provisioned resource", "resource", resource)
+		sp.Status.State = v1alpha1.ServiceProvisioningStateProvisioned
+		sp.Status.Message = ""
+		sp.Status.Resource = resource
+	}
+	if uerr := r.Status().Update(ctx, &sp); uerr != nil {
+		return ctrl.Result{}, uerr
+	}
+	return ctrl.Result{}, err
+}
+
+// manifest returns the resource to provision. Namespaced resources are
+// forced into the ServiceProvisioning's namespace.
+func manifest(sp v1alpha1.ServiceProvisioning, mapper meta.RESTMapper) (*unstructured.Unstructured, error) {
+	u := &unstructured.Unstructured{}
+	if err := json.Unmarshal(sp.Spec.Manifest.Raw, &u.Object); err != nil {
+		return nil, err
+	}
+
+	gvk := u.GroupVersionKind()
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, err
+	}
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		u.SetNamespace(sp.Namespace)
+	} else {
+		u.SetNamespace("")
+	}
+	if u.GetName() == "" {
+		u.SetName(sp.Name)
+	}
+	a := u.GetAnnotations()
+	if a == nil {
+		a = map[string]string{}
+	}
+	a[constants.ServiceProvisioningAnnotation] = sp.Name
+	a[constants.ServiceProvisioningClaimIDAnnotation] = sp.Spec.ClaimID
+	u.SetAnnotations(a)
+	return u, nil
+}
+
+// provision creates the resource described by the ServiceProvisioning's
+// manifest, if it does not exist yet
+func (r *ServiceProvisioningReconciler) provision(ctx context.Context, sp v1alpha1.ServiceProvisioning) (*corev1.ObjectReference, error) {
+	u, err := manifest(sp, r.RESTMapper())
+	if err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(u.GroupVersionKind())
+	err = r.Get(ctx, client.ObjectKeyFromObject(u), existing)
+	switch {
+	case apierrors.IsNotFound(err):
+		if err := r.Create(ctx, u); err != nil {
+			return nil, err
+		}
+		existing = u
+	case err != nil:
+		return nil, err
+	case existing.GetAnnotations()[constants.ServiceProvisioningAnnotation] != sp.Name:
+		return nil, fmt.Errorf("%s %s already exists and has not been provisioned by service provisioning %s",
+			u.GetKind(), u.GetName(), sp.Name)
+	}
+
+	return &corev1.ObjectReference{
+		APIVersion: existing.GetAPIVersion(),
+		Kind:       existing.GetKind(),
+		Namespace:  existing.GetNamespace(),
+		Name:       existing.GetName(),
+		UID:        existing.GetUID(),
+	}, nil
+}
+
+// reclaim deletes the provisioned resource if the reclaim policy is Delete.
+// Retained resources are not reserved anymore for the ServiceClaim they
+// have been provisioned for.
+func (r *ServiceProvisioningReconciler) reclaim(ctx context.Context, sp v1alpha1.ServiceProvisioning) error {
+	ref := sp.Status.Resource
+	if ref == nil {
+		return nil
+	}
+
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion(ref.APIVersion)
+	u.SetKind(ref.Kind)
+	u.SetNamespace(ref.Namespace)
+	u.SetName(ref.Name)
+	if sp.Spec.ReclaimPolicy != v1alpha1.ServiceReclaimPolicyRetain {
+		return client.IgnoreNotFound(r.Delete(ctx, u, client.Preconditions{UID: &ref.UID}))
+	}
+
+	if err := r.Get(ctx, client.ObjectKeyFromObject(u), u); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	a := u.GetAnnotations()
+	if u.GetUID() != ref.UID || a[constants.ServiceProvisioningClaimIDAnnotation] == "" {
+		return nil
+	}
+	delete(a, constants.ServiceProvisioningClaimIDAnnotation)
+	u.SetAnnotations(a)
+	return r.Update(ctx, u)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ServiceProvisioningReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.ServiceProvisioning{}).
+		Complete(r)
+}
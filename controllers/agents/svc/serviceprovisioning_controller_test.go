@@ -0,0 +1,209 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package svc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/primaza/primaza/api/v1alpha1"
+	"github.com/primaza/primaza/pkg/primaza/constants"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func testRESTMapper() meta.RESTMapper {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("PersistentVolume"), meta.RESTScopeRoot)
+	return mapper
+}
+
+func TestServiceProvisioningReconcile(t *testing.T) {
+	tests := []struct {
+		name          string
+		reclaimPolicy v1alpha1.ServiceReclaimPolicy
+		deleted       bool
+	}{
+		{
+			name:          "delete reclaim policy",
+			reclaimPolicy: v1alpha1.ServiceReclaimPolicyDelete,
+			deleted:       true,
+		},
+		{
+			name:          "retain reclaim policy",
+			reclaimPolicy: v1alpha1.ServiceReclaimPolicyRetain,
+			deleted:       false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			scheme := runtime.NewScheme()
+			if err := v1alpha1.AddToScheme(scheme); err != nil {
+				t.Fatal(err)
+			}
+			if err := corev1.AddToScheme(scheme); err != nil {
+				t.Fatal(err)
+			}
+
+			sp := &v1alpha1.ServiceProvisioning{
+				ObjectMeta: metav1.ObjectMeta{Name: "claim-1234", Namespace: "services"},
+				Spec: v1alpha1.ServiceProvisioningSpec{
+					ServiceClassName: "config",
+					ClaimID:          "1234",
+					Manifest:         runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","data":{"host":"db"}}`)},
+					ReclaimPolicy:    tt.reclaimPolicy,
+				},
+			}
+			cli := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithRESTMapper(testRESTMapper()).
+				WithObjects(sp).
+				WithStatusSubresource(sp).
+				Build()
+			r := ServiceProvisioningReconciler{Client: cli}
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "services", Name: "claim-1234"}}
+
+			if _, err := r.Reconcile(ctx, req); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			cm := corev1.ConfigMap{}
+			if err := cli.Get(ctx, req.NamespacedName, &cm); err != nil {
+				t.Fatalf("expected config map to be provisioned, got %v", err)
+			}
+			if cm.Annotations[constants.ServiceProvisioningAnnotation] != sp.Name {
+				t.Errorf("expected provisioned resource to be annotated with %s", constants.ServiceProvisioningAnnotation)
+			}
+			if cm.Annotations[constants.ServiceProvisioningClaimIDAnnotation] != sp.Spec.ClaimID {
+				t.Errorf("expected provisioned resource to be annotated with %s", constants.ServiceProvisioningClaimIDAnnotation)
+			}
+			if err := cli.Get(ctx, req.NamespacedName, sp); err != nil {
+				t.Fatal(err)
+			}
+			if sp.Status.State != v1alpha1.ServiceProvisioningStateProvisioned {
+				t.Errorf("expected state %s, got %s", v1alpha1.ServiceProvisioningStateProvisioned, sp.Status.State)
+			}
+
+			if err := cli.Delete(ctx, sp); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := r.Reconcile(ctx, req); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			err := cli.Get(ctx, req.NamespacedName, &cm)
+			if tt.deleted && !apierrors.IsNotFound(err) {
+				t.Errorf("expected provisioned resource to be deleted, got %v", err)
+			}
+			if !tt.deleted && err != nil {
+				t.Errorf("expected provisioned resource to be retained, got %v", err)
+			}
+			if _, found := cm.Annotations[constants.ServiceProvisioningClaimIDAnnotation]; !tt.deleted && found {
+				t.Errorf("expected retained resource not to be reserved for the claim anymore")
+			}
+			if err := cli.Get(ctx, req.NamespacedName, sp); !apierrors.IsNotFound(err) {
+				t.Errorf("expected service provisioning to be deleted, got %v", err)
+			}
+		})
+	}
+}
+
+func TestServiceProvisioningExistingResource(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	sp := &v1alpha1.ServiceProvisioning{
+		ObjectMeta: metav1.ObjectMeta{Name: "claim-1234", Namespace: "services"},
+		Spec: v1alpha1.ServiceProvisioningSpec{
+			Manifest: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"existing"}}`)},
+		},
+	}
+	existing := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: "services"}}
+	cli := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRESTMapper(testRESTMapper()).
+		WithObjects(sp, existing).
+		WithStatusSubresource(sp).
+		Build()
+	r := ServiceProvisioningReconciler{Client: cli}
+
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(sp)}); err == nil {
+		t.Fatal("expected an error provisioning an already existing resource")
+	}
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(sp), sp); err != nil {
+		t.Fatal(err)
+	}
+	if sp.Status.State != v1alpha1.ServiceProvisioningStateFailed {
+		t.Errorf("expected state %s, got %s", v1alpha1.ServiceProvisioningStateFailed, sp.Status.State)
+	}
+}
+
+func TestServiceProvisioningClusterScopedResource(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	sp := &v1alpha1.ServiceProvisioning{
+		ObjectMeta: metav1.ObjectMeta{Name: "claim-1234", Namespace: "services"},
+		Spec: v1alpha1.ServiceProvisioningSpec{
+			ClaimID:  "1234",
+			Manifest: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"PersistentVolume","metadata":{"name":"volume"}}`)},
+		},
+	}
+	cli := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRESTMapper(testRESTMapper()).
+		WithObjects(sp).
+		WithStatusSubresource(sp).
+		Build()
+	r := ServiceProvisioningReconciler{Client: cli}
+
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(sp)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pv := corev1.PersistentVolume{}
+	if err := cli.Get(ctx, client.ObjectKey{Name: "volume"}, &pv); err != nil {
+		t.Fatalf("expected persistent volume to be provisioned, got %v", err)
+	}
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(sp), sp); err != nil {
+		t.Fatal(err)
+	}
+	if sp.Status.Resource == nil || sp.Status.Resource.Namespace != "" {
+		t.Errorf("expected cluster-scoped resource reference, got %v", sp.Status.Resource)
+	}
+}
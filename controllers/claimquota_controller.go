@@ -0,0 +1,119 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	primazaiov1alpha1 "github.com/primaza/primaza/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+//+kubebuilder:rbac:groups=primaza.io,namespace=system,resources=claimquotas,verbs=get;list;watch
+//+kubebuilder:rbac:groups=primaza.io,namespace=system,resources=claimquotas/status,verbs=get;update;patch
+
+// ClaimQuotaReconciler reconciles a ClaimQuota object.
+// It reports the number of ServiceClaims counting against the quota, which
+// is enforced by the ServiceClaim webhook and the ServiceClaimReconciler.
+type ClaimQuotaReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+func (r *ClaimQuotaReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+	l.Info("Reconcile ClaimQuota")
+
+	var q primazaiov1alpha1.ClaimQuota
+	if err := r.Get(ctx, req.NamespacedName, &q); err != nil {
+		l.Info("unable to retrieve ClaimQuota", "error", err)
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	claims, environments, err := listQuotaServiceClaims(ctx, r.Client, q.Namespace)
+	if err != nil {
+		l.Error(err, "unable to list the ServiceClaims")
+		return ctrl.Result{}, err
+	}
+
+	used := len(q.CountedClaims(claims, environments))
+	if q.Status.Hard == q.Spec.Hard && q.Status.Used == used {
+		return ctrl.Result{}, nil
+	}
+	q.Status.Hard = q.Spec.Hard
+	q.Status.Used = used
+	if err := r.Status().Update(ctx, &q); err != nil {
+		l.Error(err, "unable to update the ClaimQuota status")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// listQuotaServiceClaims returns the ServiceClaims of the namespace, along
+// with the environment of each ClusterEnvironment by name, to count the
+// ServiceClaims against the ClaimQuotas
+func listQuotaServiceClaims(ctx context.Context, cli client.Client, namespace string) ([]primazaiov1alpha1.ServiceClaim, map[string]string, error) {
+	var scl primazaiov1alpha1.ServiceClaimList
+	if err := cli.List(ctx, &scl, client.InNamespace(namespace)); err != nil {
+		return nil, nil, err
+	}
+	var cel primazaiov1alpha1.ClusterEnvironmentList
+	if err := cli.List(ctx, &cel, client.InNamespace(namespace)); err != nil {
+		return nil, nil, err
+	}
+	environments := make(map[string]string, len(cel.Items))
+	for _, ce := range cel.Items {
+		environments[ce.Name] = ce.Spec.EnvironmentName
+	}
+	return scl.Items, environments, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ClaimQuotaReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// any change to the ServiceClaims of the namespace, or to the
+	// environment of its ClusterEnvironments, may change the usage of its
+	// ClaimQuotas
+	reconcileNamespaceClaimQuotas := func(ctx context.Context, a client.Object) []reconcile.Request {
+		l := log.FromContext(ctx)
+
+		var cql primazaiov1alpha1.ClaimQuotaList
+		if err := r.List(ctx, &cql, client.InNamespace(a.GetNamespace())); err != nil {
+			l.Error(err, "unable to list the ClaimQuotas to reconcile", "object", a.GetName())
+			return []reconcile.Request{}
+		}
+
+		rr := make([]reconcile.Request, 0, len(cql.Items))
+		for _, q := range cql.Items {
+			rr = append(rr, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: q.Namespace, Name: q.Name}})
+		}
+		return rr
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&primazaiov1alpha1.ClaimQuota{}).
+		Watches(&primazaiov1alpha1.ServiceClaim{},
+			handler.EnqueueRequestsFromMapFunc(reconcileNamespaceClaimQuotas)).
+		Watches(&primazaiov1alpha1.ClusterEnvironment{},
+			handler.EnqueueRequestsFromMapFunc(reconcileNamespaceClaimQuotas)).
+		Complete(r)
+}
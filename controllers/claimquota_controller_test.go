@@ -0,0 +1,129 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/primaza/primaza/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("ClaimQuota", func() {
+	ctx := context.Background()
+	nn := types.NamespacedName{Namespace: "primaza-system", Name: "quota"}
+	created := time.Now().Add(-time.Hour)
+
+	newQuota := func(hard int, environments ...string) *v1alpha1.ClaimQuota {
+		return &v1alpha1.ClaimQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: nn.Name, Namespace: nn.Namespace},
+			Spec:       v1alpha1.ClaimQuotaSpec{Hard: hard, Environments: environments},
+		}
+	}
+	// newServiceClaim returns the i-th ServiceClaim, created a minute after
+	// the previous one
+	newServiceClaim := func(i int, target v1alpha1.ServiceClaimTarget, state v1alpha1.ServiceClaimState) *v1alpha1.ServiceClaim {
+		return &v1alpha1.ServiceClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              fmt.Sprintf("claim-%d", i),
+				Namespace:         nn.Namespace,
+				CreationTimestamp: metav1.NewTime(created.Add(time.Duration(i) * time.Minute)),
+			},
+			Spec: v1alpha1.ServiceClaimSpec{
+				ServiceClassIdentity:          []v1alpha1.ServiceClassIdentityItem{{Name: "type", Value: "psql"}},
+				ServiceEndpointDefinitionKeys: []string{"host"},
+				Target:                        &target,
+			},
+			Status: v1alpha1.ServiceClaimStatus{State: state, ClaimID: fmt.Sprintf("claim-%d", i)},
+		}
+	}
+	prod := v1alpha1.ServiceClaimTarget{EnvironmentTag: "prod"}
+	worker := v1alpha1.ServiceClaimTarget{ApplicationClusterContext: &v1alpha1.ServiceClaimApplicationClusterContext{
+		ClusterEnvironmentName: "worker",
+		Namespace:              "applications",
+	}}
+	ce := &v1alpha1.ClusterEnvironment{
+		ObjectMeta: metav1.ObjectMeta{Name: "worker", Namespace: nn.Namespace},
+		Spec:       v1alpha1.ClusterEnvironmentSpec{EnvironmentName: "dev"},
+	}
+	newClient := func(objs ...client.Object) client.Client {
+		scheme := runtime.NewScheme()
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		return fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(objs...).
+			WithStatusSubresource(objs...).
+			Build()
+	}
+
+	DescribeTable("reporting the usage",
+		func(quota *v1alpha1.ClaimQuota, expected int) {
+			cli := newClient(quota, ce,
+				newServiceClaim(0, prod, v1alpha1.ServiceClaimStateResolved),
+				newServiceClaim(1, prod, v1alpha1.ServiceClaimStatePending),
+				newServiceClaim(2, prod, v1alpha1.ServiceClaimStateRejected),
+				newServiceClaim(3, worker, v1alpha1.ServiceClaimStateResolved))
+			r := ClaimQuotaReconciler{Client: cli, Scheme: cli.Scheme()}
+
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: nn})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(cli.Get(ctx, nn, quota)).To(Succeed())
+			Expect(quota.Status.Hard).To(Equal(quota.Spec.Hard))
+			Expect(quota.Status.Used).To(Equal(expected))
+		},
+		Entry("all environments", newQuota(5), 3),
+		Entry("environment tag", newQuota(5, "prod"), 2),
+		Entry("cluster environment", newQuota(5, "dev"), 1),
+		Entry("no matching environment", newQuota(5, "stage"), 0),
+	)
+
+	DescribeTable("enforcing the quota",
+		func(quota *v1alpha1.ClaimQuota, claim int, exceeded bool) {
+			claims := []*v1alpha1.ServiceClaim{
+				newServiceClaim(0, prod, v1alpha1.ServiceClaimStateResolved),
+				newServiceClaim(1, worker, v1alpha1.ServiceClaimStatePending),
+				newServiceClaim(2, prod, v1alpha1.ServiceClaimStatePending),
+				newServiceClaim(3, prod, v1alpha1.ServiceClaimStatePending),
+			}
+			cli := newClient(quota, ce, claims[0], claims[1], claims[2], claims[3])
+			r := ServiceClaimReconciler{Client: cli, Scheme: cli.Scheme()}
+
+			q, err := r.exceededClaimQuota(ctx, *claims[claim])
+			Expect(err).NotTo(HaveOccurred())
+			if exceeded {
+				Expect(q).NotTo(BeNil())
+				Expect(q.Name).To(Equal(nn.Name))
+			} else {
+				Expect(q).To(BeNil())
+			}
+		},
+		Entry("oldest claims within the quota", newQuota(2, "prod"), 2, false),
+		Entry("newest claims exceed the quota", newQuota(2, "prod"), 3, true),
+		Entry("claims out of the quota scope", newQuota(1, "prod"), 1, false),
+		Entry("quota counting all environments", newQuota(2), 2, true),
+	)
+})
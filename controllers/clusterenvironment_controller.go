@@ -33,9 +33,14 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/primaza/primaza/api/v1alpha1"
 	primazaiov1alpha1 "github.com/primaza/primaza/api/v1alpha1"
@@ -188,6 +193,14 @@ func (r *ClusterEnvironmentReconciler) Reconcile(ctx context.Context, req ctrl.R
 		return ctrl.Result{}, err
 	}
 
+	// report whether the environment is declared
+	ec, err := environmentKnownCondition(ctx, r.Client, ce.Namespace, ce.Spec.EnvironmentName)
+	if err != nil {
+		l.Error(err, "error retrieving environment", "environment", ce.Spec.EnvironmentName)
+		return ctrl.Result{}, err
+	}
+	meta.SetStatusCondition(&ce.Status.Conditions, ec)
+
 	if err := r.Client.Status().Update(ctx, ce); err != nil {
 		l.Error(err, "error updating cluster environment status", "status", ce.Status)
 		return ctrl.Result{}, err
@@ -444,7 +457,7 @@ func (r *ClusterEnvironmentReconciler) reconcileServiceNamespaces(ctx context.Co
 	var serviceclassFilteredList []primazaiov1alpha1.ServiceClass
 	for _, serviceclass := range serviceclassesList.Items {
 		if serviceclass.Spec.Constraints != nil &&
-			envtag.Match(ce.Spec.EnvironmentName, serviceclass.Spec.GetEnvironmentConstraints()) {
+			envtag.MatchEnvironment(environmentOf(*ce), serviceclass.Spec.GetEnvironmentConstraints()) {
 			serviceclassFilteredList = append(serviceclassFilteredList, serviceclass)
 		}
 	}
@@ -500,7 +513,7 @@ func (r *ClusterEnvironmentReconciler) reconcileServiceBindingApplicationNamespa
 		if sclaim.Spec.Target.EnvironmentTag == "" {
 			cc := sclaim.Spec.Target.ApplicationClusterContext
 			if cc != nil && ce.Name == cc.ClusterEnvironmentName {
-				if err := controlplane.PushServiceBinding(ctx, &sclaim, secret, r.Scheme, r.Client, &cc.Namespace, applicationNamespaces, cfg); err != nil {
+				if _, err := controlplane.PushServiceBinding(ctx, &sclaim, secret, r.Scheme, r.Client, &cc.Namespace, applicationNamespaces, cfg); err != nil {
 					errs = append(errs, err)
 				}
 			}
@@ -512,7 +525,7 @@ func (r *ClusterEnvironmentReconciler) reconcileServiceBindingApplicationNamespa
 			}
 
 			l.Info("cluster environment is matching environment", "cluster environment", ce, "environment tag", sclaim.Spec.Target.EnvironmentTag)
-			if err := controlplane.PushServiceBinding(ctx, &sclaim, secret, r.Scheme, r.Client, nil, applicationNamespaces, cfg); err != nil {
+			if _, err := controlplane.PushServiceBinding(ctx, &sclaim, secret, r.Scheme, r.Client, nil, applicationNamespaces, cfg); err != nil {
 				errs = append(errs, err)
 			}
 		}
@@ -802,18 +815,29 @@ func (r *ClusterEnvironmentReconciler) finalizeClusterEnvironmentInNamespaces(ct
 		return err
 	}
 
+	return removeClusterEnvironmentAgents(ctx, kcfg, ce, r.config)
+}
+
+// removeClusterEnvironmentAgents removes the agents, and the permissions
+// granted to them, from the namespaces of the ClusterEnvironment's cluster
+func removeClusterEnvironmentAgents(
+	ctx context.Context,
+	kcfg *rest.Config,
+	ce *primazaiov1alpha1.ClusterEnvironment,
+	config ClusterEnvironmentReconcilerConfig,
+) error {
 	s := controlplane.ClusterEnvironmentState{
 		Name:                   ce.Name,
 		Namespace:              ce.Namespace,
 		ClusterConfig:          kcfg,
 		ApplicationNamespaces:  []string{},
 		ServiceNamespaces:      []string{},
-		AppAgentImage:          r.config.AppAgentImage,
-		SvcAgentImage:          r.config.SvcAgentImage,
-		AppAgentManifest:       r.config.AppAgentManifest,
-		SvcAgentManifest:       r.config.SvcAgentManifest,
-		AppAgentConfigManifest: r.config.AppAgentConfigManifest,
-		SvcAgentConfigManifest: r.config.SvcAgentConfigManifest,
+		AppAgentImage:          config.AppAgentImage,
+		SvcAgentImage:          config.SvcAgentImage,
+		AppAgentManifest:       config.AppAgentManifest,
+		SvcAgentManifest:       config.SvcAgentManifest,
+		AppAgentConfigManifest: config.AppAgentConfigManifest,
+		SvcAgentConfigManifest: config.SvcAgentConfigManifest,
 		Strategy:               ce.Spec.SynchronizationStrategy,
 	}
 
@@ -827,8 +851,38 @@ func (r *ClusterEnvironmentReconciler) finalizeClusterEnvironmentInNamespaces(ct
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *ClusterEnvironmentReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// the environment a ClusterEnvironment refers to may be declared or
+	// removed after the ClusterEnvironment
+	reconcileOnEnvironmentChange := func(ctx context.Context, a client.Object) []reconcile.Request {
+		l := log.FromContext(ctx)
+
+		cel := primazaiov1alpha1.ClusterEnvironmentList{}
+		if err := r.List(ctx, &cel, client.InNamespace(a.GetNamespace())); err != nil {
+			l.Error(err, "unable to list the ClusterEnvironments and reconcile for Environment changes", "environment", a.GetName())
+			return []reconcile.Request{}
+		}
+
+		rr := []reconcile.Request{}
+		for _, ce := range r.filterClusterEnvironments(a.GetName(), cel.Items) {
+			rr = append(rr, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: ce.Namespace, Name: ce.Name}})
+		}
+		return rr
+	}
+	environmentDeclaredPred := predicate.Funcs{
+		UpdateFunc: func(event.UpdateEvent) bool { return false },
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&primazaiov1alpha1.ClusterEnvironment{}).
 		Owns(&corev1.Secret{}).
+		Watches(&primazaiov1alpha1.Environment{},
+			handler.EnqueueRequestsFromMapFunc(reconcileOnEnvironmentChange),
+			builder.WithPredicates(environmentDeclaredPred)).
 		Complete(r)
 }
+
+// environmentOf returns the environment constraints are matched against for
+// the ClusterEnvironment
+func environmentOf(ce primazaiov1alpha1.ClusterEnvironment) envtag.Environment {
+	return envtag.Environment{Name: ce.Spec.EnvironmentName, Labels: ce.Spec.Labels}
+}
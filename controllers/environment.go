@@ -0,0 +1,58 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	primazaiov1alpha1 "github.com/primaza/primaza/api/v1alpha1"
+	"github.com/primaza/primaza/pkg/primaza/constants"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//+kubebuilder:rbac:groups=primaza.io,namespace=system,resources=environments,verbs=get;list;watch
+//+kubebuilder:rbac:groups=primaza.io,namespace=system,resources=environments/status,verbs=get;update;patch
+
+// environmentKnownCondition builds the condition reporting whether the
+// environment is declared by an Environment resource in the namespace.
+// Environments are not mandatory: an unknown environment does not prevent
+// the resource from being processed, but it is likely to be a typo.
+func environmentKnownCondition(ctx context.Context, cli client.Reader, namespace, environment string) (metav1.Condition, error) {
+	var env primazaiov1alpha1.Environment
+	if err := cli.Get(ctx, types.NamespacedName{Namespace: namespace, Name: environment}, &env); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return metav1.Condition{}, err
+		}
+		return metav1.Condition{
+			Type:    primazaiov1alpha1.EnvironmentKnownCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  constants.UnknownEnvironmentReason,
+			Message: fmt.Sprintf("environment '%s' is not declared by any Environment", environment),
+		}, nil
+	}
+
+	return metav1.Condition{
+		Type:    primazaiov1alpha1.EnvironmentKnownCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  constants.EnvironmentFoundReason,
+		Message: fmt.Sprintf("environment '%s' is declared", environment),
+	}, nil
+}
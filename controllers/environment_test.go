@@ -0,0 +1,110 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/primaza/primaza/api/v1alpha1"
+	"github.com/primaza/primaza/pkg/primaza/constants"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Environments", func() {
+	newEnvironment := func() *v1alpha1.Environment {
+		return &v1alpha1.Environment{
+			ObjectMeta: metav1.ObjectMeta{Name: "stage", Namespace: "primaza-system"},
+			Spec:       v1alpha1.EnvironmentSpec{Order: 1},
+		}
+	}
+	newClient := func(objs ...client.Object) client.Client {
+		scheme := runtime.NewScheme()
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		return fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(objs...).
+			WithStatusSubresource(&v1alpha1.Environment{}).
+			Build()
+	}
+
+	DescribeTable("reporting whether the environment is known",
+		func(objs []client.Object, status metav1.ConditionStatus, reason string) {
+			c, err := environmentKnownCondition(context.Background(), newClient(objs...), "primaza-system", "stage")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c.Type).To(Equal(v1alpha1.EnvironmentKnownCondition))
+			Expect(c.Status).To(Equal(status))
+			Expect(c.Reason).To(Equal(reason))
+		},
+		Entry("declared environment", []client.Object{newEnvironment()}, metav1.ConditionTrue, constants.EnvironmentFoundReason),
+		Entry("unknown environment", []client.Object{}, metav1.ConditionFalse, constants.UnknownEnvironmentReason),
+	)
+
+	DescribeTable("refreshing the Environment's status",
+		func(objs []client.Object, expectedClusterEnvironments []string, expectedCatalog metav1.ConditionStatus) {
+			cli := newClient(append(objs, newEnvironment())...)
+			r := ServiceCatalogReconciler{Client: cli, Scheme: cli.Scheme()}
+
+			nn := types.NamespacedName{Namespace: "primaza-system", Name: "stage"}
+			_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: nn})
+			Expect(err).NotTo(HaveOccurred())
+
+			env := v1alpha1.Environment{}
+			Expect(cli.Get(context.Background(), nn, &env)).To(Succeed())
+			if expectedClusterEnvironments == nil {
+				Expect(env.Status.ClusterEnvironments).To(BeEmpty())
+			} else {
+				Expect(env.Status.ClusterEnvironments).To(Equal(expectedClusterEnvironments))
+			}
+			Expect(meta.IsStatusConditionPresentAndEqual(env.Status.Conditions, v1alpha1.EnvironmentCatalogReadyCondition, expectedCatalog)).To(BeTrue())
+		},
+		Entry("no ClusterEnvironment nor ServiceCatalog",
+			[]client.Object{},
+			nil,
+			metav1.ConditionFalse),
+		Entry("ClusterEnvironments before the ServiceCatalog is created",
+			[]client.Object{
+				&v1alpha1.ClusterEnvironment{
+					ObjectMeta: metav1.ObjectMeta{Name: "worker-b", Namespace: "primaza-system"},
+					Spec:       v1alpha1.ClusterEnvironmentSpec{EnvironmentName: "stage"},
+				},
+				&v1alpha1.ClusterEnvironment{
+					ObjectMeta: metav1.ObjectMeta{Name: "worker-a", Namespace: "primaza-system"},
+					Spec:       v1alpha1.ClusterEnvironmentSpec{EnvironmentName: "stage"},
+				},
+				&v1alpha1.ClusterEnvironment{
+					ObjectMeta: metav1.ObjectMeta{Name: "worker-prod", Namespace: "primaza-system"},
+					Spec:       v1alpha1.ClusterEnvironmentSpec{EnvironmentName: "prod"},
+				},
+			},
+			[]string{"worker-a", "worker-b"},
+			metav1.ConditionFalse),
+		Entry("existing ServiceCatalog",
+			[]client.Object{
+				&v1alpha1.ServiceCatalog{ObjectMeta: metav1.ObjectMeta{Name: "stage", Namespace: "primaza-system"}},
+			},
+			nil,
+			metav1.ConditionTrue),
+	)
+})
@@ -43,9 +43,11 @@ type RegisteredServiceReconciler struct {
 	Scheme *runtime.Scheme
 }
 
+// ServiceInCatalog returns the index of the tenant's own service with the
+// given name in the ServiceCatalog, or -1 if it is not in the catalog
 func ServiceInCatalog(sc primazaiov1alpha1.ServiceCatalog, serviceName string) int {
 	for i, service := range sc.Spec.Services {
-		if service.Name == serviceName {
+		if service.Name == serviceName && service.GrantedBy == "" {
 			return i
 		}
 	}
@@ -160,21 +162,26 @@ func (r *RegisteredServiceReconciler) reconcileCatalogs(ctx context.Context, rs
 	return errors.Join(errs...)
 }
 
-func (r *RegisteredServiceReconciler) addServiceToCatalog(ctx context.Context, sc primazaiov1alpha1.ServiceCatalog, rs primazaiov1alpha1.RegisteredService) error {
-	log := log.FromContext(ctx)
-
+// catalogService returns the ServiceCatalog entry of the RegisteredService
+func catalogService(rs primazaiov1alpha1.RegisteredService) primazaiov1alpha1.ServiceCatalogService {
 	// Extracting Keys of SED
 	sedKeys := make([]string, 0, len(rs.Spec.ServiceEndpointDefinition))
 	for i := 0; i < len(rs.Spec.ServiceEndpointDefinition); i++ {
 		sedKeys = append(sedKeys, rs.Spec.ServiceEndpointDefinition[i].Name)
 	}
 
-	// Initializing Service Catalog Service
-	scs := primazaiov1alpha1.ServiceCatalogService{
+	return primazaiov1alpha1.ServiceCatalogService{
 		Name:                          rs.Name,
 		ServiceClassIdentity:          rs.Spec.ServiceClassIdentity,
 		ServiceEndpointDefinitionKeys: sedKeys,
 	}
+}
+
+func (r *RegisteredServiceReconciler) addServiceToCatalog(ctx context.Context, sc primazaiov1alpha1.ServiceCatalog, rs primazaiov1alpha1.RegisteredService) error {
+	log := log.FromContext(ctx)
+
+	// Initializing Service Catalog Service
+	scs := catalogService(rs)
 
 	if ServiceInCatalog(sc, scs.Name) == -1 {
 		log.Info("Updating Service Catalog")
@@ -314,7 +321,7 @@ func (r *RegisteredServiceReconciler) handleHealthcheck(ctx context.Context, rs
 	if completed {
 		// only keep it in 'Claimed' if the healthcheck succeeded
 		if rs.Status.State != primazaiov1alpha1.RegisteredServiceStateClaimed {
-			rs.Status.State = primazaiov1alpha1.RegisteredServiceStateAvailable
+			rs.Status.State = availableOrClaimed(rs)
 		}
 	} else if failed {
 		rs.Status.State = primazaiov1alpha1.RegisteredServiceStateUnreachable
@@ -325,6 +332,15 @@ func (r *RegisteredServiceReconciler) handleHealthcheck(ctx context.Context, rs
 	return r.registerHealthcheck(ctx, rs)
 }
 
+// availableOrClaimed returns the state of a reachable RegisteredService,
+// given the ServiceClaims holding it
+func availableOrClaimed(rs *primazaiov1alpha1.RegisteredService) primazaiov1alpha1.RegisteredServiceState {
+	if rs.HasCapacity() {
+		return primazaiov1alpha1.RegisteredServiceStateAvailable
+	}
+	return primazaiov1alpha1.RegisteredServiceStateClaimed
+}
+
 func (r *RegisteredServiceReconciler) cleanupHealthchecks(ctx context.Context, rs *primazaiov1alpha1.RegisteredService) error {
 	cronjobList := batchv1.CronJobList{}
 	err := r.List(ctx, &cronjobList, client.InNamespace(rs.Namespace))
@@ -415,7 +431,7 @@ func (r *RegisteredServiceReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		// Available or Claimed.  Explicitly set to Available if not Claimed,
 		// since this also lets us clean up healthcheck removal.
 		if rs.Status.State != primazaiov1alpha1.RegisteredServiceStateClaimed {
-			rs.Status.State = primazaiov1alpha1.RegisteredServiceStateAvailable
+			rs.Status.State = availableOrClaimed(&rs)
 		}
 	}
 
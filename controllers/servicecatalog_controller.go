@@ -20,16 +20,27 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 
 	"github.com/primaza/primaza/api/v1alpha1"
 	primazaiov1alpha1 "github.com/primaza/primaza/api/v1alpha1"
+	"github.com/primaza/primaza/pkg/envtag"
 	"github.com/primaza/primaza/pkg/primaza/clustercontext"
+	"github.com/primaza/primaza/pkg/primaza/constants"
 	"github.com/primaza/primaza/pkg/primaza/controlplane"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 //+kubebuilder:rbac:groups=primaza.io,namespace=system,resources=servicecatalogs,verbs=get;list;watch;create;update;patch;delete
@@ -40,23 +51,23 @@ import (
 type ServiceCatalogReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+
+	// APIReader reads the resources other tenants grant, which are not
+	// cached
+	APIReader client.Reader
 }
 
+// Reconcile pushes the ServiceCatalog to the application namespaces of the
+// environment's ClusterEnvironments. ServiceCatalogs are named after the
+// environment they belong to, so that the request also identifies the
+// Environment, whose status is refreshed if it is declared.
 func (r *ServiceCatalogReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	l := log.FromContext(ctx)
 	l.Info("Reconcile Service Catalog")
 
-	// first, get the service catalog
-	serviceCatalog := v1alpha1.ServiceCatalog{}
-	err := r.Get(ctx, req.NamespacedName, &serviceCatalog)
+	env, err := r.getEnvironment(ctx, req.NamespacedName)
 	if err != nil {
-		l.Error(err, "Failed to retrieve ServiceCatalog")
-		if apierrors.IsNotFound(err) {
-			// nothing to do as it means no ClusterEnvironment
-			// exists for deleted ServiceCatalog
-			return ctrl.Result{}, nil
-		}
-
+		l.Error(err, "Failed to retrieve Environment")
 		return ctrl.Result{}, err
 	}
 
@@ -67,20 +78,133 @@ func (r *ServiceCatalogReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		l.Error(err, "Error on listing clusterenvironment")
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
+	cee := []v1alpha1.ClusterEnvironment{}
+	for _, ce := range clusterEnvironmentList.Items {
+		if ce.Spec.EnvironmentName == req.Name && !ce.HasDeletionTimestamp() {
+			cee = append(cee, ce)
+		}
+	}
+
+	// first, get the service catalog
+	serviceCatalog := v1alpha1.ServiceCatalog{}
+	if err := r.Get(ctx, req.NamespacedName, &serviceCatalog); err != nil {
+		if apierrors.IsNotFound(err) {
+			// nothing to push as it means no ClusterEnvironment
+			// exists for deleted ServiceCatalog
+			return ctrl.Result{}, r.updateEnvironmentStatus(ctx, env, false, cee)
+		}
+
+		l.Error(err, "Failed to retrieve ServiceCatalog")
+		return ctrl.Result{}, err
+	}
+
+	// the ServiceCatalog is updated, and pushed on the next reconciliation,
+	// when the services granted by other tenants change
+	updated, err := r.updateGrantedServices(ctx, &serviceCatalog)
+	if err != nil || updated {
+		return ctrl.Result{}, err
+	}
 
 	var errorList []error
-	for index := range clusterEnvironmentList.Items {
-		ce := clusterEnvironmentList.Items[index]
-		if ce.Spec.EnvironmentName == serviceCatalog.Name {
-			if err := r.PushServiceCatalog(ctx, serviceCatalog, ce); err != nil {
-				l.Error(err, fmt.Sprintf("ServiceCatalog:%v failed to be pushed to application namespaces of Cluster Envoronment:%v ", serviceCatalog, ce.Name))
-				errorList = append(errorList, err)
-			}
+	for _, ce := range cee {
+		if err := r.PushServiceCatalog(ctx, serviceCatalog, ce); err != nil {
+			l.Error(err, fmt.Sprintf("ServiceCatalog:%v failed to be pushed to application namespaces of Cluster Envoronment:%v ", serviceCatalog, ce.Name))
+			errorList = append(errorList, err)
 		}
 	}
+	errorList = append(errorList, r.updateEnvironmentStatus(ctx, env, true, cee))
 	return ctrl.Result{}, errors.Join(errorList...)
 }
 
+// updateGrantedServices updates the ServiceCatalog's services granted by
+// other tenants. Like the tenant's own RegisteredServices, the granted ones
+// are listed while they are Available and match the environment. It returns
+// whether the ServiceCatalog has been updated.
+func (r *ServiceCatalogReconciler) updateGrantedServices(ctx context.Context, sc *v1alpha1.ServiceCatalog) (bool, error) {
+	var reader client.Reader = r.Client
+	if r.APIReader != nil {
+		reader = r.APIReader
+	}
+	rss, err := grantedRegisteredServices(ctx, r.Client, reader, sc.Namespace)
+	if err != nil {
+		return false, err
+	}
+
+	granted := []v1alpha1.ServiceCatalogService{}
+	for _, rs := range rss {
+		if rs.Status.State == v1alpha1.RegisteredServiceStateAvailable &&
+			envtag.Match(sc.Name, rs.Spec.GetEnvironmentConstraints()) {
+			s := catalogService(rs)
+			s.GrantedBy = rs.Namespace
+			granted = append(granted, s)
+		}
+	}
+
+	services := []v1alpha1.ServiceCatalogService{}
+	current := []v1alpha1.ServiceCatalogService{}
+	for _, s := range sc.Spec.Services {
+		if s.GrantedBy == "" {
+			services = append(services, s)
+		} else {
+			current = append(current, s)
+		}
+	}
+	if equality.Semantic.DeepEqual(current, granted) {
+		return false, nil
+	}
+
+	sc.Spec.Services = append(services, granted...)
+	return true, r.Update(ctx, sc)
+}
+
+// getEnvironment returns the Environment with the given name, or nil if the
+// environment is not declared
+func (r *ServiceCatalogReconciler) getEnvironment(ctx context.Context, nn types.NamespacedName) (*v1alpha1.Environment, error) {
+	env := v1alpha1.Environment{}
+	if err := r.Get(ctx, nn, &env); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &env, nil
+}
+
+// updateEnvironmentStatus records the Environment's ClusterEnvironments and
+// whether its ServiceCatalog exists
+func (r *ServiceCatalogReconciler) updateEnvironmentStatus(
+	ctx context.Context,
+	env *v1alpha1.Environment,
+	catalogFound bool,
+	cee []v1alpha1.ClusterEnvironment,
+) error {
+	if env == nil || !env.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	names := make([]string, 0, len(cee))
+	for _, ce := range cee {
+		names = append(names, ce.Name)
+	}
+	sort.Strings(names)
+	env.Status.ClusterEnvironments = names
+
+	c := metav1.Condition{
+		Type:    v1alpha1.EnvironmentCatalogReadyCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  constants.ServiceCatalogFoundReason,
+		Message: fmt.Sprintf("ServiceCatalog '%s' exists", env.Name),
+	}
+	if !catalogFound {
+		c.Status = metav1.ConditionFalse
+		c.Reason = constants.ServiceCatalogNotFoundReason
+		c.Message = "no ServiceCatalog exists yet, as no ClusterEnvironment is associated to the environment"
+	}
+	meta.SetStatusCondition(&env.Status.Conditions, c)
+
+	return r.Status().Update(ctx, env)
+}
+
 func (r *ServiceCatalogReconciler) PushServiceCatalog(ctx context.Context, serviceCatalog v1alpha1.ServiceCatalog, ce v1alpha1.ClusterEnvironment) error {
 	l := log.FromContext(ctx)
 	cfg, err := clustercontext.GetClusterRESTConfig(ctx, r.Client, ce.Namespace, ce.Spec.ClusterContextSecret)
@@ -97,7 +221,43 @@ func (r *ServiceCatalogReconciler) PushServiceCatalog(ctx context.Context, servi
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *ServiceCatalogReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// the ServiceCatalog of an environment is pushed to the ClusterEnvironments
+	// associated to it, and it is reported in the Environment's status
+	reconcileOnClusterEnvironmentChange := func(ctx context.Context, a client.Object) []reconcile.Request {
+		ce, ok := a.(*primazaiov1alpha1.ClusterEnvironment)
+		if !ok {
+			return []reconcile.Request{}
+		}
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: ce.Namespace, Name: ce.Spec.EnvironmentName}}}
+	}
+	// the services granted by other tenants are published in the tenant's
+	// namespace as ServiceGrantReferences
+	reconcileOnServiceGrantReferenceChange := func(ctx context.Context, a client.Object) []reconcile.Request {
+		l := log.FromContext(ctx)
+
+		var scl primazaiov1alpha1.ServiceCatalogList
+		if err := r.List(ctx, &scl, client.InNamespace(a.GetNamespace())); err != nil {
+			l.Error(err, "unable to list the ServiceCatalogs to reconcile", "object", a.GetName())
+			return []reconcile.Request{}
+		}
+
+		rr := make([]reconcile.Request, 0, len(scl.Items))
+		for _, sc := range scl.Items {
+			rr = append(rr, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: sc.Namespace, Name: sc.Name}})
+		}
+		return rr
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&primazaiov1alpha1.ServiceCatalog{}).
+		Watches(&primazaiov1alpha1.Environment{},
+			&handler.EnqueueRequestForObject{},
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&primazaiov1alpha1.ClusterEnvironment{},
+			handler.EnqueueRequestsFromMapFunc(reconcileOnClusterEnvironmentChange),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&primazaiov1alpha1.ServiceGrantReference{},
+			handler.EnqueueRequestsFromMapFunc(reconcileOnServiceGrantReferenceChange),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
 		Complete(r)
 }
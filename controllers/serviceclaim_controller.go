@@ -22,15 +22,19 @@ import (
 	"fmt"
 	"reflect"
 	"slices"
+	"time"
 
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
@@ -50,6 +54,10 @@ type ServiceClaimReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
 	Mapper meta.RESTMapper
+
+	// APIReader reads the resources other tenants grant, which are not
+	// cached
+	APIReader client.Reader
 }
 
 const ServiceClaimFinalizer = "serviceclaims.primaza.io/finalizer"
@@ -59,12 +67,21 @@ func NewServiceClaimReconciler(mgr ctrl.Manager) *ServiceClaimReconciler {
 		Client: mgr.GetClient(),
 		Scheme: mgr.GetScheme(),
 		Mapper: mgr.GetRESTMapper(),
+
+		APIReader: mgr.GetAPIReader(),
 	}
 }
 
 //+kubebuilder:rbac:groups=primaza.io,namespace=system,resources=serviceclaims,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=primaza.io,namespace=system,resources=serviceclaims/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=primaza.io,namespace=system,resources=serviceclaims/finalizers,verbs=update
+//+kubebuilder:rbac:groups=primaza.io,namespace=system,resources=claimquotas,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",namespace=system,resources=secrets,verbs=create;delete;get;list;watch;update;patch
+//+kubebuilder:rbac:groups="",namespace=system,resources=serviceaccounts,verbs=create;delete;get
+//+kubebuilder:rbac:groups=batch,namespace=system,resources=jobs,verbs=get;list;watch;create;delete
+//+kubebuilder:rbac:groups=rbac.authorization.k8s.io,namespace=system,resources=roles;rolebindings,verbs=create;delete;get
+//+kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
+//+kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -90,7 +107,7 @@ func (r *ServiceClaimReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	l.Info("Check if Service Claim is marked for deletion")
 	if sclaim.HasDeletionTimestamp() {
 		if controllerutil.ContainsFinalizer(&sclaim, ServiceClaimFinalizer) {
-			if err := r.processClaimMarkedForDeletion(ctx, req, sclaim); err != nil {
+			if done, err := r.processClaimMarkedForDeletion(ctx, req, sclaim); err != nil || !done {
 				return ctrl.Result{}, err
 			}
 			// Remove finalizer from service binding
@@ -118,22 +135,75 @@ func (r *ServiceClaimReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return ctrl.Result{}, err
 	}
 
+	// expired ServiceClaims are deleted, and finalized as any other
+	expired, leaseRequeueAfter, err := r.expireIfNeeded(ctx, &sclaim, time.Now())
+	if err != nil {
+		l.Error(err, "error checking the ServiceClaim's lease")
+		return ctrl.Result{}, err
+	}
+	if expired {
+		return ctrl.Result{}, nil
+	}
+
+	// credentials issued by a RegisteredService the ServiceClaim failed
+	// over from are revoked asynchronously
+	if _, err := r.awaitCredentialRevocation(ctx, sclaim, nil); err != nil {
+		l.Error(err, "error revoking the credentials issued by a previously claimed registered service")
+	}
+
 	l = l.WithValues("service-claim", sclaim.Name, "state", sclaim.Status.State)
-	var err error
 	switch sclaim.Status.State {
 	case primazaiov1alpha1.ServiceClaimStateResolved:
+		if bindingsRefreshDue(sclaim, time.Now()) {
+			l.Info("refreshing the state of the service bindings", "bindings", sclaim.Status.Bindings)
+			err = r.refreshBindings(ctx, &sclaim, time.Now())
+			break
+		}
 		l.Info("reconciling Resolved service claim")
-		err = r.processResolvedServiceClaim(ctx, sclaim)
+		err = r.processResolvedServiceClaim(ctx, &sclaim)
+	case primazaiov1alpha1.ServiceClaimStateRejected:
+		l.Info("service claim has been rejected, nothing to reconcile")
 	default:
 		l.Info("reconciling Pending or marked for deletion service claim")
-		err = r.processClaim(ctx, req, sclaim)
+		err = r.processClaim(ctx, req, &sclaim)
 	}
 	if err != nil {
 		l.Error(err, "error processing ServiceClaim")
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, nil
+	// the Service Agent provisions the service asynchronously: look for it
+	// until it is discovered as a RegisteredService
+	if sclaim.Status.State == primazaiov1alpha1.ServiceClaimStatePending && sclaim.Status.Provisioning != nil {
+		if after := provisioningRequeueAfter(*sclaim.Status.Provisioning, time.Now()); after > 0 {
+			l.Info("waiting for the provisioned service, requeueing", "provisioning", sclaim.Status.Provisioning)
+			return ctrl.Result{RequeueAfter: minRequeueAfter(after, leaseRequeueAfter)}, nil
+		}
+	}
+
+	// the claimed RegisteredService is Unreachable: check it again
+	// when the failover grace period expires
+	result := ctrl.Result{RequeueAfter: failoverRequeueAfter(sclaim, time.Now())}
+	if result.RequeueAfter > 0 {
+		l.Info("registered service is unreachable, requeueing", "unreachable-since", sclaim.Status.UnreachableSince)
+	}
+
+	// the Application Agents update the state of the ServiceBindings
+	// asynchronously: refresh the ServiceClaim's bindings until they are
+	// Ready, backing off between refreshes
+	if after := bindingsRefreshRequeueAfter(sclaim, time.Now()); after > 0 {
+		l.Info("service bindings are not ready yet, requeueing", "bindings", sclaim.Status.Bindings)
+		result.RequeueAfter = minRequeueAfter(result.RequeueAfter, after)
+	}
+
+	// check the lease again when the ServiceClaim is about to expire,
+	// and when it expires
+	if leaseRequeueAfter > 0 {
+		l.Info("service claim has a lease, requeueing", "expiration-time", sclaim.Status.ExpirationTime)
+		result.RequeueAfter = minRequeueAfter(result.RequeueAfter, leaseRequeueAfter)
+	}
+
+	return result, nil
 }
 
 func (r *ServiceClaimReconciler) ensureServiceClaimIsInitialized(ctx context.Context, sc *primazaiov1alpha1.ServiceClaim) error {
@@ -147,12 +217,11 @@ func (r *ServiceClaimReconciler) ensureServiceClaimIsInitialized(ctx context.Con
 	return r.Client.Status().Update(ctx, sc)
 }
 
-func (r *ServiceClaimReconciler) processClaim(ctx context.Context, req ctrl.Request, sclaim primazaiov1alpha1.ServiceClaim) error {
+func (r *ServiceClaimReconciler) processClaim(ctx context.Context, req ctrl.Request, sclaim *primazaiov1alpha1.ServiceClaim) error {
 	l := log.FromContext(ctx)
 
-	var rsl primazaiov1alpha1.RegisteredServiceList
-	lo := client.ListOptions{Namespace: req.NamespacedName.Namespace}
-	if err := r.List(ctx, &rsl, &lo); err != nil {
+	rsl, err := r.listRegisteredServices(ctx, req.NamespacedName.Namespace)
+	if err != nil {
 		l.Info("unable to retrieve RegisteredServiceList", "error", err)
 		return client.IgnoreNotFound(err)
 	}
@@ -164,41 +233,40 @@ func (r *ServiceClaimReconciler) processClaim(ctx context.Context, req ctrl.Requ
 	return nil
 }
 
-func (r *ServiceClaimReconciler) processClaimMarkedForDeletion(ctx context.Context, req ctrl.Request, sclaim primazaiov1alpha1.ServiceClaim) error {
+// processClaimMarkedForDeletion releases the resources of a ServiceClaim
+// marked for deletion. It returns false while the credentials issued for the
+// ServiceClaim are being revoked.
+func (r *ServiceClaimReconciler) processClaimMarkedForDeletion(ctx context.Context, req ctrl.Request, sclaim primazaiov1alpha1.ServiceClaim) (bool, error) {
 	l := log.FromContext(ctx)
 	errs := []error{}
-	var rsl primazaiov1alpha1.RegisteredServiceList
-	lo := client.ListOptions{Namespace: req.NamespacedName.Namespace}
-	if err := r.List(ctx, &rsl, &lo); err != nil {
-		l.Info("unable to retrieve RegisteredServiceList", "error", err)
-		errs = append(errs, client.IgnoreNotFound(err))
-	}
-	var registeredServiceFound bool
-	var registeredService primazaiov1alpha1.RegisteredService
-	for _, rs := range rsl.Items {
-		// Check if the ServiceClassIdentity given in ServiceClaim is a subset of
-		// ServiceClassIdentity given in the RegisteredService
-		if checkSCISubset(sclaim.Spec.ServiceClassIdentity, rs.Spec.ServiceClassIdentity) &&
-			(rs.Spec.Constraints == nil ||
-				envtag.Match(sclaim.Spec.Target.EnvironmentTag, rs.Spec.Constraints.Environments)) {
-			registeredServiceFound = true
-			registeredService = rs
-			break
-		}
-	}
-
 	if err := r.DeleteServiceBindingsAndSecret(ctx, req, sclaim); err != nil {
 		l.Error(err, "unable to delete service binding and secret", "Service Binding", sclaim.Name)
 		errs = append(errs, err)
 	}
 
-	if registeredServiceFound {
-		if err := r.changeServiceState(ctx, registeredService, primazaiov1alpha1.RegisteredServiceStateAvailable); err != nil {
-			l.Error(err, "unable to update the RegisteredService", "RegisteredService", registeredService)
+	done := true
+	if sclaim.Status.RegisteredService != nil {
+		var rs primazaiov1alpha1.RegisteredService
+		if k, err := r.getRegisteredService(ctx, sclaim, &rs); err != nil {
+			l.Info("unable to retrieve RegisteredService", "error", err, "registered-service", k)
+			errs = append(errs, client.IgnoreNotFound(err))
+		} else if revoked, err := r.revokeCredentials(ctx, sclaim, rs); err != nil {
+			l.Error(err, "unable to revoke issued credentials", "RegisteredService", rs)
+			errs = append(errs, err)
+		} else if !revoked {
+			l.Info("waiting for the issued credentials to be revoked", "RegisteredService", rs.Name)
+			done = false
+		} else if err := r.releaseService(ctx, &rs, sclaim.Status.ClaimID); err != nil {
+			l.Error(err, "unable to update the RegisteredService", "RegisteredService", rs)
 			errs = append(errs, err)
 		}
 	}
-	return errors.Join(errs...)
+
+	if err := r.deleteServiceProvisioning(ctx, sclaim); err != nil {
+		l.Error(err, "unable to delete service provisioning", "provisioning", sclaim.Status.Provisioning)
+		errs = append(errs, err)
+	}
+	return done, errors.Join(errs...)
 }
 
 // Ref. https://stackoverflow.com/a/18879994/547840
@@ -221,9 +289,13 @@ func checkSCISubset(serviceClaim, registeredService []v1alpha1.ServiceClassIdent
 	return true
 }
 
+// extractServiceEndpointDefinition adds the values of the given keys of the
+// RegisteredService's Service Endpoint Definition to the secret. Values from
+// Secrets are read in the RegisteredService's namespace, that is not the
+// tenant's one for RegisteredServices granted by other tenants.
 func (r *ServiceClaimReconciler) extractServiceEndpointDefinition(
 	ctx context.Context,
-	namespace string,
+	tenant string,
 	rs v1alpha1.RegisteredService,
 	sedKeys []string,
 	secret *corev1.Secret) (int, error) {
@@ -243,8 +315,8 @@ func (r *ServiceClaimReconciler) extractServiceEndpointDefinition(
 		} else if k := sed.ValueFromSecret.Key; k != "" { // check value if the key is non-empty
 			if slices.Contains(sedKeys, sed.Name) {
 				sec := &corev1.Secret{}
-				nn := types.NamespacedName{Namespace: namespace, Name: sed.ValueFromSecret.Name}
-				if err := r.Get(ctx, nn, sec); err != nil {
+				nn := types.NamespacedName{Namespace: rs.Namespace, Name: sed.ValueFromSecret.Name}
+				if err := r.reader(tenant, rs.Namespace).Get(ctx, nn, sec); err != nil {
 					l.Info("unable to retrieve Secret", "error", err, "secret", nn)
 					continue
 				}
@@ -257,13 +329,32 @@ func (r *ServiceClaimReconciler) extractServiceEndpointDefinition(
 	return count, nil
 }
 
-func (r *ServiceClaimReconciler) changeServiceState(ctx context.Context, rs primazaiov1alpha1.RegisteredService, state primazaiov1alpha1.RegisteredServiceState) error {
-	rs.Status.State = state
-	if err := r.Status().Update(ctx, &rs); err != nil {
-		return err
+// claimService records the ServiceClaim among the ones holding the
+// RegisteredService. The RegisteredService is marked as Claimed only when it
+// can not accept more claims
+func (r *ServiceClaimReconciler) claimService(ctx context.Context, rs *primazaiov1alpha1.RegisteredService, claimID string) error {
+	if !slices.Contains(rs.Status.Claims, claimID) {
+		rs.Status.Claims = append(rs.Status.Claims, claimID)
+		now := metav1.Now()
+		rs.Status.LastClaimTime = &now
+	}
+	if !rs.HasCapacity() {
+		rs.Status.State = primazaiov1alpha1.RegisteredServiceStateClaimed
 	}
 
-	return nil
+	return r.Status().Update(ctx, rs)
+}
+
+// releaseService removes the ServiceClaim from the ones holding the
+// RegisteredService. A Claimed RegisteredService is made Available again as
+// soon as it can accept new claims
+func (r *ServiceClaimReconciler) releaseService(ctx context.Context, rs *primazaiov1alpha1.RegisteredService, claimID string) error {
+	rs.Status.Claims = slices.DeleteFunc(slices.Clone(rs.Status.Claims), func(c string) bool { return c == claimID })
+	if rs.Status.State == primazaiov1alpha1.RegisteredServiceStateClaimed && rs.HasCapacity() {
+		rs.Status.State = primazaiov1alpha1.RegisteredServiceStateAvailable
+	}
+
+	return r.Status().Update(ctx, rs)
 }
 
 func (r *ServiceClaimReconciler) getEnvironmentFromClusterEnvironment(
@@ -281,6 +372,20 @@ func (r *ServiceClaimReconciler) getEnvironmentFromClusterEnvironment(
 	return ce, nil
 }
 
+// serviceClaimEnvironment returns the environment the ServiceClaim targets.
+// Labels are only known when the ServiceClaim targets a ClusterEnvironment.
+func (r *ServiceClaimReconciler) serviceClaimEnvironment(ctx context.Context, sclaim primazaiov1alpha1.ServiceClaim) (envtag.Environment, error) {
+	if sclaim.Spec.Target.ApplicationClusterContext == nil {
+		return envtag.Environment{Name: sclaim.Spec.Target.EnvironmentTag}, nil
+	}
+
+	ce, err := r.getEnvironmentFromClusterEnvironment(ctx, sclaim.Namespace, sclaim.Spec.Target.ApplicationClusterContext.ClusterEnvironmentName)
+	if err != nil {
+		return envtag.Environment{}, err
+	}
+	return environmentOf(*ce), nil
+}
+
 func (r *ServiceClaimReconciler) getServiceEndpointDefinition(
 	ctx context.Context,
 	sclaim primazaiov1alpha1.ServiceClaim,
@@ -304,6 +409,19 @@ func (r *ServiceClaimReconciler) getServiceEndpointDefinition(
 		return nil, err
 	}
 
+	// per-claim credentials are going to override
+	// the values extracted from the RegisteredService
+	creds, err := r.getIssuedCredentials(ctx, sclaim)
+	if err != nil {
+		l.Error(err, "error retrieving issued credentials", "service-claim", sclaim)
+		return nil, err
+	}
+	for k, v := range creds {
+		if slices.Contains(sclaim.Spec.ServiceEndpointDefinitionKeys, k) {
+			secret.StringData[k] = v
+		}
+	}
+
 	// ServiceClassIdentity values are going to override
 	// any values in the secret resource
 	for _, sci := range sclaim.Spec.ServiceClassIdentity {
@@ -315,7 +433,7 @@ func (r *ServiceClaimReconciler) getServiceEndpointDefinition(
 
 func (r *ServiceClaimReconciler) processResolvedServiceClaim(
 	ctx context.Context,
-	sclaim primazaiov1alpha1.ServiceClaim) error {
+	sclaim *primazaiov1alpha1.ServiceClaim) error {
 	l := log.FromContext(ctx).WithValues("service-claim", sclaim)
 
 	if sclaim.Status.RegisteredService == nil {
@@ -326,55 +444,66 @@ func (r *ServiceClaimReconciler) processResolvedServiceClaim(
 
 	// retrieve already bound RegisteredService
 	var rs primazaiov1alpha1.RegisteredService
-	k := types.NamespacedName{Name: sclaim.Status.RegisteredService.Name, Namespace: sclaim.Namespace}
-	if err := r.Get(ctx, k, &rs, &client.GetOptions{}); err != nil {
+	if k, err := r.getRegisteredService(ctx, *sclaim, &rs); err != nil {
 		l.Info("error retrieving the RegisteredService", "error", err, "registered-service", k)
 		return err
 	}
 
+	// move to another RegisteredService if the claimed one has been
+	// Unreachable for too long
+	failedOver, err := r.failoverIfNeeded(ctx, sclaim, rs)
+	if err != nil {
+		l.Error(err, "error failing over the ServiceClaim", "registered-service", rs.Name)
+		return err
+	}
+	if failedOver {
+		req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: sclaim.Namespace, Name: sclaim.Name}}
+		return r.processClaim(ctx, req, sclaim)
+	}
+
 	// bake the ServiceEndpointDefinition Secret
-	secret, err := r.getServiceEndpointDefinition(ctx, sclaim, rs)
+	secret, err := r.getServiceEndpointDefinition(ctx, *sclaim, rs)
 	if err != nil {
 		l.Error(err, "error baking the ServiceEndpointDefinition", "registered-service", rs, "service-claim", sclaim)
 		return err
 	}
 
 	sclaim.Status.State = primazaiov1alpha1.ServiceClaimStatePending
-	sclaim.Status.RegisteredService = &corev1.ObjectReference{
-		Name: rs.Name,
-		UID:  rs.UID,
-	}
-	if err := r.updateServiceClaimStatus(ctx, &sclaim); err != nil {
+	sclaim.Status.RegisteredService = registeredServiceReference(*sclaim, rs)
+	if err := r.updateServiceClaimStatus(ctx, sclaim); err != nil {
 		l.Error(err, "error updating the ServiceClaim",
 			"registered-service", rs, "service-claim", sclaim)
 		return err
 	}
 
-	// Update RegisteredService status to Claimed to avoid raise conditions
-	if err := r.changeServiceState(ctx, rs, primazaiov1alpha1.RegisteredServiceStateClaimed); err != nil {
+	// Record the claim on the RegisteredService to avoid raise conditions
+	if err := r.claimService(ctx, &rs, sclaim.Status.ClaimID); err != nil {
 		l.Error(err, "error updating the RegisteredService", "registered-service", rs, "service-claim", sclaim)
 		return err
 	}
 
 	sclaim.Status.State = primazaiov1alpha1.ServiceClaimStateResolved
-	sclaim.Status.RegisteredService = &corev1.ObjectReference{
-		Name: rs.Name,
-		UID:  rs.UID,
-	}
+	sclaim.Status.RegisteredService = registeredServiceReference(*sclaim, rs)
 	if err := r.pushToClusterEnvironments(ctx, sclaim, secret); err != nil {
 		l.Error(err,
 			"error pushing the ServiceBinding and secret to the cluster environments",
 			"registered-service", rs, "service-claim", sclaim)
-		// Update RegisteredService status back to Available
-		if err := r.changeServiceState(ctx, rs, primazaiov1alpha1.RegisteredServiceStateAvailable); err != nil {
+		// Release the RegisteredService
+		if err := r.releaseService(ctx, &rs, sclaim.Status.ClaimID); err != nil {
 			l.Error(err,
 				"error updating the RegisteredService with details on failed push of Service Binding",
 				"registered-service", rs, "service-claim", sclaim)
 		}
+		// record the outcome of the push in the ServiceClaim's bindings
+		sclaim.Status.State = primazaiov1alpha1.ServiceClaimStatePending
+		if err := r.updateServiceClaimStatus(ctx, sclaim); err != nil {
+			l.Error(err, "error updating the ServiceClaim",
+				"registered-service", rs, "service-claim", sclaim)
+		}
 		return err
 	}
 
-	if err := r.updateServiceClaimStatus(ctx, &sclaim); err != nil {
+	if err := r.updateServiceClaimStatus(ctx, sclaim); err != nil {
 		l.Error(err, "error updating the ServiceClaim",
 			"registered-service", rs, "service-claim", sclaim)
 		return err
@@ -404,7 +533,7 @@ func (r *ServiceClaimReconciler) updateServiceClaimStatus(ctx context.Context, s
 func (r *ServiceClaimReconciler) processServiceClaim(
 	ctx context.Context,
 	rsl primazaiov1alpha1.RegisteredServiceList,
-	sclaim primazaiov1alpha1.ServiceClaim) error {
+	sclaim *primazaiov1alpha1.ServiceClaim) error {
 	l := log.FromContext(ctx)
 	secret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
@@ -414,71 +543,123 @@ func (r *ServiceClaimReconciler) processServiceClaim(
 		StringData: map[string]string{},
 	}
 
-	// count the number of secret data entries
-	count := 0
-	// loop over every RegisteredService
-	registeredServiceFound := false
-	var registeredService primazaiov1alpha1.RegisteredService
+	env, err := r.serviceClaimEnvironment(ctx, *sclaim)
+	if err != nil {
+		l.Error(err, "unable to get environment from cluster environment")
+		return err
+	}
 
-	var env string
-	if sclaim.Spec.Target.ApplicationClusterContext != nil {
-		var err error
-		ce, err := r.getEnvironmentFromClusterEnvironment(ctx, sclaim.Namespace, sclaim.Spec.Target.ApplicationClusterContext.ClusterEnvironmentName)
+	// report whether the environment is declared
+	ec, err := environmentKnownCondition(ctx, r.Client, sclaim.Namespace, env.Name)
+	if err != nil {
+		l.Error(err, "unable to retrieve environment", "environment", env.Name)
+		return err
+	}
+	meta.SetStatusCondition(&sclaim.Status.Conditions, ec)
+
+	// a RegisteredService may have already been reserved for the claim,
+	// e.g. while waiting for per-claim credentials to be issued
+	rs := reservedRegisteredService(*sclaim, rsl.Items)
+	if rs == nil {
+		// ServiceClaims exceeding a ClaimQuota wait for the ServiceClaims
+		// counting against it to be deleted
+		q, err := r.exceededClaimQuota(ctx, *sclaim)
 		if err != nil {
-			l.Error(err, "unable to get environment from cluster environment")
+			l.Error(err, "unable to check the claim quotas")
 			return err
 		}
-		env = ce.Spec.EnvironmentName
-	} else {
-		env = sclaim.Spec.Target.EnvironmentTag
-	}
-
-	for _, rs := range rsl.Items {
-		// Check if the registered Service is Available
-		if rs.Status.State != primazaiov1alpha1.RegisteredServiceStateAvailable {
-			continue
+		if q != nil {
+			return r.reportClaimQuotaExceeded(ctx, sclaim, *q)
 		}
 
-		// Check if the ServiceClassIdentity given in ServiceClaim is a subset of
-		// ServiceClassIdentity given in the RegisteredService
-		if checkSCISubset(sclaim.Spec.ServiceClassIdentity, rs.Spec.ServiceClassIdentity) &&
-			(rs.Spec.Constraints == nil ||
-				envtag.Match(env, rs.Spec.Constraints.Environments)) {
-			registeredServiceFound = true
-			registeredService = rs
-			var err error
-			count, err = r.extractServiceEndpointDefinition(
-				ctx, sclaim.Namespace, rs, sclaim.Spec.ServiceEndpointDefinitionKeys, secret)
-			if err != nil {
-				l.Error(err, "unable to extract SED")
-				return err
+		var selection primazaiov1alpha1.ServiceClaimSelection
+		rs, selection = selectRegisteredService(*sclaim, env, rsl.Items)
+		sclaim.Status.Selection = &selection
+	}
+	sclaim.Status.Explanation = nil
+	if rs == nil {
+		// record why the ServiceClaim can not be resolved
+		explanation := explainServiceClaim(*sclaim, env, rsl.Items)
+		sclaim.Status.Explanation = &explanation
+
+		// ask a Service Agent to provision the service, if a ServiceClass
+		// is able to
+		provisioning, err := r.provisionService(ctx, sclaim, env.Name)
+		if err != nil {
+			l.Error(err, "unable to provision service")
+			meta.SetStatusCondition(&sclaim.Status.Conditions, metav1.Condition{
+				LastTransitionTime: metav1.Now(),
+				Type:               string(primazaiov1alpha1.ServiceClaimConditionReady),
+				Status:             metav1.ConditionFalse,
+				Reason:             constants.ServiceProvisioningFailedReason,
+				Message:            err.Error(),
+			})
+			sclaim.Status.State = primazaiov1alpha1.ServiceClaimStatePending
+			if err := r.updateServiceClaimStatus(ctx, sclaim); err != nil {
+				l.Error(err, "unable to update the ServiceClaim", "ServiceClaim", sclaim)
 			}
-			break
+			return err
+		}
+		if provisioning {
+			reason, msg := provisioningCondition(*sclaim.Status.Provisioning)
+			meta.SetStatusCondition(&sclaim.Status.Conditions, metav1.Condition{
+				LastTransitionTime: metav1.Now(),
+				Type:               string(primazaiov1alpha1.ServiceClaimConditionReady),
+				Status:             metav1.ConditionFalse,
+				Reason:             reason,
+				Message:            msg,
+			})
+			sclaim.Status.State = primazaiov1alpha1.ServiceClaimStatePending
+			return r.updateServiceClaimStatus(ctx, sclaim)
 		}
-	}
 
-	if !registeredServiceFound {
+		selection := sclaim.Status.Selection
+		msg := "SCI is not matched"
+		if len(selection.Rejected) > 0 {
+			msg = "no RegisteredService matching the SCI can be claimed"
+		}
 		c := metav1.Condition{
 			LastTransitionTime: metav1.Now(),
 			Type:               string(primazaiov1alpha1.ServiceClaimConditionReady),
 			Status:             metav1.ConditionFalse,
 			Reason:             constants.NoMatchingServiceFoundReason,
-			Message:            "SCI is not matched",
+			Message:            msg,
 		}
 		meta.SetStatusCondition(&sclaim.Status.Conditions, c)
 
 		sclaim.Status.State = primazaiov1alpha1.ServiceClaimStatePending
-		if err := r.updateServiceClaimStatus(ctx, &sclaim); err != nil {
+		if err := r.updateServiceClaimStatus(ctx, sclaim); err != nil {
 			l.Error(err, "unable to update the ServiceClaim", "ServiceClaim", sclaim)
 			return err
 		}
 
-		return fmt.Errorf("SCI is not matched")
+		return errors.New(msg)
+	}
+	registeredService := *rs
+
+	// the service provisioned for the ServiceClaim is not needed if another
+	// RegisteredService has been selected in the meantime
+	if sclaim.Status.Provisioning != nil && registeredService.Spec.ReservedFor != sclaim.Status.ClaimID {
+		l.Info("releasing the service provisioned for the claim", "provisioning", sclaim.Status.Provisioning)
+		if err := r.deleteServiceProvisioning(ctx, *sclaim); err != nil {
+			l.Error(err, "unable to delete service provisioning", "provisioning", sclaim.Status.Provisioning)
+			return err
+		}
+		sclaim.Status.Provisioning = nil
 	}
 
-	// if the number of SED keys is more than the number of secret data entries
-	// that indicates one or more keys are missing
-	if len(sclaim.Spec.ServiceEndpointDefinitionKeys) > count {
+	if _, err := r.extractServiceEndpointDefinition(
+		ctx, sclaim.Namespace, registeredService, sclaim.Spec.ServiceEndpointDefinitionKeys, secret); err != nil {
+		l.Error(err, "unable to extract SED")
+		return err
+	}
+
+	// if a SED key is neither in the secret data entries nor issued per claim
+	// that indicates the key is missing
+	if slices.ContainsFunc(sclaim.Spec.ServiceEndpointDefinitionKeys, func(k string) bool {
+		_, ok := secret.StringData[k]
+		return !ok && !slices.Contains(issuedKeys(registeredService), k)
+	}) {
 		c := metav1.Condition{
 			LastTransitionTime: metav1.Now(),
 			Type:               string(primazaiov1alpha1.ServiceClaimConditionReady),
@@ -489,7 +670,7 @@ func (r *ServiceClaimReconciler) processServiceClaim(
 		meta.SetStatusCondition(&sclaim.Status.Conditions, c)
 
 		sclaim.Status.State = primazaiov1alpha1.ServiceClaimStatePending
-		if err := r.updateServiceClaimStatus(ctx, &sclaim); err != nil {
+		if err := r.updateServiceClaimStatus(ctx, sclaim); err != nil {
 			l.Error(err, "unable to update the ServiceClaim", "ServiceClaim", sclaim)
 			return err
 		}
@@ -504,37 +685,45 @@ func (r *ServiceClaimReconciler) processServiceClaim(
 	}
 
 	sclaim.Status.State = primazaiov1alpha1.ServiceClaimStatePending
-	sclaim.Status.RegisteredService = &corev1.ObjectReference{
-		Name: registeredService.Name,
-		UID:  registeredService.UID,
-	}
-	if err := r.updateServiceClaimStatus(ctx, &sclaim); err != nil {
+	sclaim.Status.RegisteredService = registeredServiceReference(*sclaim, registeredService)
+	if err := r.updateServiceClaimStatus(ctx, sclaim); err != nil {
 		l.Error(err, "unable to update the ServiceClaim", "ServiceClaim", sclaim)
 		return err
 	}
 
-	// Update RegisteredService status to Claimed to avoid raise conditions
-	if err := r.changeServiceState(ctx, registeredService, primazaiov1alpha1.RegisteredServiceStateClaimed); err != nil {
+	// Record the claim on the RegisteredService to avoid raise conditions
+	if err := r.claimService(ctx, &registeredService, sclaim.Status.ClaimID); err != nil {
 		l.Error(err, "unable to update the RegisteredService", "RegisteredService", registeredService)
 		return err
 	}
 
-	sclaim.Status.State = primazaiov1alpha1.ServiceClaimStateResolved
-	sclaim.Status.RegisteredService = &corev1.ObjectReference{
-		Name: registeredService.Name,
-		UID:  registeredService.UID,
+	// RegisteredServices requiring approval stay reserved until an
+	// authorized user takes a decision
+	if approved, err := r.approveIfNeeded(ctx, sclaim, registeredService); err != nil || !approved {
+		return err
 	}
-	err := r.pushToClusterEnvironments(ctx, sclaim, secret)
-	if err != nil {
+
+	if issued, err := r.addIssuedCredentials(ctx, sclaim, registeredService, secret); err != nil || !issued {
+		return err
+	}
+
+	sclaim.Status.State = primazaiov1alpha1.ServiceClaimStateResolved
+	sclaim.Status.RegisteredService = registeredServiceReference(*sclaim, registeredService)
+	if err := r.pushToClusterEnvironments(ctx, sclaim, secret); err != nil {
 		l.Error(err, "error pushing to cluster environments")
-		// Update RegisteredService status back to Available
-		if err := r.changeServiceState(ctx, registeredService, primazaiov1alpha1.RegisteredServiceStateAvailable); err != nil {
+		// Release the RegisteredService
+		if err := r.releaseService(ctx, &registeredService, sclaim.Status.ClaimID); err != nil {
 			l.Error(err, "unable to update the RegisteredService", "RegisteredService", registeredService)
 		}
+		// record the outcome of the push in the ServiceClaim's bindings
+		sclaim.Status.State = primazaiov1alpha1.ServiceClaimStatePending
+		if err := r.updateServiceClaimStatus(ctx, sclaim); err != nil {
+			l.Error(err, "unable to update the ServiceClaim", "ServiceClaim", sclaim)
+		}
 		return client.IgnoreNotFound(err)
 	}
 
-	if err := r.updateServiceClaimStatus(ctx, &sclaim); err != nil {
+	if err := r.updateServiceClaimStatus(ctx, sclaim); err != nil {
 		l.Error(err, "unable to update the ServiceClaim", "ServiceClaim", sclaim)
 		return err
 	}
@@ -591,10 +780,18 @@ func (r *ServiceClaimReconciler) updateRemoteServiceClaimStatusIfNeeded(
 	l = l.WithValues("status", sclaim.Status)
 	if rsc.Status.RegisteredService != sclaim.Status.RegisteredService ||
 		rsc.Status.State != sclaim.Status.State ||
-		!reflect.DeepEqual(rsc.Status.Conditions, sclaim.Status.Conditions) {
+		!reflect.DeepEqual(rsc.Status.Conditions, sclaim.Status.Conditions) ||
+		!reflect.DeepEqual(rsc.Status.ExpirationTime, sclaim.Status.ExpirationTime) ||
+		!reflect.DeepEqual(rsc.Status.Approval, sclaim.Status.Approval) ||
+		!reflect.DeepEqual(rsc.Status.Selection, sclaim.Status.Selection) ||
+		!reflect.DeepEqual(rsc.Status.Bindings, sclaim.Status.Bindings) {
 		rsc.Status.RegisteredService = sclaim.Status.RegisteredService
 		rsc.Status.State = sclaim.Status.State
 		rsc.Status.Conditions = sclaim.Status.Conditions
+		rsc.Status.ExpirationTime = sclaim.Status.ExpirationTime
+		rsc.Status.Approval = sclaim.Status.Approval
+		rsc.Status.Selection = sclaim.Status.Selection
+		rsc.Status.Bindings = sclaim.Status.Bindings
 		if err := cli.Status().Update(ctx, &rsc); err != nil {
 			l.Error(err, "error updating serviceclaim status")
 			return fmt.Errorf("error updating ServiceClaim from application namespace %s of cluster environment %s: %w", ans, ce.Name, err)
@@ -607,13 +804,22 @@ func (r *ServiceClaimReconciler) updateRemoteServiceClaimStatusIfNeeded(
 	return nil
 }
 
+// pushToClusterEnvironments pushes the ServiceBinding and the secret
+// fulfilling the ServiceClaim into the targeted application namespaces.
+// The outcome of each push is recorded in the ServiceClaim's status bindings
 func (r *ServiceClaimReconciler) pushToClusterEnvironments(
 	ctx context.Context,
-	sclaim primazaiov1alpha1.ServiceClaim,
+	sclaim *primazaiov1alpha1.ServiceClaim,
 	secret *corev1.Secret,
 ) error {
 	l := log.FromContext(ctx)
 	errs := []error{}
+	bb := []primazaiov1alpha1.ServiceClaimBinding{}
+	defer func() {
+		sclaim.Status.Bindings = bb
+		scheduleBindingsRefresh(&sclaim.Status, 0, time.Now())
+	}()
+
 	if sclaim.Spec.Target.ApplicationClusterContext != nil {
 		var err error
 		ce, err := r.getEnvironmentFromClusterEnvironment(ctx, sclaim.Namespace, sclaim.Spec.Target.ApplicationClusterContext.ClusterEnvironmentName)
@@ -625,10 +831,12 @@ func (r *ServiceClaimReconciler) pushToClusterEnvironments(
 		if err != nil {
 			return err
 		}
-		if err = controlplane.PushServiceBinding(ctx, &sclaim, secret, r.Scheme, r.Client, &sclaim.Spec.Target.ApplicationClusterContext.Namespace, ce.Spec.ApplicationNamespaces, cfg); err != nil {
+		rr, err := controlplane.PushServiceBinding(ctx, sclaim, secret, r.Scheme, r.Client, &sclaim.Spec.Target.ApplicationClusterContext.Namespace, ce.Spec.ApplicationNamespaces, cfg)
+		if err != nil {
 			l.Error(err, "error pushing service binding", "serviceclaim", sclaim)
 			errs = append(errs, err)
 		}
+		bb = append(bb, serviceClaimBindings(ce.Name, rr)...)
 	} else {
 		var cel primazaiov1alpha1.ClusterEnvironmentList
 		if err := r.List(ctx, &cel); err != nil {
@@ -648,10 +856,11 @@ func (r *ServiceClaimReconciler) pushToClusterEnvironments(
 			}
 
 			l.Info("cluster environment is matching environment", "cluster environment", ce, "environment tag", sclaim.Spec.Target.EnvironmentTag)
-			if err = controlplane.PushServiceBinding(ctx, &sclaim, secret, r.Scheme, r.Client, nil, ce.Spec.ApplicationNamespaces, cfg); err != nil {
+			rr, err := controlplane.PushServiceBinding(ctx, sclaim, secret, r.Scheme, r.Client, nil, ce.Spec.ApplicationNamespaces, cfg)
+			if err != nil {
 				errs = append(errs, err)
 			}
-
+			bb = append(bb, serviceClaimBindings(ce.Name, rr)...)
 		}
 	}
 	if len(errs) > 0 {
@@ -660,6 +869,27 @@ func (r *ServiceClaimReconciler) pushToClusterEnvironments(
 	return nil
 }
 
+// serviceClaimBindings converts the results of pushing a ServiceBinding into
+// the application namespaces of a ClusterEnvironment to ServiceClaim's status bindings
+func serviceClaimBindings(clusterEnvironment string, rr []controlplane.ServiceBindingPushResult) []primazaiov1alpha1.ServiceClaimBinding {
+	bb := make([]primazaiov1alpha1.ServiceClaimBinding, 0, len(rr))
+	for _, r := range rr {
+		b := primazaiov1alpha1.ServiceClaimBinding{
+			ClusterEnvironment: clusterEnvironment,
+			Namespace:          r.Namespace,
+		}
+		if r.Err != nil {
+			b.LastError = r.Err.Error()
+		}
+		if r.Status != nil {
+			b.State = r.Status.State
+			b.Workloads = r.Status.Connections
+		}
+		bb = append(bb, b)
+	}
+	return bb
+}
+
 func (r *ServiceClaimReconciler) DeleteServiceBindingsAndSecret(
 	ctx context.Context,
 	req ctrl.Request,
@@ -711,6 +941,35 @@ func (r *ServiceClaimReconciler) DeleteServiceBindingsAndSecret(
 	return errors.Join(errs...)
 }
 
+// resolvedServiceClaimsForRegisteredService returns the reconcile requests
+// for the resolved ServiceClaims bound to the given RegisteredService
+func (r *ServiceClaimReconciler) resolvedServiceClaimsForRegisteredService(
+	ctx context.Context,
+	rs primazaiov1alpha1.RegisteredService,
+) []reconcile.Request {
+	l := log.FromContext(ctx)
+
+	serviceclaims := &v1alpha1.ServiceClaimList{}
+	opts := &client.ListOptions{}
+	if err := r.List(ctx, serviceclaims, opts); err != nil {
+		l.Error(err,
+			"unable to list the ServiceClaims and reconcile for Registered Service Updates",
+			"RegisteredService", rs.Name)
+		return []reconcile.Request{}
+	}
+	rr := []reconcile.Request{}
+	for _, sc := range serviceclaims.Items {
+		if sc.Status.State == primazaiov1alpha1.ServiceClaimStateResolved &&
+			sc.Status.RegisteredService != nil && sc.Status.RegisteredService.UID == rs.UID {
+			rr = append(rr, reconcile.Request{NamespacedName: types.NamespacedName{
+				Namespace: sc.Namespace,
+				Name:      sc.Name,
+			}})
+		}
+	}
+	return rr
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *ServiceClaimReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	genPred := predicate.GenerationChangedPredicate{}
@@ -721,32 +980,148 @@ func (r *ServiceClaimReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			l.Info("error parsing object to RegisteredService when mapping to ServiceClaim reconciliation trigger", "object", a)
 			return []reconcile.Request{}
 		}
-		if rs.Status.State != `Claimed` {
-			l.Info("Registered service is unclaimed, no service claim to reconcile", "registered-service", rs.Name)
+
+		// the RegisteredService may resolve ServiceClaims still Pending
+		rr := r.pendingServiceClaimsForRegisteredService(ctx, *rs)
+		if rs.Status.State != primazaiov1alpha1.RegisteredServiceStateClaimed && len(rs.Status.Claims) == 0 {
+			l.Info("Registered service is unclaimed, no resolved service claim to reconcile", "registered-service", rs.Name)
+			return rr
+		}
+		return append(rr, r.resolvedServiceClaimsForRegisteredService(ctx, *rs)...)
+	}
+
+	// secrets referenced by RegisteredServices' Service Endpoint Definition
+	// may be rotated: the Service Endpoint Definition of the resolved
+	// ServiceClaims needs to be baked and pushed again
+	reconcileOnSecretUpdate := func(ctx context.Context, a client.Object) []reconcile.Request {
+		l := log.FromContext(ctx)
+
+		var rsl primazaiov1alpha1.RegisteredServiceList
+		if err := r.List(ctx, &rsl, client.InNamespace(a.GetNamespace())); err != nil {
+			l.Error(err, "unable to list the RegisteredServices and reconcile for Secret Updates", "secret", a.GetName())
 			return []reconcile.Request{}
 		}
-		serviceclaims := &v1alpha1.ServiceClaimList{}
-		opts := &client.ListOptions{}
-		if err := r.List(ctx, serviceclaims, opts); err != nil {
-			l.Error(err,
-				"unable to list the ServiceClaims and reconcile for Registered Service Updates",
-				"RegisteredService", rs.Name)
+
+		rr := []reconcile.Request{}
+		for _, rs := range rsl.Items {
+			if slices.ContainsFunc(rs.Spec.ServiceEndpointDefinition, func(sed primazaiov1alpha1.ServiceEndpointDefinitionItem) bool {
+				return sed.ValueFromSecret != nil && sed.ValueFromSecret.Name == a.GetName()
+			}) {
+				rr = append(rr, r.resolvedServiceClaimsForRegisteredService(ctx, rs)...)
+			}
+		}
+		return rr
+	}
+	// resolved ServiceClaims with a failover policy need to know when
+	// the claimed RegisteredService becomes Unreachable or recovers, while
+	// pending ServiceClaims need to know when it can be claimed again
+	stateChangedPred := predicate.Funcs{
+		CreateFunc: func(event.CreateEvent) bool { return false },
+		DeleteFunc: func(event.DeleteEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			ors, ok := e.ObjectOld.(*primazaiov1alpha1.RegisteredService)
+			nrs, nok := e.ObjectNew.(*primazaiov1alpha1.RegisteredService)
+			if !ok || !nok || ors.Status.State == nrs.Status.State {
+				return false
+			}
+			switch nrs.Status.State {
+			case primazaiov1alpha1.RegisteredServiceStateAvailable, primazaiov1alpha1.RegisteredServiceStateUnknown:
+				return true
+			default:
+				return ors.Status.State == primazaiov1alpha1.RegisteredServiceStateUnreachable ||
+					nrs.Status.State == primazaiov1alpha1.RegisteredServiceStateUnreachable
+			}
+		},
+	}
+	secretDataPred := predicate.Funcs{
+		CreateFunc: func(event.CreateEvent) bool { return false },
+		DeleteFunc: func(event.DeleteEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			os, ok := e.ObjectOld.(*corev1.Secret)
+			ns, nok := e.ObjectNew.(*corev1.Secret)
+			return ok && nok && !reflect.DeepEqual(os.Data, ns.Data)
+		},
+	}
+
+	// pending ServiceClaims need to report whether the environment they
+	// refer to has been declared or removed, and may be resolved when a
+	// ClaimQuota frees up
+	reconcilePendingServiceClaims := func(ctx context.Context, a client.Object) []reconcile.Request {
+		l := log.FromContext(ctx)
+
+		var scl primazaiov1alpha1.ServiceClaimList
+		if err := r.List(ctx, &scl, client.InNamespace(a.GetNamespace())); err != nil {
+			l.Error(err, "unable to list the pending ServiceClaims to reconcile", "object", a.GetName())
 			return []reconcile.Request{}
 		}
-		for _, sc := range serviceclaims.Items {
-			if sc.Status.State == primazaiov1alpha1.ServiceClaimStateResolved &&
-				sc.Status.RegisteredService != nil && sc.Status.RegisteredService.UID == rs.UID {
-				return []reconcile.Request{{NamespacedName: types.NamespacedName{
-					Namespace: sc.Namespace,
-					Name:      sc.Name,
-				}}}
+
+		rr := []reconcile.Request{}
+		for _, sc := range scl.Items {
+			if sc.Status.State == primazaiov1alpha1.ServiceClaimStatePending && !sc.HasDeletionTimestamp() {
+				rr = append(rr, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: sc.Namespace, Name: sc.Name}})
 			}
 		}
-		return []reconcile.Request{}
+		return rr
 	}
+	// the RegisteredServices other tenants grant may resolve pending
+	// ServiceClaims, and the claimed ones may become Unreachable or recover:
+	// their changes are published in the tenant's ServiceGrantReferences
+	reconcileOnServiceGrantReferenceChange := func(ctx context.Context, a client.Object) []reconcile.Request {
+		l := log.FromContext(ctx)
+		ref, ok := a.(*primazaiov1alpha1.ServiceGrantReference)
+		if !ok {
+			l.Info("error parsing object to ServiceGrantReference when mapping to ServiceClaim reconciliation trigger", "object", a)
+			return []reconcile.Request{}
+		}
+
+		var scl primazaiov1alpha1.ServiceClaimList
+		if err := r.List(ctx, &scl, client.InNamespace(ref.Namespace)); err != nil {
+			l.Error(err, "unable to list the ServiceClaims to reconcile", "object", ref.Name)
+			return []reconcile.Request{}
+		}
+
+		rr := []reconcile.Request{}
+		for _, sc := range scl.Items {
+			pending := sc.Status.State == primazaiov1alpha1.ServiceClaimStatePending && !sc.HasDeletionTimestamp()
+			granted := sc.Status.State == primazaiov1alpha1.ServiceClaimStateResolved &&
+				sc.Status.RegisteredService != nil && sc.Status.RegisteredService.Namespace == ref.Spec.Tenant
+			if pending || granted {
+				rr = append(rr, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: sc.Namespace, Name: sc.Name}})
+			}
+		}
+		return rr
+	}
+
+	environmentDeclaredPred := predicate.Funcs{
+		UpdateFunc: func(event.UpdateEvent) bool { return false },
+	}
+
+	if err := mgr.GetFieldIndexer().IndexField(
+		context.Background(),
+		&primazaiov1alpha1.ServiceClaim{},
+		ServiceClassIdentityIndexKey,
+		indexServiceClassIdentity); err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&primazaiov1alpha1.ServiceClaim{}).
-		Watches(&primazaiov1alpha1.RegisteredService{}, handler.EnqueueRequestsFromMapFunc(reconcileOnRegisteredServiceUpdate)).
-		WithEventFilter(genPred).
+		For(&primazaiov1alpha1.ServiceClaim{}, builder.WithPredicates(predicate.Or(genPred, predicate.AnnotationChangedPredicate{}))).
+		Owns(&primazaiov1alpha1.ServiceClaimApproval{}, builder.WithPredicates(genPred)).
+		// credential issuer Jobs are awaited until they finish
+		Owns(&batchv1.Job{}).
+		Watches(&primazaiov1alpha1.RegisteredService{},
+			handler.EnqueueRequestsFromMapFunc(reconcileOnRegisteredServiceUpdate),
+			builder.WithPredicates(predicate.Or(genPred, stateChangedPred))).
+		Watches(&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(reconcileOnSecretUpdate),
+			builder.WithPredicates(secretDataPred)).
+		Watches(&primazaiov1alpha1.Environment{},
+			handler.EnqueueRequestsFromMapFunc(reconcilePendingServiceClaims),
+			builder.WithPredicates(environmentDeclaredPred)).
+		Watches(&primazaiov1alpha1.ClaimQuota{},
+			handler.EnqueueRequestsFromMapFunc(reconcilePendingServiceClaims)).
+		Watches(&primazaiov1alpha1.ServiceGrantReference{},
+			handler.EnqueueRequestsFromMapFunc(reconcileOnServiceGrantReferenceChange),
+			builder.WithPredicates(genPred)).
 		Complete(r)
 }
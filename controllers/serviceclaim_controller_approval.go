@@ -0,0 +1,154 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	primazaiov1alpha1 "github.com/primaza/primaza/api/v1alpha1"
+	"github.com/primaza/primaza/pkg/primaza/constants"
+)
+
+//+kubebuilder:rbac:groups=primaza.io,namespace=system,resources=serviceclaimapprovals,verbs=get;list;watch;create;delete
+
+// approveIfNeeded checks whether the ServiceClaim may claim the given
+// RegisteredService, which it has already reserved. When the
+// RegisteredService requires approval, it creates the ServiceClaimApproval
+// an authorized user decides on, and reports the ServiceClaim as
+// AwaitingApproval until the decision is taken. Rejected ServiceClaims
+// release the RegisteredService.
+//
+// It returns true if the ServiceClaim can go on claiming the
+// RegisteredService.
+func (r *ServiceClaimReconciler) approveIfNeeded(
+	ctx context.Context,
+	sclaim *primazaiov1alpha1.ServiceClaim,
+	rs primazaiov1alpha1.RegisteredService,
+) (bool, error) {
+	l := log.FromContext(ctx).WithValues("service-claim", sclaim.Name, "registered-service", rs.Name)
+
+	if !rs.Spec.RequiresApproval {
+		return true, nil
+	}
+
+	approval, err := r.getOrCreateServiceClaimApproval(ctx, *sclaim, rs)
+	if err != nil {
+		return false, err
+	}
+
+	switch approval.Spec.Decision {
+	case primazaiov1alpha1.ServiceClaimApprovalDecisionApproved:
+		l.Info("service claim approved", "approver", approval.Spec.Approver)
+		sclaim.Status.Approval = approvalRecord(*approval)
+		if c := meta.FindStatusCondition(sclaim.Status.Conditions, string(primazaiov1alpha1.ServiceClaimConditionReady)); c != nil &&
+			c.Reason == constants.AwaitingApprovalReason {
+			meta.RemoveStatusCondition(&sclaim.Status.Conditions, c.Type)
+		}
+		return true, nil
+
+	case primazaiov1alpha1.ServiceClaimApprovalDecisionRejected:
+		l.Info("service claim rejected", "approver", approval.Spec.Approver)
+		if err := r.releaseService(ctx, &rs, sclaim.Status.ClaimID); err != nil {
+			return false, err
+		}
+		sclaim.Status.Approval = approvalRecord(*approval)
+		sclaim.Status.State = primazaiov1alpha1.ServiceClaimStateRejected
+		meta.SetStatusCondition(&sclaim.Status.Conditions, metav1.Condition{
+			LastTransitionTime: metav1.Now(),
+			Type:               string(primazaiov1alpha1.ServiceClaimConditionReady),
+			Status:             metav1.ConditionFalse,
+			Reason:             constants.ClaimRejectedReason,
+			Message:            fmt.Sprintf("claim of registered service %s rejected by %s: %s", rs.Name, approval.Spec.Approver, approval.Spec.Reason),
+		})
+		return false, r.updateServiceClaimStatus(ctx, sclaim)
+
+	default:
+		l.Info("service claim awaiting approval", "approval", approval.Name)
+		sclaim.Status.Approval = nil
+		sclaim.Status.State = primazaiov1alpha1.ServiceClaimStateAwaitingApproval
+		meta.SetStatusCondition(&sclaim.Status.Conditions, metav1.Condition{
+			LastTransitionTime: metav1.Now(),
+			Type:               string(primazaiov1alpha1.ServiceClaimConditionReady),
+			Status:             metav1.ConditionFalse,
+			Reason:             constants.AwaitingApprovalReason,
+			Message:            fmt.Sprintf("registered service %s requires approval, see ServiceClaimApproval %s", rs.Name, approval.Name),
+		})
+		return false, r.updateServiceClaimStatus(ctx, sclaim)
+	}
+}
+
+// getOrCreateServiceClaimApproval returns the ServiceClaimApproval for the
+// ServiceClaim to claim the given RegisteredService, creating it if needed.
+// ServiceClaimApprovals referring to another RegisteredService, e.g. after a
+// failover, are replaced.
+func (r *ServiceClaimReconciler) getOrCreateServiceClaimApproval(
+	ctx context.Context,
+	sclaim primazaiov1alpha1.ServiceClaim,
+	rs primazaiov1alpha1.RegisteredService,
+) (*primazaiov1alpha1.ServiceClaimApproval, error) {
+	l := log.FromContext(ctx).WithValues("service-claim", sclaim.Name, "registered-service", rs.Name)
+
+	approval := primazaiov1alpha1.ServiceClaimApproval{}
+	k := types.NamespacedName{Namespace: sclaim.Namespace, Name: sclaim.Name}
+	err := r.Get(ctx, k, &approval)
+	switch {
+	case err == nil && approval.Spec.ClaimID == sclaim.Status.ClaimID && approval.Spec.RegisteredServiceName == rs.Name:
+		return &approval, nil
+	case err == nil:
+		l.Info("deleting outdated service claim approval", "approval", approval.Name)
+		if err := r.Delete(ctx, &approval); client.IgnoreNotFound(err) != nil {
+			return nil, err
+		}
+	case !apierrors.IsNotFound(err):
+		return nil, err
+	}
+
+	approval = primazaiov1alpha1.ServiceClaimApproval{
+		ObjectMeta: metav1.ObjectMeta{Name: sclaim.Name, Namespace: sclaim.Namespace},
+		Spec: primazaiov1alpha1.ServiceClaimApprovalSpec{
+			ServiceClaimName:      sclaim.Name,
+			ClaimID:               sclaim.Status.ClaimID,
+			RegisteredServiceName: rs.Name,
+		},
+	}
+	if err := controllerutil.SetControllerReference(&sclaim, &approval, r.Scheme); err != nil {
+		return nil, err
+	}
+	l.Info("creating service claim approval", "approval", approval.Name)
+	if err := r.Create(ctx, &approval); err != nil {
+		return nil, err
+	}
+	return &approval, nil
+}
+
+func approvalRecord(approval primazaiov1alpha1.ServiceClaimApproval) *primazaiov1alpha1.ServiceClaimApprovalRecord {
+	return &primazaiov1alpha1.ServiceClaimApprovalRecord{
+		Decision: approval.Spec.Decision,
+		Approver: approval.Spec.Approver,
+		Time:     approval.Spec.DecisionTime,
+		Reason:   approval.Spec.Reason,
+	}
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/primaza/primaza/api/v1alpha1"
+	"github.com/primaza/primaza/pkg/primaza/constants"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("ServiceClaim approval", func() {
+	newRegisteredService := func(name string, requiresApproval bool) *v1alpha1.RegisteredService {
+		return &v1alpha1.RegisteredService{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "primaza-system", UID: types.UID(name)},
+			Spec: v1alpha1.RegisteredServiceSpec{
+				ServiceClassIdentity:      []v1alpha1.ServiceClassIdentityItem{{Name: "type", Value: "psql"}},
+				ServiceEndpointDefinition: []v1alpha1.ServiceEndpointDefinitionItem{{Name: "host", Value: name}},
+				RequiresApproval:          requiresApproval,
+			},
+			Status: v1alpha1.RegisteredServiceStatus{State: v1alpha1.RegisteredServiceStateClaimed, Claims: []string{"claim-id"}},
+		}
+	}
+	newServiceClaim := func() *v1alpha1.ServiceClaim {
+		return &v1alpha1.ServiceClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "claim", Namespace: "primaza-system", UID: "claim"},
+			Spec: v1alpha1.ServiceClaimSpec{
+				ServiceClassIdentity:          []v1alpha1.ServiceClassIdentityItem{{Name: "type", Value: "psql"}},
+				ServiceEndpointDefinitionKeys: []string{"host"},
+				Target:                        &v1alpha1.ServiceClaimTarget{EnvironmentTag: "prod"},
+			},
+			Status: v1alpha1.ServiceClaimStatus{
+				State:             v1alpha1.ServiceClaimStatePending,
+				ClaimID:           "claim-id",
+				RegisteredService: &corev1.ObjectReference{Name: "prod-database", UID: "prod-database"},
+			},
+		}
+	}
+	newApproval := func(rs string, decision v1alpha1.ServiceClaimApprovalDecision) *v1alpha1.ServiceClaimApproval {
+		now := metav1.Now()
+		return &v1alpha1.ServiceClaimApproval{
+			ObjectMeta: metav1.ObjectMeta{Name: "claim", Namespace: "primaza-system"},
+			Spec: v1alpha1.ServiceClaimApprovalSpec{
+				ServiceClaimName:      "claim",
+				ClaimID:               "claim-id",
+				RegisteredServiceName: rs,
+				Decision:              decision,
+				Reason:                "production data",
+				Approver:              "alice",
+				DecisionTime:          &now,
+			},
+		}
+	}
+	newReconciler := func(objs ...client.Object) (ServiceClaimReconciler, client.Client) {
+		scheme := runtime.NewScheme()
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		cli := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(objs...).
+			WithStatusSubresource(objs...).
+			Build()
+		ctx := context.Background()
+		for _, o := range objs {
+			Expect(cli.Get(ctx, client.ObjectKeyFromObject(o), o)).To(Succeed())
+		}
+		return ServiceClaimReconciler{Client: cli, Scheme: scheme}, cli
+	}
+	approvalKey := client.ObjectKey{Namespace: "primaza-system", Name: "claim"}
+
+	It("should not require approval for unprotected services", func() {
+		sclaim, rs := newServiceClaim(), newRegisteredService("prod-database", false)
+		r, cli := newReconciler(sclaim, rs)
+		ctx := context.Background()
+
+		approved, err := r.approveIfNeeded(ctx, sclaim, *rs)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(approved).To(BeTrue())
+		Expect(cli.Get(ctx, approvalKey, &v1alpha1.ServiceClaimApproval{})).NotTo(Succeed())
+	})
+
+	It("should await approval for protected services", func() {
+		sclaim, rs := newServiceClaim(), newRegisteredService("prod-database", true)
+		r, cli := newReconciler(sclaim, rs)
+		ctx := context.Background()
+
+		approved, err := r.approveIfNeeded(ctx, sclaim, *rs)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(approved).To(BeFalse())
+
+		approval := v1alpha1.ServiceClaimApproval{}
+		Expect(cli.Get(ctx, approvalKey, &approval)).To(Succeed())
+		Expect(approval.Spec.ClaimID).To(Equal("claim-id"))
+		Expect(approval.Spec.RegisteredServiceName).To(Equal("prod-database"))
+		Expect(approval.Spec.Decision).To(BeEmpty())
+		Expect(metav1.IsControlledBy(&approval, sclaim)).To(BeTrue())
+
+		Expect(cli.Get(ctx, client.ObjectKeyFromObject(sclaim), sclaim)).To(Succeed())
+		Expect(sclaim.Status.State).To(Equal(v1alpha1.ServiceClaimStateAwaitingApproval))
+		c := meta.FindStatusCondition(sclaim.Status.Conditions, string(v1alpha1.ServiceClaimConditionReady))
+		Expect(c).NotTo(BeNil())
+		Expect(c.Reason).To(Equal(constants.AwaitingApprovalReason))
+	})
+
+	It("should go on with approved claims", func() {
+		sclaim, rs := newServiceClaim(), newRegisteredService("prod-database", true)
+		r, _ := newReconciler(sclaim, rs, newApproval("prod-database", v1alpha1.ServiceClaimApprovalDecisionApproved))
+		meta.SetStatusCondition(&sclaim.Status.Conditions, metav1.Condition{
+			Type:   string(v1alpha1.ServiceClaimConditionReady),
+			Status: metav1.ConditionFalse,
+			Reason: constants.AwaitingApprovalReason,
+		})
+
+		approved, err := r.approveIfNeeded(context.Background(), sclaim, *rs)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(approved).To(BeTrue())
+		Expect(sclaim.Status.Approval).NotTo(BeNil())
+		Expect(sclaim.Status.Approval.Decision).To(Equal(v1alpha1.ServiceClaimApprovalDecisionApproved))
+		Expect(sclaim.Status.Approval.Approver).To(Equal("alice"))
+		Expect(sclaim.Status.Approval.Time).NotTo(BeNil())
+		Expect(meta.FindStatusCondition(sclaim.Status.Conditions, string(v1alpha1.ServiceClaimConditionReady))).To(BeNil())
+	})
+
+	It("should release the service for rejected claims", func() {
+		sclaim, rs := newServiceClaim(), newRegisteredService("prod-database", true)
+		r, cli := newReconciler(sclaim, rs, newApproval("prod-database", v1alpha1.ServiceClaimApprovalDecisionRejected))
+		ctx := context.Background()
+
+		approved, err := r.approveIfNeeded(ctx, sclaim, *rs)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(approved).To(BeFalse())
+
+		Expect(cli.Get(ctx, client.ObjectKeyFromObject(sclaim), sclaim)).To(Succeed())
+		Expect(sclaim.Status.State).To(Equal(v1alpha1.ServiceClaimStateRejected))
+		Expect(sclaim.Status.Approval.Decision).To(Equal(v1alpha1.ServiceClaimApprovalDecisionRejected))
+		Expect(sclaim.Status.Approval.Reason).To(Equal("production data"))
+		Expect(cli.Get(ctx, client.ObjectKeyFromObject(rs), rs)).To(Succeed())
+		Expect(rs.Status.Claims).To(BeEmpty())
+	})
+
+	It("should replace approvals for another service", func() {
+		sclaim, rs := newServiceClaim(), newRegisteredService("prod-database", true)
+		r, cli := newReconciler(sclaim, rs, newApproval("old-database", v1alpha1.ServiceClaimApprovalDecisionApproved))
+		ctx := context.Background()
+
+		approved, err := r.approveIfNeeded(ctx, sclaim, *rs)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(approved).To(BeFalse())
+
+		approval := v1alpha1.ServiceClaimApproval{}
+		Expect(cli.Get(ctx, approvalKey, &approval)).To(Succeed())
+		Expect(approval.Spec.RegisteredServiceName).To(Equal("prod-database"))
+		Expect(approval.Spec.Decision).To(BeEmpty())
+	})
+})
@@ -0,0 +1,109 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	primazaiov1alpha1 "github.com/primaza/primaza/api/v1alpha1"
+	"github.com/primaza/primaza/pkg/primaza/clustercontext"
+)
+
+const (
+	// BindingsRefreshInterval is the time waited before the first refresh
+	// of the bindings of a Resolved ServiceClaim that are not Ready. It
+	// doubles after each refresh.
+	BindingsRefreshInterval = 30 * time.Second
+	// MaxBindingsRefreshes is the number of refreshes after which the
+	// bindings are not refreshed anymore until the ServiceClaim is
+	// reconciled again
+	MaxBindingsRefreshes = 5
+)
+
+// scheduleBindingsRefresh schedules the refresh of the ServiceClaim's
+// bindings if they are not Ready, unless they have already been refreshed
+// MaxBindingsRefreshes times
+func scheduleBindingsRefresh(s *primazaiov1alpha1.ServiceClaimStatus, attempts int32, now time.Time) {
+	if s.BindingsReady() || attempts >= MaxBindingsRefreshes {
+		s.BindingsRefresh = nil
+		return
+	}
+	s.BindingsRefresh = &primazaiov1alpha1.ServiceClaimBindingsRefresh{
+		Attempts: attempts,
+		Time:     metav1.NewTime(now.Add(BindingsRefreshInterval << attempts)),
+	}
+}
+
+// bindingsRefreshDue checks whether the bindings of the Resolved
+// ServiceClaim only need to be refreshed. The bindings of a ServiceClaim
+// whose RegisteredService is Unreachable are not refreshed, as the
+// ServiceClaim may need to fail over.
+func bindingsRefreshDue(sclaim primazaiov1alpha1.ServiceClaim, now time.Time) bool {
+	return sclaim.Status.State == primazaiov1alpha1.ServiceClaimStateResolved &&
+		sclaim.Status.UnreachableSince == nil &&
+		sclaim.Status.BindingsRefresh != nil &&
+		!now.Before(sclaim.Status.BindingsRefresh.Time.Time)
+}
+
+// bindingsRefreshRequeueAfter returns when the bindings of the ServiceClaim
+// have to be refreshed, or 0 if no refresh is scheduled
+func bindingsRefreshRequeueAfter(sclaim primazaiov1alpha1.ServiceClaim, now time.Time) time.Duration {
+	if sclaim.Status.State != primazaiov1alpha1.ServiceClaimStateResolved || sclaim.Status.BindingsRefresh == nil {
+		return 0
+	}
+	return max(sclaim.Status.BindingsRefresh.Time.Sub(now), time.Second)
+}
+
+// refreshBindings copies the state of the pushed ServiceBindings into the
+// ServiceClaim's bindings, without pushing them again, and schedules the
+// next refresh
+func (r *ServiceClaimReconciler) refreshBindings(ctx context.Context, sclaim *primazaiov1alpha1.ServiceClaim, now time.Time) error {
+	l := log.FromContext(ctx)
+
+	for i := range sclaim.Status.Bindings {
+		b := &sclaim.Status.Bindings[i]
+		if b.LastError != "" {
+			continue
+		}
+
+		ce, err := r.getEnvironmentFromClusterEnvironment(ctx, sclaim.Namespace, b.ClusterEnvironment)
+		if err != nil {
+			l.Info("unable to retrieve cluster environment", "error", err, "cluster environment", b.ClusterEnvironment)
+			continue
+		}
+		cli, err := clustercontext.CreateClient(ctx, r.Client, *ce, r.Scheme, r.Client.RESTMapper())
+		if err != nil {
+			l.Info("unable to create client for cluster environment", "error", err, "cluster environment", ce.Name)
+			continue
+		}
+		sb := primazaiov1alpha1.ServiceBinding{}
+		if err := cli.Get(ctx, types.NamespacedName{Namespace: b.Namespace, Name: sclaim.Name}, &sb); err != nil {
+			l.Info("unable to retrieve ServiceBinding", "error", err, "namespace", b.Namespace)
+			continue
+		}
+		b.State = sb.Status.State
+		b.Workloads = sb.Status.Connections
+	}
+
+	scheduleBindingsRefresh(&sclaim.Status, sclaim.Status.BindingsRefresh.Attempts+1, now)
+	return r.updateServiceClaimStatus(ctx, sclaim)
+}
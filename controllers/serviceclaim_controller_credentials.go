@@ -0,0 +1,697 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	primazaiov1alpha1 "github.com/primaza/primaza/api/v1alpha1"
+	"github.com/primaza/primaza/pkg/primaza/constants"
+)
+
+const (
+	credentialIssuerOperationIssue  = "issue"
+	credentialIssuerOperationRevoke = "revoke"
+
+	credentialIssuerWebhookTimeout = 10 * time.Second
+
+	// credentialIssuerCredentialsPath is where the credentials to revoke are
+	// mounted in the credential issuer container
+	credentialIssuerCredentialsPath = "/var/run/primaza/credentials"
+)
+
+// errCredentialsPending is returned while the per-claim credentials are
+// being issued
+var errCredentialsPending = errors.New("credentials are being issued")
+
+// credentialIssuerRequest is the payload sent to credential issuer webhooks
+type credentialIssuerRequest struct {
+	Operation         string            `json:"operation"`
+	ClaimID           string            `json:"claimID"`
+	ClaimName         string            `json:"claimName"`
+	ClaimNamespace    string            `json:"claimNamespace"`
+	RegisteredService string            `json:"registeredService"`
+	Credentials       map[string]string `json:"credentials,omitempty"`
+}
+
+// credentialIssuerResponse is the payload returned by credential issuer
+// webhooks when issuing credentials
+type credentialIssuerResponse struct {
+	Credentials map[string]string `json:"credentials"`
+}
+
+// issuedKeys returns the ServiceEndpointDefinition keys issued per claim
+// by the RegisteredService's credential issuer
+func issuedKeys(rs primazaiov1alpha1.RegisteredService) []string {
+	if rs.Spec.CredentialIssuer == nil {
+		return nil
+	}
+	return rs.Spec.CredentialIssuer.Keys
+}
+
+func credentialsSecretName(sclaim primazaiov1alpha1.ServiceClaim) string {
+	return sclaim.Name + "-credentials"
+}
+
+// credentialIssuerJobName returns the name of the Job running the given
+// operation. The Secret exchanging credentials with the Job, and the
+// ServiceAccount, Role and RoleBinding of the issuing Job have the same name.
+func credentialIssuerJobName(sclaim primazaiov1alpha1.ServiceClaim, operation string) string {
+	return fmt.Sprintf("credential-%s-%s", operation, sclaim.Status.ClaimID)
+}
+
+// issueCredentials returns the per-claim credentials for the ServiceClaim,
+// issuing them if needed. Issued credentials are stored in a secret owned
+// by the ServiceClaim. It returns errCredentialsPending while the
+// credentials are being issued.
+func (r *ServiceClaimReconciler) issueCredentials(
+	ctx context.Context,
+	sclaim primazaiov1alpha1.ServiceClaim,
+	rs primazaiov1alpha1.RegisteredService,
+) (map[string]string, error) {
+	l := log.FromContext(ctx).WithValues("service-claim", sclaim.Name, "registered-service", rs.Name)
+	ci := rs.Spec.CredentialIssuer
+	if ci == nil {
+		return nil, nil
+	}
+
+	creds, err := r.getIssuedCredentials(ctx, sclaim)
+	if err != nil || creds != nil {
+		return creds, err
+	}
+
+	req := credentialIssuerRequest{
+		Operation:         credentialIssuerOperationIssue,
+		ClaimID:           sclaim.Status.ClaimID,
+		ClaimName:         sclaim.Name,
+		ClaimNamespace:    sclaim.Namespace,
+		RegisteredService: rs.Name,
+	}
+	switch {
+	case ci.Webhook != nil:
+		creds, err = callCredentialIssuerWebhook(ctx, *ci.Webhook, req)
+	case ci.Container != nil:
+		creds, err = r.runCredentialIssuerJob(ctx, sclaim, *ci.Container, req)
+	default:
+		err = fmt.Errorf("credential issuer of registered service %s defines neither a container nor a webhook", rs.Name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, k := range ci.Keys {
+		if _, ok := creds[k]; !ok {
+			err := fmt.Errorf("credential issuer of registered service %s did not issue key %s", rs.Name, k)
+			return nil, r.discardIssuedCredentials(ctx, sclaim, rs, creds, err)
+		}
+	}
+
+	s := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      credentialsSecretName(sclaim),
+			Namespace: sclaim.Namespace,
+		},
+		Data: map[string][]byte{},
+	}
+	stored := map[string]string{}
+	for _, k := range ci.Keys {
+		stored[k] = creds[k]
+		s.Data[k] = []byte(creds[k])
+	}
+	if err := ctrl.SetControllerReference(&sclaim, &s, r.Scheme); err != nil {
+		return nil, r.discardIssuedCredentials(ctx, sclaim, rs, creds, err)
+	}
+	if err := r.Create(ctx, &s); err != nil {
+		l.Error(err, "unable to store issued credentials", "secret", s.Name)
+		return nil, r.discardIssuedCredentials(ctx, sclaim, rs, creds, err)
+	}
+
+	l.Info("credentials issued", "secret", s.Name)
+	return stored, nil
+}
+
+// discardIssuedCredentials revokes credentials that have been issued but
+// can not be stored, as they would otherwise never be revoked. It returns
+// the error that prevented storing them, joined with the revocation one.
+func (r *ServiceClaimReconciler) discardIssuedCredentials(
+	ctx context.Context,
+	sclaim primazaiov1alpha1.ServiceClaim,
+	rs primazaiov1alpha1.RegisteredService,
+	creds map[string]string,
+	err error,
+) error {
+	log.FromContext(ctx).Info("revoking credentials that can not be stored", "error", err)
+	if rerr := r.revokeIssuedCredentials(ctx, sclaim, rs, creds); rerr != nil {
+		return errors.Join(err, fmt.Errorf("unable to revoke issued credentials: %w", rerr))
+	}
+	return err
+}
+
+// revokeIssuedCredentials asks the credential issuer to revoke the given
+// credentials. Container issuers revoke them asynchronously, in a Job
+// awaited by awaitCredentialRevocation.
+func (r *ServiceClaimReconciler) revokeIssuedCredentials(
+	ctx context.Context,
+	sclaim primazaiov1alpha1.ServiceClaim,
+	rs primazaiov1alpha1.RegisteredService,
+	creds map[string]string,
+) error {
+	ci := rs.Spec.CredentialIssuer
+	switch {
+	case ci.Webhook != nil:
+		req := credentialIssuerRequest{
+			Operation:         credentialIssuerOperationRevoke,
+			ClaimID:           sclaim.Status.ClaimID,
+			ClaimName:         sclaim.Name,
+			ClaimNamespace:    sclaim.Namespace,
+			RegisteredService: rs.Name,
+			Credentials:       creds,
+		}
+		_, err := callCredentialIssuerWebhook(ctx, *ci.Webhook, req)
+		return err
+	case ci.Container != nil:
+		// the credentials are handed over to the revoking Job through
+		// a Secret owned by the ServiceClaim
+		return r.createCredentialRevocationSecret(ctx, sclaim, creds)
+	}
+	return nil
+}
+
+// addIssuedCredentials issues the per-claim credentials and adds the ones
+// requested by the ServiceClaim to the secret. It returns false while
+// credentials are being issued. While credentials are being issued, or if
+// their issuing failed, the ServiceClaim's Ready condition is updated
+// accordingly.
+func (r *ServiceClaimReconciler) addIssuedCredentials(
+	ctx context.Context,
+	sclaim *primazaiov1alpha1.ServiceClaim,
+	rs primazaiov1alpha1.RegisteredService,
+	secret *corev1.Secret,
+) (bool, error) {
+	l := log.FromContext(ctx)
+
+	creds, err := r.issueCredentials(ctx, *sclaim, rs)
+	if err != nil {
+		pending := errors.Is(err, errCredentialsPending)
+		reason := constants.CredentialsIssuingFailedReason
+		if pending {
+			reason = constants.CredentialsPendingReason
+		}
+		c := metav1.Condition{
+			LastTransitionTime: metav1.Now(),
+			Type:               string(primazaiov1alpha1.ServiceClaimConditionReady),
+			Status:             metav1.ConditionFalse,
+			Reason:             reason,
+			Message:            err.Error(),
+		}
+		meta.SetStatusCondition(&sclaim.Status.Conditions, c)
+
+		if err := r.updateServiceClaimStatus(ctx, sclaim); err != nil {
+			l.Error(err, "unable to update the ServiceClaim", "ServiceClaim", sclaim)
+			return false, err
+		}
+
+		// the credential issuer Job is owned by the ServiceClaim,
+		// that is reconciled again when the Job completes
+		if pending {
+			l.Info("waiting for the credentials to be issued")
+			return false, nil
+		}
+		return false, err
+	}
+
+	for k, v := range creds {
+		if slices.Contains(sclaim.Spec.ServiceEndpointDefinitionKeys, k) {
+			secret.StringData[k] = v
+		}
+	}
+	return true, nil
+}
+
+// revokeCredentials revokes the per-claim credentials issued for the
+// ServiceClaim, if any, and deletes the secret storing them.
+// It returns false while a credential issuer Job is revoking them.
+func (r *ServiceClaimReconciler) revokeCredentials(
+	ctx context.Context,
+	sclaim primazaiov1alpha1.ServiceClaim,
+	rs primazaiov1alpha1.RegisteredService,
+) (bool, error) {
+	l := log.FromContext(ctx).WithValues("service-claim", sclaim.Name, "registered-service", rs.Name)
+	ci := rs.Spec.CredentialIssuer
+	if ci == nil {
+		return true, nil
+	}
+
+	// a previous revocation may still be in progress, e.g. after a failover
+	if done, err := r.awaitCredentialRevocation(ctx, sclaim, &rs); err != nil || !done {
+		return false, err
+	}
+
+	creds, err := r.getIssuedCredentials(ctx, sclaim)
+	if err != nil || creds == nil {
+		return err == nil, err
+	}
+
+	if err := r.revokeIssuedCredentials(ctx, sclaim, rs, creds); err != nil {
+		return false, err
+	}
+
+	s := corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: credentialsSecretName(sclaim), Namespace: sclaim.Namespace}}
+	if err := r.Delete(ctx, &s); client.IgnoreNotFound(err) != nil {
+		return false, err
+	}
+
+	if ci.Container != nil {
+		l.Info("revoking credentials")
+		return r.awaitCredentialRevocation(ctx, sclaim, &rs)
+	}
+	l.Info("credentials revoked")
+	return true, nil
+}
+
+// getIssuedCredentials returns the credentials issued for the ServiceClaim,
+// or nil if no credentials have been issued
+func (r *ServiceClaimReconciler) getIssuedCredentials(ctx context.Context, sclaim primazaiov1alpha1.ServiceClaim) (map[string]string, error) {
+	s := corev1.Secret{}
+	k := types.NamespacedName{Namespace: sclaim.Namespace, Name: credentialsSecretName(sclaim)}
+	if err := r.Get(ctx, k, &s); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+
+	creds := map[string]string{}
+	for k, v := range s.Data {
+		creds[k] = string(v)
+	}
+	return creds, nil
+}
+
+func callCredentialIssuerWebhook(
+	ctx context.Context,
+	webhook primazaiov1alpha1.CredentialIssuerWebhook,
+	req credentialIssuerRequest,
+) (map[string]string, error) {
+	tc := &tls.Config{MinVersion: tls.VersionTLS12}
+	if len(webhook.CABundle) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(webhook.CABundle) {
+			return nil, fmt.Errorf("invalid CA bundle for credential issuer webhook %s", webhook.URL)
+		}
+		tc.RootCAs = pool
+	}
+	cli := http.Client{
+		Timeout:   credentialIssuerWebhookTimeout,
+		Transport: &http.Transport{TLSClientConfig: tc},
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	hreq, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	hreq.Header.Set("Content-Type", "application/json")
+
+	res, err := cli.Do(hreq)
+	if err != nil {
+		return nil, fmt.Errorf("error calling credential issuer webhook %s: %w", webhook.URL, err)
+	}
+	defer res.Body.Close()
+
+	rb, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, fmt.Errorf("credential issuer webhook %s replied with status %d: %s", webhook.URL, res.StatusCode, rb)
+	}
+	if req.Operation != credentialIssuerOperationIssue {
+		return nil, nil
+	}
+
+	cr := credentialIssuerResponse{}
+	if err := json.Unmarshal(rb, &cr); err != nil {
+		return nil, fmt.Errorf("invalid response from credential issuer webhook %s: %w", webhook.URL, err)
+	}
+	return cr.Credentials, nil
+}
+
+// runCredentialIssuerJob runs the credential issuer container in a Job and
+// returns the credentials it wrote to its output Secret.
+// It returns errCredentialsPending until the Job completes.
+func (r *ServiceClaimReconciler) runCredentialIssuerJob(
+	ctx context.Context,
+	sclaim primazaiov1alpha1.ServiceClaim,
+	container primazaiov1alpha1.CredentialIssuerContainer,
+	req credentialIssuerRequest,
+) (map[string]string, error) {
+	l := log.FromContext(ctx).WithValues("service-claim", sclaim.Name)
+
+	job := batchv1.Job{}
+	k := types.NamespacedName{Namespace: sclaim.Namespace, Name: credentialIssuerJobName(sclaim, req.Operation)}
+	if err := r.Get(ctx, k, &job); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		if err := r.createCredentialIssuerOutput(ctx, sclaim); err != nil {
+			return nil, err
+		}
+		if err := r.createCredentialIssuerJob(ctx, sclaim, container, req); err != nil {
+			return nil, err
+		}
+		return nil, errCredentialsPending
+	}
+
+	switch c := jobResult(job); {
+	case c == nil:
+		return nil, errCredentialsPending
+	case c.Type == batchv1.JobFailed:
+		// delete the job so that it is run again at next reconciliation
+		if err := r.deleteCredentialIssuerJob(ctx, job); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("credential issuer job %s failed: %s", job.Name, c.Message)
+	default:
+		creds, err := r.readCredentialIssuerJobResult(ctx, job)
+		if err != nil {
+			return nil, err
+		}
+		l.Info("credential issuer job completed", "job", job.Name)
+		return creds, r.deleteCredentialIssuerObjects(ctx, sclaim, job)
+	}
+}
+
+// awaitCredentialRevocation waits for the Job revoking the credentials
+// stored in the ServiceClaim's revocation Secret, if any, and cleans them
+// up once the Job completes. It returns true when no revocation is in
+// progress.
+// Failed Jobs are run again with the RegisteredService's credential issuer.
+// If the RegisteredService is nil, the revocation is given up instead.
+func (r *ServiceClaimReconciler) awaitCredentialRevocation(
+	ctx context.Context,
+	sclaim primazaiov1alpha1.ServiceClaim,
+	rs *primazaiov1alpha1.RegisteredService,
+) (bool, error) {
+	l := log.FromContext(ctx).WithValues("service-claim", sclaim.Name)
+
+	k := types.NamespacedName{Namespace: sclaim.Namespace, Name: credentialIssuerJobName(sclaim, credentialIssuerOperationRevoke)}
+	s := corev1.Secret{}
+	if err := r.Get(ctx, k, &s); err != nil {
+		return apierrors.IsNotFound(err), client.IgnoreNotFound(err)
+	}
+
+	var container *primazaiov1alpha1.CredentialIssuerContainer
+	if rs != nil && rs.Spec.CredentialIssuer != nil {
+		container = rs.Spec.CredentialIssuer.Container
+	}
+
+	job := batchv1.Job{}
+	if err := r.Get(ctx, k, &job); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return false, err
+		}
+		if container == nil {
+			l.Info("no credential issuer to revoke the credentials, giving up", "secret", s.Name)
+			return true, client.IgnoreNotFound(r.Delete(ctx, &s))
+		}
+		req := credentialIssuerRequest{
+			Operation:         credentialIssuerOperationRevoke,
+			ClaimID:           sclaim.Status.ClaimID,
+			ClaimName:         sclaim.Name,
+			ClaimNamespace:    sclaim.Namespace,
+			RegisteredService: rs.Name,
+		}
+		return false, r.createCredentialIssuerJob(ctx, sclaim, *container, req)
+	}
+
+	switch c := jobResult(job); {
+	case c == nil:
+		return false, nil
+	case c.Type == batchv1.JobFailed:
+		err := fmt.Errorf("credential issuer job %s failed: %s", job.Name, c.Message)
+		if container != nil {
+			// delete the job so that it is run again at next reconciliation
+			return false, errors.Join(err, r.deleteCredentialIssuerJob(ctx, job))
+		}
+		return true, errors.Join(err, r.deleteCredentialIssuerObjects(ctx, sclaim, job))
+	default:
+		l.Info("credentials revoked", "job", job.Name)
+		return true, r.deleteCredentialIssuerObjects(ctx, sclaim, job)
+	}
+}
+
+// jobResult returns the Job's Complete or Failed condition, or nil if the
+// Job is still running
+func jobResult(job batchv1.Job) *batchv1.JobCondition {
+	for _, c := range job.Status.Conditions {
+		if c.Status == corev1.ConditionTrue && (c.Type == batchv1.JobComplete || c.Type == batchv1.JobFailed) {
+			return &c
+		}
+	}
+	return nil
+}
+
+// readCredentialIssuerJobResult reads the credentials written by the
+// credential issuer container to its output Secret
+func (r *ServiceClaimReconciler) readCredentialIssuerJobResult(ctx context.Context, job batchv1.Job) (map[string]string, error) {
+	s := corev1.Secret{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(&job), &s); err != nil {
+		return nil, err
+	}
+	if len(s.Data) == 0 {
+		return nil, fmt.Errorf("credential issuer job %s did not write any credentials to secret %s", job.Name, s.Name)
+	}
+
+	creds := map[string]string{}
+	for k, v := range s.Data {
+		creds[k] = string(v)
+	}
+	return creds, nil
+}
+
+// createCredentialRevocationSecret stores the credentials to revoke in a
+// Secret owned by the ServiceClaim, that is mounted by the revoking Job
+func (r *ServiceClaimReconciler) createCredentialRevocationSecret(
+	ctx context.Context,
+	sclaim primazaiov1alpha1.ServiceClaim,
+	creds map[string]string,
+) error {
+	s := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      credentialIssuerJobName(sclaim, credentialIssuerOperationRevoke),
+			Namespace: sclaim.Namespace,
+		},
+		Data: map[string][]byte{},
+	}
+	for k, v := range creds {
+		s.Data[k] = []byte(v)
+	}
+	return r.createOwnedCredentialIssuerObject(ctx, sclaim, &s)
+}
+
+// createCredentialIssuerOutput creates the Secret the issuing Job writes the
+// credentials to, and the ServiceAccount allowed to update it.
+// They are owned by the ServiceClaim.
+func (r *ServiceClaimReconciler) createCredentialIssuerOutput(ctx context.Context, sclaim primazaiov1alpha1.ServiceClaim) error {
+	om := metav1.ObjectMeta{
+		Name:      credentialIssuerJobName(sclaim, credentialIssuerOperationIssue),
+		Namespace: sclaim.Namespace,
+	}
+	oo := []client.Object{
+		&corev1.Secret{ObjectMeta: om},
+		&corev1.ServiceAccount{ObjectMeta: om},
+		&rbacv1.Role{
+			ObjectMeta: om,
+			Rules: []rbacv1.PolicyRule{{
+				APIGroups:     []string{""},
+				Resources:     []string{"secrets"},
+				ResourceNames: []string{om.Name},
+				Verbs:         []string{"get", "update", "patch"},
+			}},
+		},
+		&rbacv1.RoleBinding{
+			ObjectMeta: om,
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: om.Name},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: om.Name, Namespace: om.Namespace}},
+		},
+	}
+	for _, o := range oo {
+		if err := r.createOwnedCredentialIssuerObject(ctx, sclaim, o); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *ServiceClaimReconciler) createOwnedCredentialIssuerObject(
+	ctx context.Context,
+	sclaim primazaiov1alpha1.ServiceClaim,
+	obj client.Object,
+) error {
+	if err := ctrl.SetControllerReference(&sclaim, obj, r.Scheme); err != nil {
+		return err
+	}
+	if err := r.Create(ctx, obj); err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+func (r *ServiceClaimReconciler) deleteCredentialIssuerJob(ctx context.Context, job batchv1.Job) error {
+	p := metav1.DeletePropagationBackground
+	return client.IgnoreNotFound(r.Delete(ctx, &job, &client.DeleteOptions{PropagationPolicy: &p}))
+}
+
+// deleteCredentialIssuerObjects deletes the finished credential issuer Job,
+// the Secret it exchanged the credentials through, and its ServiceAccount,
+// Role and RoleBinding, if any
+func (r *ServiceClaimReconciler) deleteCredentialIssuerObjects(
+	ctx context.Context,
+	sclaim primazaiov1alpha1.ServiceClaim,
+	job batchv1.Job,
+) error {
+	if err := r.deleteCredentialIssuerJob(ctx, job); err != nil {
+		return err
+	}
+
+	om := metav1.ObjectMeta{Name: job.Name, Namespace: job.Namespace}
+	oo := []client.Object{
+		&corev1.Secret{ObjectMeta: om},
+		&corev1.ServiceAccount{ObjectMeta: om},
+		&rbacv1.Role{ObjectMeta: om},
+		&rbacv1.RoleBinding{ObjectMeta: om},
+	}
+	errs := []error{}
+	for _, o := range oo {
+		errs = append(errs, client.IgnoreNotFound(r.Delete(ctx, o)))
+	}
+	return errors.Join(errs...)
+}
+
+// createCredentialIssuerJob creates the Job running the credential issuer
+// container. The Job is owned by the ServiceClaim.
+// The issuing Job runs with a ServiceAccount allowed to write the
+// credentials to its output Secret, named in the `PRIMAZA_CREDENTIALS_SECRET`
+// environment variable. The revoking Job mounts the credentials to revoke at
+// the path in the `PRIMAZA_CREDENTIALS_PATH` environment variable.
+func (r *ServiceClaimReconciler) createCredentialIssuerJob(
+	ctx context.Context,
+	sclaim primazaiov1alpha1.ServiceClaim,
+	container primazaiov1alpha1.CredentialIssuerContainer,
+	req credentialIssuerRequest,
+) error {
+	var two int32 = 2
+	var mode int32 = 0400
+	var uid int64 = 65530
+	t := true
+	f := false
+
+	name := credentialIssuerJobName(sclaim, req.Operation)
+	env := []corev1.EnvVar{
+		{Name: "PRIMAZA_CREDENTIAL_OPERATION", Value: req.Operation},
+		{Name: "PRIMAZA_CLAIM_ID", Value: req.ClaimID},
+		{Name: "PRIMAZA_CLAIM_NAME", Value: req.ClaimName},
+		{Name: "PRIMAZA_CLAIM_NAMESPACE", Value: req.ClaimNamespace},
+		{Name: "PRIMAZA_REGISTERED_SERVICE", Value: req.RegisteredService},
+	}
+
+	job := batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: sclaim.Namespace,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &two,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:            "credential-issuer",
+							Image:           container.Image,
+							Command:         container.Command,
+							ImagePullPolicy: corev1.PullIfNotPresent,
+							SecurityContext: &corev1.SecurityContext{
+								Capabilities: &corev1.Capabilities{
+									Drop: []corev1.Capability{"ALL"},
+								},
+								Privileged:               &f,
+								RunAsNonRoot:             &t,
+								AllowPrivilegeEscalation: &f,
+							},
+						},
+					},
+					AutomountServiceAccountToken: &f,
+					RestartPolicy:                corev1.RestartPolicyNever,
+					SecurityContext: &corev1.PodSecurityContext{
+						RunAsUser:    &uid,
+						RunAsGroup:   &uid,
+						RunAsNonRoot: &t,
+						SeccompProfile: &corev1.SeccompProfile{
+							Type: corev1.SeccompProfileTypeRuntimeDefault,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	ps := &job.Spec.Template.Spec
+	switch req.Operation {
+	case credentialIssuerOperationIssue:
+		ps.ServiceAccountName = name
+		ps.AutomountServiceAccountToken = &t
+		env = append(env, corev1.EnvVar{Name: "PRIMAZA_CREDENTIALS_SECRET", Value: name})
+	case credentialIssuerOperationRevoke:
+		ps.Volumes = []corev1.Volume{{
+			Name: "credentials",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{SecretName: name, DefaultMode: &mode},
+			},
+		}}
+		ps.Containers[0].VolumeMounts = []corev1.VolumeMount{{
+			Name:      "credentials",
+			MountPath: credentialIssuerCredentialsPath,
+			ReadOnly:  true,
+		}}
+		env = append(env, corev1.EnvVar{Name: "PRIMAZA_CREDENTIALS_PATH", Value: credentialIssuerCredentialsPath})
+	}
+	ps.Containers[0].Env = append(env, container.Env...)
+
+	return r.createOwnedCredentialIssuerObject(ctx, sclaim, &job)
+}
@@ -0,0 +1,219 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/primaza/primaza/api/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+var _ = Describe("ServiceClaim credential issuer", func() {
+	var (
+		ctx      context.Context
+		r        ServiceClaimReconciler
+		server   *httptest.Server
+		requests []credentialIssuerRequest
+		sclaim   v1alpha1.ServiceClaim
+		rs       v1alpha1.RegisteredService
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		requests = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			cr := credentialIssuerRequest{}
+			Expect(json.NewDecoder(req.Body).Decode(&cr)).To(Succeed())
+			requests = append(requests, cr)
+			_ = json.NewEncoder(w).Encode(credentialIssuerResponse{
+				Credentials: map[string]string{"username": "user-" + cr.ClaimID, "password": "secret", "extra": "ignored"},
+			})
+		}))
+
+		scheme := runtime.NewScheme()
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(batchv1.AddToScheme(scheme)).To(Succeed())
+		Expect(rbacv1.AddToScheme(scheme)).To(Succeed())
+		r = ServiceClaimReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&batchv1.Job{}).Build(), Scheme: scheme}
+
+		sclaim = v1alpha1.ServiceClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "claim", Namespace: "primaza-system", UID: "claim-uid"},
+			Status:     v1alpha1.ServiceClaimStatus{ClaimID: "claim-1"},
+		}
+		rs = v1alpha1.RegisteredService{
+			ObjectMeta: metav1.ObjectMeta{Name: "rs", Namespace: "primaza-system"},
+			Spec: v1alpha1.RegisteredServiceSpec{
+				CredentialIssuer: &v1alpha1.CredentialIssuer{
+					Keys:    []string{"username", "password"},
+					Webhook: &v1alpha1.CredentialIssuerWebhook{URL: server.URL},
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should issue credentials once and revoke them", func() {
+		creds, err := r.issueCredentials(ctx, sclaim, rs)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(creds).To(Equal(map[string]string{"username": "user-claim-1", "password": "secret"}))
+
+		// issued credentials are reused
+		creds, err = r.issueCredentials(ctx, sclaim, rs)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(creds).To(HaveKeyWithValue("username", "user-claim-1"))
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].Operation).To(Equal(credentialIssuerOperationIssue))
+
+		revoked, err := r.revokeCredentials(ctx, sclaim, rs)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(revoked).To(BeTrue())
+		Expect(requests).To(HaveLen(2))
+		Expect(requests[1].Operation).To(Equal(credentialIssuerOperationRevoke))
+		Expect(requests[1].Credentials).To(Equal(creds))
+
+		s := corev1.Secret{}
+		err = r.Get(ctx, client.ObjectKey{Namespace: sclaim.Namespace, Name: credentialsSecretName(sclaim)}, &s)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should fail if a key is not issued", func() {
+		rs.Spec.CredentialIssuer.Keys = append(rs.Spec.CredentialIssuer.Keys, "token")
+
+		_, err := r.issueCredentials(ctx, sclaim, rs)
+		Expect(err).To(HaveOccurred())
+		Expect(requests).To(HaveLen(2))
+		Expect(requests[1].Operation).To(Equal(credentialIssuerOperationRevoke))
+	})
+
+	It("should revoke the issued credentials if they can not be stored", func() {
+		r.Client = interceptor.NewClient(r.Client.(client.WithWatch), interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				if _, ok := obj.(*corev1.Secret); ok {
+					return apierrors.NewInternalError(errors.New("unavailable"))
+				}
+				return c.Create(ctx, obj, opts...)
+			},
+		})
+
+		_, err := r.issueCredentials(ctx, sclaim, rs)
+		Expect(err).To(HaveOccurred())
+		Expect(requests).To(HaveLen(2))
+		Expect(requests[1].Operation).To(Equal(credentialIssuerOperationRevoke))
+		Expect(requests[1].Credentials).To(HaveKeyWithValue("username", "user-claim-1"))
+	})
+
+	Describe("container", func() {
+		BeforeEach(func() {
+			rs.Spec.CredentialIssuer.Webhook = nil
+			rs.Spec.CredentialIssuer.Container = &v1alpha1.CredentialIssuerContainer{Image: "issuer", Command: []string{"issue"}}
+		})
+
+		complete := func(name string) {
+			job := batchv1.Job{}
+			Expect(r.Get(ctx, client.ObjectKey{Namespace: sclaim.Namespace, Name: name}, &job)).To(Succeed())
+			job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+			Expect(r.Status().Update(ctx, &job)).To(Succeed())
+		}
+
+		It("should read the issued credentials from the output secret", func() {
+			name := credentialIssuerJobName(sclaim, credentialIssuerOperationIssue)
+
+			_, err := r.issueCredentials(ctx, sclaim, rs)
+			Expect(err).To(MatchError(errCredentialsPending))
+
+			job := batchv1.Job{}
+			Expect(r.Get(ctx, client.ObjectKey{Namespace: sclaim.Namespace, Name: name}, &job)).To(Succeed())
+			Expect(job.OwnerReferences).To(HaveLen(1))
+			Expect(job.Spec.Template.Spec.ServiceAccountName).To(Equal(name))
+			Expect(job.Spec.Template.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "PRIMAZA_CREDENTIALS_SECRET", Value: name}))
+			role := rbacv1.Role{}
+			Expect(r.Get(ctx, client.ObjectKey{Namespace: sclaim.Namespace, Name: name}, &role)).To(Succeed())
+			Expect(role.Rules[0].ResourceNames).To(Equal([]string{name}))
+
+			// the issuer writes the credentials to the output secret
+			out := corev1.Secret{}
+			Expect(r.Get(ctx, client.ObjectKey{Namespace: sclaim.Namespace, Name: name}, &out)).To(Succeed())
+			out.Data = map[string][]byte{"username": []byte("user"), "password": []byte("secret")}
+			Expect(r.Update(ctx, &out)).To(Succeed())
+			complete(name)
+
+			creds, err := r.issueCredentials(ctx, sclaim, rs)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(creds).To(Equal(map[string]string{"username": "user", "password": "secret"}))
+			for _, o := range []client.Object{&batchv1.Job{}, &corev1.Secret{}, &corev1.ServiceAccount{}, &rbacv1.Role{}, &rbacv1.RoleBinding{}} {
+				err := r.Get(ctx, client.ObjectKey{Namespace: sclaim.Namespace, Name: name}, o)
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			}
+		})
+
+		It("should mount the credentials to revoke from a secret", func() {
+			name := credentialIssuerJobName(sclaim, credentialIssuerOperationRevoke)
+			creds := corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: sclaim.Namespace, Name: credentialsSecretName(sclaim)},
+				Data:       map[string][]byte{"username": []byte("user"), "password": []byte("secret")},
+			}
+			Expect(r.Create(ctx, &creds)).To(Succeed())
+
+			revoked, err := r.revokeCredentials(ctx, sclaim, rs)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(revoked).To(BeFalse())
+
+			in := corev1.Secret{}
+			Expect(r.Get(ctx, client.ObjectKey{Namespace: sclaim.Namespace, Name: name}, &in)).To(Succeed())
+			Expect(in.Data).To(Equal(creds.Data))
+			Expect(in.OwnerReferences).To(HaveLen(1))
+			job := batchv1.Job{}
+			Expect(r.Get(ctx, client.ObjectKey{Namespace: sclaim.Namespace, Name: name}, &job)).To(Succeed())
+			Expect(job.Spec.Template.Spec.Volumes[0].Secret.SecretName).To(Equal(name))
+			for _, e := range job.Spec.Template.Spec.Containers[0].Env {
+				Expect(e.Value).NotTo(ContainSubstring("secret"))
+			}
+
+			revoked, err = r.revokeCredentials(ctx, sclaim, rs)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(revoked).To(BeFalse())
+
+			complete(name)
+			revoked, err = r.revokeCredentials(ctx, sclaim, rs)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(revoked).To(BeTrue())
+			err = r.Get(ctx, client.ObjectKey{Namespace: sclaim.Namespace, Name: name}, &in)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			err = r.Get(ctx, client.ObjectKeyFromObject(&creds), &creds)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,276 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	primazaiov1alpha1 "github.com/primaza/primaza/api/v1alpha1"
+	"github.com/primaza/primaza/pkg/envtag"
+)
+
+// ServiceClaimExplanationPath is the path of the HTTP endpoint explaining
+// ServiceClaims, served as `<path><namespace>/<name>`
+const ServiceClaimExplanationPath = "/explain/serviceclaims/"
+
+// maxExplainedCandidates is the number of RegisteredServices recorded in a
+// ServiceClaim's explanation
+const maxExplainedCandidates = 20
+
+// explainServiceClaim evaluates each RegisteredService against the
+// ServiceClaim and records all the reasons why it can not resolve it.
+// Candidates with fewer rejections come first.
+func explainServiceClaim(
+	sclaim primazaiov1alpha1.ServiceClaim,
+	env envtag.Environment,
+	rss []primazaiov1alpha1.RegisteredService,
+) primazaiov1alpha1.ServiceClaimExplanation {
+	candidates := make([]primazaiov1alpha1.ServiceClaimCandidate, 0, len(rss))
+	for _, rs := range rss {
+		candidates = append(candidates, primazaiov1alpha1.ServiceClaimCandidate{
+			Name:       rs.Name,
+			Rejections: rejectionsOf(sclaim, env, rs),
+		})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if li, lj := len(candidates[i].Rejections), len(candidates[j].Rejections); li != lj {
+			return li < lj
+		}
+		return candidates[i].Name < candidates[j].Name
+	})
+
+	explanation := primazaiov1alpha1.ServiceClaimExplanation{Environment: env.Name, Candidates: candidates}
+	if len(candidates) > maxExplainedCandidates {
+		explanation.Candidates = candidates[:maxExplainedCandidates]
+		explanation.Truncated = true
+	}
+	return explanation
+}
+
+// rejectionsOf returns why the RegisteredService can not resolve the
+// ServiceClaim
+func rejectionsOf(
+	sclaim primazaiov1alpha1.ServiceClaim,
+	env envtag.Environment,
+	rs primazaiov1alpha1.RegisteredService,
+) []primazaiov1alpha1.ServiceClaimRejection {
+	rr := []primazaiov1alpha1.ServiceClaimRejection{}
+
+	if mi := missingIdentityItems(sclaim.Spec.ServiceClassIdentity, rs.Spec.ServiceClassIdentity); len(mi) > 0 {
+		rr = append(rr, primazaiov1alpha1.ServiceClaimRejection{
+			Reason:  primazaiov1alpha1.ServiceClaimRejectionIdentityMismatch,
+			Message: fmt.Sprintf("service class identity does not include %s", strings.Join(mi, ", ")),
+			Items:   mi,
+		})
+	}
+
+	if mk := missingServiceEndpointDefinitionKeys(rs, sclaim.Spec.ServiceEndpointDefinitionKeys); len(mk) > 0 {
+		rr = append(rr, primazaiov1alpha1.ServiceClaimRejection{
+			Reason:  primazaiov1alpha1.ServiceClaimRejectionMissingKeys,
+			Message: fmt.Sprintf("missing service endpoint definition keys: %s", strings.Join(mk, ", ")),
+			Items:   mk,
+		})
+	}
+
+	if rs.Spec.ReservedFor != "" && rs.Spec.ReservedFor != sclaim.Status.ClaimID {
+		rr = append(rr, primazaiov1alpha1.ServiceClaimRejection{
+			Reason:  primazaiov1alpha1.ServiceClaimRejectionReserved,
+			Message: "registered service has been provisioned for another service claim",
+		})
+	}
+
+	if rs.Spec.Constraints != nil && !envtag.MatchEnvironment(env, rs.Spec.Constraints.Environments) {
+		rr = append(rr, primazaiov1alpha1.ServiceClaimRejection{
+			Reason:  primazaiov1alpha1.ServiceClaimRejectionEnvironmentConstraint,
+			Message: fmt.Sprintf("environment constraints %v are not satisfied by environment '%s'", rs.Spec.Constraints.Environments, env.Name),
+		})
+	}
+
+	p := primazaiov1alpha1.ServiceClaimSelectionPolicy{}
+	if sclaim.Spec.SelectionPolicy != nil {
+		p = *sclaim.Spec.SelectionPolicy
+	}
+	switch {
+	case isClaimableState(rs.Status.State, p):
+	case rs.Status.State == primazaiov1alpha1.RegisteredServiceStateClaimed:
+		rr = append(rr, primazaiov1alpha1.ServiceClaimRejection{
+			Reason:  primazaiov1alpha1.ServiceClaimRejectionClaimed,
+			Message: "registered service can not accept more claims",
+		})
+	case rs.Status.State == primazaiov1alpha1.RegisteredServiceStateUnreachable:
+		rr = append(rr, primazaiov1alpha1.ServiceClaimRejection{
+			Reason:  primazaiov1alpha1.ServiceClaimRejectionUnreachable,
+			Message: "registered service is unreachable",
+		})
+	default:
+		rr = append(rr, primazaiov1alpha1.ServiceClaimRejection{
+			Reason:  primazaiov1alpha1.ServiceClaimRejectionNotClaimable,
+			Message: fmt.Sprintf("state is %s", rs.Status.State),
+		})
+	}
+
+	return rr
+}
+
+// missingIdentityItems returns the ServiceClaim's ServiceClassIdentity items
+// not included in the RegisteredService's ones, formatted as `name=value`
+func missingIdentityItems(serviceClaim, registeredService []primazaiov1alpha1.ServiceClassIdentityItem) []string {
+	mi := []string{}
+	for _, i := range serviceClaim {
+		if !checkSCISubset([]primazaiov1alpha1.ServiceClassIdentityItem{i}, registeredService) {
+			mi = append(mi, serviceClassIdentityIndexValue(i))
+		}
+	}
+	return mi
+}
+
+// serviceClaimExplanationResponse is the body of the replies of the
+// ServiceClaim explanation endpoint
+type serviceClaimExplanationResponse struct {
+	Namespace         string                                    `json:"namespace"`
+	Name              string                                    `json:"name"`
+	State             primazaiov1alpha1.ServiceClaimState       `json:"state"`
+	RegisteredService string                                    `json:"registeredService,omitempty"`
+	Explanation       primazaiov1alpha1.ServiceClaimExplanation `json:"explanation"`
+}
+
+// ExplanationHandler returns the read-only HTTP handler explaining, for the
+// ServiceClaim in the request's path, why each RegisteredService in its
+// namespace can or can not resolve it.
+// Requests need a bearer token authenticating a user allowed to get the
+// ServiceClaim.
+func (r *ServiceClaimReconciler) ExplanationHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+		l := log.FromContext(ctx).WithValues("path", req.URL.Path)
+
+		if req.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		ns, name, ok := strings.Cut(strings.TrimPrefix(req.URL.Path, ServiceClaimExplanationPath), "/")
+		if !ok || ns == "" || name == "" || strings.Contains(name, "/") {
+			http.Error(w, fmt.Sprintf("expected path %s<namespace>/<name>", ServiceClaimExplanationPath), http.StatusNotFound)
+			return
+		}
+
+		if status, err := r.authorizeExplanation(ctx, req, ns, name); err != nil {
+			l.Info("explanation request not authorized", "error", err)
+			http.Error(w, err.Error(), status)
+			return
+		}
+
+		sclaim := primazaiov1alpha1.ServiceClaim{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: ns, Name: name}, &sclaim); err != nil {
+			if apierrors.IsNotFound(err) {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+			l.Error(err, "unable to retrieve ServiceClaim")
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		env, err := r.serviceClaimEnvironment(ctx, sclaim)
+		if err != nil {
+			l.Error(err, "unable to get environment from cluster environment")
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		rsl, err := r.listRegisteredServices(ctx, ns)
+		if err != nil {
+			l.Error(err, "unable to retrieve RegisteredServiceList")
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		res := serviceClaimExplanationResponse{
+			Namespace:   ns,
+			Name:        name,
+			State:       sclaim.Status.State,
+			Explanation: explainServiceClaim(sclaim, env, rsl.Items),
+		}
+		if sclaim.Status.RegisteredService != nil {
+			res.RegisteredService = sclaim.Status.RegisteredService.Name
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(res); err != nil {
+			l.Error(err, "unable to write ServiceClaim explanation")
+		}
+	})
+}
+
+// authorizeExplanation checks, with a TokenReview and a SubjectAccessReview,
+// that the request's bearer token authenticates a user allowed to get the
+// ServiceClaim. On failure, it returns the HTTP status to reply with.
+func (r *ServiceClaimReconciler) authorizeExplanation(ctx context.Context, req *http.Request, namespace, name string) (int, error) {
+	token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return http.StatusUnauthorized, fmt.Errorf("missing bearer token")
+	}
+
+	tr := authenticationv1.TokenReview{Spec: authenticationv1.TokenReviewSpec{Token: token}}
+	if err := r.Create(ctx, &tr); err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("unable to review token: %w", err)
+	}
+	if !tr.Status.Authenticated {
+		return http.StatusUnauthorized, fmt.Errorf("invalid bearer token")
+	}
+
+	u := tr.Status.User
+	extra := make(map[string]authorizationv1.ExtraValue, len(u.Extra))
+	for k, v := range u.Extra {
+		extra[k] = authorizationv1.ExtraValue(v)
+	}
+	sar := authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   u.Username,
+			UID:    u.UID,
+			Groups: u.Groups,
+			Extra:  extra,
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      "get",
+				Group:     primazaiov1alpha1.GroupVersion.Group,
+				Resource:  "serviceclaims",
+				Name:      name,
+			},
+		},
+	}
+	if err := r.Create(ctx, &sar); err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("unable to review access: %w", err)
+	}
+	if !sar.Status.Allowed {
+		return http.StatusForbidden, fmt.Errorf("user %s is not allowed to get serviceclaim %s/%s", u.Username, namespace, name)
+	}
+	return http.StatusOK, nil
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/primaza/primaza/api/v1alpha1"
+	"github.com/primaza/primaza/pkg/envtag"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+var _ = Describe("ServiceClaim explanation", func() {
+	registeredService := func(name string, state v1alpha1.RegisteredServiceState, mutate ...func(*v1alpha1.RegisteredService)) v1alpha1.RegisteredService {
+		rs := v1alpha1.RegisteredService{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "primaza-system"},
+			Spec: v1alpha1.RegisteredServiceSpec{
+				ServiceClassIdentity:      []v1alpha1.ServiceClassIdentityItem{{Name: "type", Value: "psql"}, {Name: "provider", Value: "aws"}},
+				ServiceEndpointDefinition: []v1alpha1.ServiceEndpointDefinitionItem{{Name: "host", Value: "localhost"}},
+			},
+			Status: v1alpha1.RegisteredServiceStatus{State: state},
+		}
+		for _, m := range mutate {
+			m(&rs)
+		}
+		return rs
+	}
+	sclaim := v1alpha1.ServiceClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "claim", Namespace: "primaza-system"},
+		Spec: v1alpha1.ServiceClaimSpec{
+			ServiceClassIdentity:          []v1alpha1.ServiceClassIdentityItem{{Name: "type", Value: "psql"}, {Name: "provider", Value: "aws"}},
+			ServiceEndpointDefinitionKeys: []string{"host"},
+			Target:                        &v1alpha1.ServiceClaimTarget{EnvironmentTag: "dev"},
+		},
+		Status: v1alpha1.ServiceClaimStatus{State: v1alpha1.ServiceClaimStatePending},
+	}
+
+	DescribeTable("explaining rejections",
+		func(rs v1alpha1.RegisteredService, expected []v1alpha1.ServiceClaimRejection) {
+			explanation := explainServiceClaim(sclaim, envtag.Environment{Name: "dev"}, []v1alpha1.RegisteredService{rs})
+			Expect(explanation.Environment).To(Equal("dev"))
+			Expect(explanation.Candidates).To(HaveLen(1))
+			Expect(explanation.Candidates[0].Name).To(Equal(rs.Name))
+
+			reasons := []v1alpha1.ServiceClaimRejection{}
+			for _, r := range explanation.Candidates[0].Rejections {
+				reasons = append(reasons, v1alpha1.ServiceClaimRejection{Reason: r.Reason, Items: r.Items})
+			}
+			Expect(reasons).To(Equal(expected))
+		},
+		Entry("available matching service",
+			registeredService("rs", v1alpha1.RegisteredServiceStateAvailable),
+			[]v1alpha1.ServiceClaimRejection{}),
+		Entry("identity mismatch by item",
+			registeredService("rs", v1alpha1.RegisteredServiceStateAvailable, func(rs *v1alpha1.RegisteredService) {
+				rs.Spec.ServiceClassIdentity = []v1alpha1.ServiceClassIdentityItem{{Name: "type", Value: "psql"}, {Name: "provider", Value: "gcp"}}
+			}),
+			[]v1alpha1.ServiceClaimRejection{{Reason: v1alpha1.ServiceClaimRejectionIdentityMismatch, Items: []string{"provider=aws"}}}),
+		Entry("missing keys",
+			registeredService("rs", v1alpha1.RegisteredServiceStateAvailable, func(rs *v1alpha1.RegisteredService) {
+				rs.Spec.ServiceEndpointDefinition = nil
+			}),
+			[]v1alpha1.ServiceClaimRejection{{Reason: v1alpha1.ServiceClaimRejectionMissingKeys, Items: []string{"host"}}}),
+		Entry("environment constraint",
+			registeredService("rs", v1alpha1.RegisteredServiceStateAvailable, func(rs *v1alpha1.RegisteredService) {
+				rs.Spec.Constraints = &v1alpha1.RegisteredServiceConstraints{Environments: []string{"prod"}}
+			}),
+			[]v1alpha1.ServiceClaimRejection{{Reason: v1alpha1.ServiceClaimRejectionEnvironmentConstraint}}),
+		Entry("claimed",
+			registeredService("rs", v1alpha1.RegisteredServiceStateClaimed),
+			[]v1alpha1.ServiceClaimRejection{{Reason: v1alpha1.ServiceClaimRejectionClaimed}}),
+		Entry("unreachable",
+			registeredService("rs", v1alpha1.RegisteredServiceStateUnreachable),
+			[]v1alpha1.ServiceClaimRejection{{Reason: v1alpha1.ServiceClaimRejectionUnreachable}}),
+		Entry("unknown",
+			registeredService("rs", v1alpha1.RegisteredServiceStateUnknown),
+			[]v1alpha1.ServiceClaimRejection{{Reason: v1alpha1.ServiceClaimRejectionNotClaimable}}),
+		Entry("many reasons",
+			registeredService("rs", v1alpha1.RegisteredServiceStateUnreachable, func(rs *v1alpha1.RegisteredService) {
+				rs.Spec.ServiceClassIdentity = nil
+				rs.Spec.Constraints = &v1alpha1.RegisteredServiceConstraints{Environments: []string{"!dev"}}
+			}),
+			[]v1alpha1.ServiceClaimRejection{
+				{Reason: v1alpha1.ServiceClaimRejectionIdentityMismatch, Items: []string{"type=psql", "provider=aws"}},
+				{Reason: v1alpha1.ServiceClaimRejectionEnvironmentConstraint},
+				{Reason: v1alpha1.ServiceClaimRejectionUnreachable},
+			}),
+	)
+
+	It("should sort candidates by number of rejections and truncate them", func() {
+		rss := []v1alpha1.RegisteredService{
+			registeredService("claimed", v1alpha1.RegisteredServiceStateClaimed),
+			registeredService("available", v1alpha1.RegisteredServiceStateAvailable),
+		}
+		for i := 0; i < maxExplainedCandidates; i++ {
+			rss = append(rss, registeredService("unreachable", v1alpha1.RegisteredServiceStateUnreachable, func(rs *v1alpha1.RegisteredService) {
+				rs.Spec.ServiceEndpointDefinition = nil
+			}))
+		}
+
+		explanation := explainServiceClaim(sclaim, envtag.Environment{Name: "dev"}, rss)
+		Expect(explanation.Truncated).To(BeTrue())
+		Expect(explanation.Candidates).To(HaveLen(maxExplainedCandidates))
+		Expect(explanation.Candidates[0].Name).To(Equal("available"))
+		Expect(explanation.Candidates[1].Name).To(Equal("claimed"))
+	})
+
+	// reviews authenticate the "allowed" and "denied" tokens as the users
+	// with the same name, and only let "allowed" get claim
+	reviews := interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			switch o := obj.(type) {
+			case *authenticationv1.TokenReview:
+				if o.Spec.Token == "allowed" || o.Spec.Token == "denied" {
+					o.Status.Authenticated = true
+					o.Status.User.Username = o.Spec.Token
+				}
+			case *authorizationv1.SubjectAccessReview:
+				a := o.Spec.ResourceAttributes
+				o.Status.Allowed = o.Spec.User == "allowed" &&
+					a.Verb == "get" && a.Resource == "serviceclaims" && a.Namespace == "primaza-system" && a.Name == "claim"
+			default:
+				return c.Create(ctx, obj, opts...)
+			}
+			return nil
+		},
+	}
+
+	DescribeTable("serving explanations",
+		func(method, path, token string, expectedStatus int) {
+			scheme := runtime.NewScheme()
+			Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+			rs := registeredService("rs", v1alpha1.RegisteredServiceStateClaimed)
+			r := ServiceClaimReconciler{
+				Client: fake.NewClientBuilder().
+					WithScheme(scheme).
+					WithObjects(sclaim.DeepCopy(), &rs).
+					WithInterceptorFuncs(reviews).
+					Build(),
+				Scheme: scheme,
+			}
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(method, path, nil)
+			if token != "" {
+				req.Header.Set("Authorization", "Bearer "+token)
+			}
+			r.ExplanationHandler().ServeHTTP(rec, req)
+			Expect(rec.Code).To(Equal(expectedStatus))
+			if expectedStatus != http.StatusOK {
+				return
+			}
+
+			res := serviceClaimExplanationResponse{}
+			Expect(json.NewDecoder(rec.Body).Decode(&res)).To(Succeed())
+			Expect(res.Name).To(Equal("claim"))
+			Expect(res.State).To(Equal(v1alpha1.ServiceClaimStatePending))
+			Expect(res.Explanation.Candidates).To(HaveLen(1))
+			Expect(res.Explanation.Candidates[0].Rejections[0].Reason).To(Equal(v1alpha1.ServiceClaimRejectionClaimed))
+		},
+		Entry("existing claim", http.MethodGet, ServiceClaimExplanationPath+"primaza-system/claim", "allowed", http.StatusOK),
+		Entry("missing token", http.MethodGet, ServiceClaimExplanationPath+"primaza-system/claim", "", http.StatusUnauthorized),
+		Entry("invalid token", http.MethodGet, ServiceClaimExplanationPath+"primaza-system/claim", "invalid", http.StatusUnauthorized),
+		Entry("user not allowed", http.MethodGet, ServiceClaimExplanationPath+"primaza-system/claim", "denied", http.StatusForbidden),
+		Entry("claim not allowed", http.MethodGet, ServiceClaimExplanationPath+"primaza-system/other", "allowed", http.StatusForbidden),
+		Entry("malformed path", http.MethodGet, ServiceClaimExplanationPath+"primaza-system", "allowed", http.StatusNotFound),
+		Entry("read-only", http.MethodPost, ServiceClaimExplanationPath+"primaza-system/claim", "allowed", http.StatusMethodNotAllowed),
+	)
+})
@@ -0,0 +1,141 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	primazaiov1alpha1 "github.com/primaza/primaza/api/v1alpha1"
+)
+
+// FailoverRetryInterval is the interval at which a ServiceClaim whose
+// failover grace period has expired looks again for another
+// RegisteredService
+const FailoverRetryInterval = 30 * time.Second
+
+// maxFailoverHistory is the number of failovers recorded in the
+// ServiceClaim's status
+const maxFailoverHistory = 10
+
+// failoverIfNeeded moves the resolved ServiceClaim to another matching
+// RegisteredService when the claimed one has been Unreachable for longer
+// than the failover policy's grace period.
+//
+// It returns true if the ServiceClaim has been moved: it is then Pending and
+// holds the new RegisteredService, and needs to be processed again to push
+// the new Service Endpoint Definition.
+func (r *ServiceClaimReconciler) failoverIfNeeded(
+	ctx context.Context,
+	sclaim *primazaiov1alpha1.ServiceClaim,
+	rs primazaiov1alpha1.RegisteredService,
+) (bool, error) {
+	l := log.FromContext(ctx).WithValues("service-claim", sclaim.Name, "registered-service", rs.Name)
+
+	p := sclaim.Spec.FailoverPolicy
+	if p == nil || rs.Status.State != primazaiov1alpha1.RegisteredServiceStateUnreachable {
+		sclaim.Status.UnreachableSince = nil
+		return false, nil
+	}
+
+	now := metav1.Now()
+	if sclaim.Status.UnreachableSince == nil {
+		sclaim.Status.UnreachableSince = &now
+	}
+	if now.Sub(sclaim.Status.UnreachableSince.Time) < p.GetGracePeriod() {
+		l.Info("registered service is unreachable, waiting for the grace period to expire")
+		return false, nil
+	}
+
+	env, err := r.serviceClaimEnvironment(ctx, *sclaim)
+	if err != nil {
+		return false, err
+	}
+
+	rsl, err := r.listRegisteredServices(ctx, sclaim.Namespace)
+	if err != nil {
+		return false, err
+	}
+	candidates := []primazaiov1alpha1.RegisteredService{}
+	for _, c := range rsl.Items {
+		if c.UID != rs.UID {
+			candidates = append(candidates, c)
+		}
+	}
+
+	next, selection := selectRegisteredService(*sclaim, env, candidates)
+	if next == nil {
+		l.Info("no registered service to fail over to")
+		return false, nil
+	}
+
+	l.Info("failing over to another registered service", "next", next.Name)
+
+	// credentials have been issued by the unreachable service and
+	// can not be used with the new one. Revocation Jobs are awaited
+	// in later reconciliations.
+	if _, err := r.revokeCredentials(ctx, *sclaim, rs); err != nil {
+		l.Error(err, "unable to revoke the credentials issued by the unreachable registered service")
+	}
+
+	sclaim.Status.Selection = &selection
+	sclaim.Status.State = primazaiov1alpha1.ServiceClaimStatePending
+	sclaim.Status.RegisteredService = registeredServiceReference(*sclaim, *next)
+	sclaim.Status.UnreachableSince = nil
+	sclaim.Status.Failovers = append(sclaim.Status.Failovers, primazaiov1alpha1.ServiceClaimFailover{
+		Time: now,
+		From: rs.Name,
+		To:   next.Name,
+	})
+	if l := len(sclaim.Status.Failovers); l > maxFailoverHistory {
+		sclaim.Status.Failovers = sclaim.Status.Failovers[l-maxFailoverHistory:]
+	}
+	if err := r.updateServiceClaimStatus(ctx, sclaim); err != nil {
+		return false, err
+	}
+
+	if err := r.claimService(ctx, next, sclaim.Status.ClaimID); err != nil {
+		return false, err
+	}
+
+	// the unreachable service may not come back: do not fail
+	// the failover if it can not be released
+	if err := r.releaseService(ctx, &rs, sclaim.Status.ClaimID); err != nil {
+		l.Error(err, "unable to release the unreachable registered service")
+	}
+
+	return true, nil
+}
+
+// failoverRequeueAfter returns when the ServiceClaim needs to be reconciled
+// again to check whether it has to fail over, or 0 if it does not need to
+func failoverRequeueAfter(sclaim primazaiov1alpha1.ServiceClaim, now time.Time) time.Duration {
+	if sclaim.Status.State != primazaiov1alpha1.ServiceClaimStateResolved ||
+		sclaim.Spec.FailoverPolicy == nil ||
+		sclaim.Status.UnreachableSince == nil {
+		return 0
+	}
+
+	remaining := sclaim.Status.UnreachableSince.Add(sclaim.Spec.FailoverPolicy.GetGracePeriod()).Sub(now)
+	if remaining <= 0 {
+		return FailoverRetryInterval
+	}
+	return remaining
+}
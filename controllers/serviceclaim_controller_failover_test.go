@@ -0,0 +1,145 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/primaza/primaza/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("ServiceClaim failover", func() {
+	newRegisteredService := func(name string, state v1alpha1.RegisteredServiceState, claims ...string) *v1alpha1.RegisteredService {
+		return &v1alpha1.RegisteredService{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "primaza-system", UID: types.UID(name)},
+			Spec: v1alpha1.RegisteredServiceSpec{
+				ServiceClassIdentity:      []v1alpha1.ServiceClassIdentityItem{{Name: "type", Value: "psql"}},
+				ServiceEndpointDefinition: []v1alpha1.ServiceEndpointDefinitionItem{{Name: "host", Value: name}},
+			},
+			Status: v1alpha1.RegisteredServiceStatus{State: state, Claims: claims},
+		}
+	}
+	newServiceClaim := func(policy *v1alpha1.ServiceClaimFailoverPolicy, unreachableSince *metav1.Time) *v1alpha1.ServiceClaim {
+		return &v1alpha1.ServiceClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "claim", Namespace: "primaza-system"},
+			Spec: v1alpha1.ServiceClaimSpec{
+				ServiceClassIdentity:          []v1alpha1.ServiceClassIdentityItem{{Name: "type", Value: "psql"}},
+				ServiceEndpointDefinitionKeys: []string{"host"},
+				Target:                        &v1alpha1.ServiceClaimTarget{EnvironmentTag: "dev"},
+				FailoverPolicy:                policy,
+			},
+			Status: v1alpha1.ServiceClaimStatus{
+				State:             v1alpha1.ServiceClaimStateResolved,
+				ClaimID:           "claim-id",
+				RegisteredService: &corev1.ObjectReference{Name: "current", UID: "current"},
+				UnreachableSince:  unreachableSince,
+			},
+		}
+	}
+	policy := &v1alpha1.ServiceClaimFailoverPolicy{GracePeriod: metav1.Duration{Duration: time.Minute}}
+	expired := metav1.NewTime(time.Now().Add(-2 * time.Minute))
+
+	DescribeTable("failing over",
+		func(sclaim *v1alpha1.ServiceClaim, current *v1alpha1.RegisteredService, others []client.Object, expected string) {
+			scheme := runtime.NewScheme()
+			Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+			objs := append([]client.Object{sclaim, current}, others...)
+			cli := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(objs...).
+				WithStatusSubresource(objs...).
+				Build()
+			r := ServiceClaimReconciler{Client: cli, Scheme: scheme}
+			ctx := context.Background()
+			Expect(cli.Get(ctx, client.ObjectKeyFromObject(sclaim), sclaim)).To(Succeed())
+			Expect(cli.Get(ctx, client.ObjectKeyFromObject(current), current)).To(Succeed())
+
+			failedOver, err := r.failoverIfNeeded(ctx, sclaim, *current)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(failedOver).To(Equal(expected != ""))
+			if expected == "" {
+				Expect(sclaim.Status.RegisteredService.Name).To(Equal(current.Name))
+				Expect(sclaim.Status.Failovers).To(BeEmpty())
+				return
+			}
+
+			Expect(sclaim.Status.State).To(Equal(v1alpha1.ServiceClaimStatePending))
+			Expect(sclaim.Status.RegisteredService.Name).To(Equal(expected))
+			Expect(sclaim.Status.UnreachableSince).To(BeNil())
+			Expect(sclaim.Status.Failovers).To(HaveLen(1))
+			Expect(sclaim.Status.Failovers[0].From).To(Equal(current.Name))
+			Expect(sclaim.Status.Failovers[0].To).To(Equal(expected))
+
+			next := v1alpha1.RegisteredService{}
+			Expect(cli.Get(ctx, client.ObjectKey{Namespace: "primaza-system", Name: expected}, &next)).To(Succeed())
+			Expect(next.Status.Claims).To(ConsistOf("claim-id"))
+			Expect(cli.Get(ctx, client.ObjectKeyFromObject(current), current)).To(Succeed())
+			Expect(current.Status.Claims).To(BeEmpty())
+		},
+		Entry("no failover policy",
+			newServiceClaim(nil, &expired),
+			newRegisteredService("current", v1alpha1.RegisteredServiceStateUnreachable, "claim-id"),
+			[]client.Object{newRegisteredService("next", v1alpha1.RegisteredServiceStateAvailable)},
+			""),
+		Entry("registered service is reachable",
+			newServiceClaim(policy, nil),
+			newRegisteredService("current", v1alpha1.RegisteredServiceStateAvailable, "claim-id"),
+			[]client.Object{newRegisteredService("next", v1alpha1.RegisteredServiceStateAvailable)},
+			""),
+		Entry("grace period not expired",
+			newServiceClaim(policy, nil),
+			newRegisteredService("current", v1alpha1.RegisteredServiceStateUnreachable, "claim-id"),
+			[]client.Object{newRegisteredService("next", v1alpha1.RegisteredServiceStateAvailable)},
+			""),
+		Entry("no healthy registered service",
+			newServiceClaim(policy, &expired),
+			newRegisteredService("current", v1alpha1.RegisteredServiceStateUnreachable, "claim-id"),
+			[]client.Object{newRegisteredService("next", v1alpha1.RegisteredServiceStateUnreachable)},
+			""),
+		Entry("grace period expired",
+			newServiceClaim(policy, &expired),
+			newRegisteredService("current", v1alpha1.RegisteredServiceStateUnreachable, "claim-id"),
+			[]client.Object{newRegisteredService("next", v1alpha1.RegisteredServiceStateAvailable)},
+			"next"),
+	)
+
+	It("should track since when the registered service is unreachable", func() {
+		sclaim := newServiceClaim(policy, nil)
+		r := ServiceClaimReconciler{}
+		rs := newRegisteredService("current", v1alpha1.RegisteredServiceStateUnreachable, "claim-id")
+
+		Expect(r.failoverIfNeeded(context.Background(), sclaim, *rs)).To(BeFalse())
+		Expect(sclaim.Status.UnreachableSince).NotTo(BeNil())
+		now := sclaim.Status.UnreachableSince.Time
+		Expect(failoverRequeueAfter(*sclaim, now)).To(Equal(time.Minute))
+		Expect(failoverRequeueAfter(*sclaim, now.Add(2*time.Minute))).To(Equal(FailoverRetryInterval))
+
+		rs.Status.State = v1alpha1.RegisteredServiceStateAvailable
+		Expect(r.failoverIfNeeded(context.Background(), sclaim, *rs)).To(BeFalse())
+		Expect(sclaim.Status.UnreachableSince).To(BeNil())
+		Expect(failoverRequeueAfter(*sclaim, now)).To(BeZero())
+	})
+})
@@ -0,0 +1,89 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	primazaiov1alpha1 "github.com/primaza/primaza/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// reader returns the client.Reader for the resources of the given namespace.
+// The cache only holds the resources of the tenant, so the resources other
+// tenants grant are read from the API server.
+func (r *ServiceClaimReconciler) reader(tenant, namespace string) client.Reader {
+	if namespace == tenant || r.APIReader == nil {
+		return r.Client
+	}
+	return r.APIReader
+}
+
+// listRegisteredServices returns the RegisteredServices that can resolve the
+// ServiceClaims of the tenant: the tenant's own ones, followed by the ones
+// other tenants grant to it
+func (r *ServiceClaimReconciler) listRegisteredServices(ctx context.Context, tenant string) (primazaiov1alpha1.RegisteredServiceList, error) {
+	var rsl primazaiov1alpha1.RegisteredServiceList
+	if err := r.List(ctx, &rsl, client.InNamespace(tenant)); err != nil {
+		return rsl, err
+	}
+
+	granted, err := grantedRegisteredServices(ctx, r.Client, r.reader(tenant, metav1.NamespaceAll), tenant)
+	if err != nil {
+		return rsl, err
+	}
+	rsl.Items = append(rsl.Items, granted...)
+	return rsl, nil
+}
+
+// registeredServiceReference returns the reference to the RegisteredService
+// to record in the ServiceClaim's status. The namespace is only recorded for
+// RegisteredServices granted by other tenants.
+func registeredServiceReference(sclaim primazaiov1alpha1.ServiceClaim, rs primazaiov1alpha1.RegisteredService) *corev1.ObjectReference {
+	ref := &corev1.ObjectReference{
+		Name: rs.Name,
+		UID:  rs.UID,
+	}
+	if rs.Namespace != "" && rs.Namespace != sclaim.Namespace {
+		ref.Namespace = rs.Namespace
+	}
+	return ref
+}
+
+// getRegisteredService retrieves the RegisteredService the ServiceClaim
+// refers to. A RegisteredService of another tenant is reported as not found
+// once the access to it is revoked.
+func (r *ServiceClaimReconciler) getRegisteredService(
+	ctx context.Context,
+	sclaim primazaiov1alpha1.ServiceClaim,
+	rs *primazaiov1alpha1.RegisteredService,
+) (types.NamespacedName, error) {
+	k := types.NamespacedName{Name: sclaim.Status.RegisteredService.Name, Namespace: sclaim.Namespace}
+	if ns := sclaim.Status.RegisteredService.Namespace; ns != "" {
+		k.Namespace = ns
+	}
+
+	err := r.reader(sclaim.Namespace, k.Namespace).Get(ctx, k, rs)
+	if apierrors.IsForbidden(err) && k.Namespace != sclaim.Namespace {
+		return k, apierrors.NewNotFound(primazaiov1alpha1.GroupVersion.WithResource("registeredservices").GroupResource(), k.Name)
+	}
+	return k, err
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	primazaiov1alpha1 "github.com/primaza/primaza/api/v1alpha1"
+)
+
+// ServiceClassIdentityIndexKey is the name of the index of ServiceClaims by
+// the items of their ServiceClassIdentity
+const ServiceClassIdentityIndexKey = "spec.serviceClassIdentity"
+
+// anyServiceClassIdentityIndexValue indexes the ServiceClaims with an empty
+// ServiceClassIdentity, which match any RegisteredService
+const anyServiceClassIdentityIndexValue = "*"
+
+// serviceClassIdentityIndexValue returns the value indexing a
+// ServiceClassIdentity item
+func serviceClassIdentityIndexValue(i primazaiov1alpha1.ServiceClassIdentityItem) string {
+	return i.Name + "=" + i.Value
+}
+
+// indexServiceClassIdentity extracts the ServiceClassIdentityIndexKey
+// values of a ServiceClaim
+func indexServiceClassIdentity(o client.Object) []string {
+	sclaim, ok := o.(*primazaiov1alpha1.ServiceClaim)
+	if !ok {
+		return nil
+	}
+
+	if len(sclaim.Spec.ServiceClassIdentity) == 0 {
+		return []string{anyServiceClassIdentityIndexValue}
+	}
+
+	vv := make([]string, 0, len(sclaim.Spec.ServiceClassIdentity))
+	for _, i := range sclaim.Spec.ServiceClassIdentity {
+		vv = append(vv, serviceClassIdentityIndexValue(i))
+	}
+	return vv
+}
+
+// pendingServiceClaimsForRegisteredService returns the reconcile requests
+// for the Pending ServiceClaims the given RegisteredService may resolve.
+//
+// As a ServiceClaim's ServiceClassIdentity needs to be a subset of the
+// RegisteredService's one, every candidate ServiceClaim is indexed by at
+// least one of the RegisteredService's ServiceClassIdentity items, or by
+// anyServiceClassIdentityIndexValue if its ServiceClassIdentity is empty.
+func (r *ServiceClaimReconciler) pendingServiceClaimsForRegisteredService(
+	ctx context.Context,
+	rs primazaiov1alpha1.RegisteredService,
+) []reconcile.Request {
+	l := log.FromContext(ctx)
+
+	seen := map[string]struct{}{}
+	rr := []reconcile.Request{}
+	keys := []string{anyServiceClassIdentityIndexValue}
+	for _, i := range rs.Spec.ServiceClassIdentity {
+		keys = append(keys, serviceClassIdentityIndexValue(i))
+	}
+	for _, k := range keys {
+		serviceclaims := primazaiov1alpha1.ServiceClaimList{}
+		if err := r.List(ctx, &serviceclaims,
+			client.InNamespace(rs.Namespace),
+			client.MatchingFields{ServiceClassIdentityIndexKey: k}); err != nil {
+			l.Error(err,
+				"unable to list the ServiceClaims and reconcile for Registered Service Updates",
+				"RegisteredService", rs.Name)
+			return []reconcile.Request{}
+		}
+
+		for _, sc := range serviceclaims.Items {
+			if _, ok := seen[sc.Name]; ok {
+				continue
+			}
+			seen[sc.Name] = struct{}{}
+
+			if sc.Status.State != primazaiov1alpha1.ServiceClaimStatePending ||
+				sc.HasDeletionTimestamp() ||
+				!checkSCISubset(sc.Spec.ServiceClassIdentity, rs.Spec.ServiceClassIdentity) {
+				continue
+			}
+
+			p := primazaiov1alpha1.ServiceClaimSelectionPolicy{}
+			if sc.Spec.SelectionPolicy != nil {
+				p = *sc.Spec.SelectionPolicy
+			}
+			if !isClaimableState(rs.Status.State, p) {
+				continue
+			}
+
+			rr = append(rr, reconcile.Request{NamespacedName: types.NamespacedName{
+				Namespace: sc.Namespace,
+				Name:      sc.Name,
+			}})
+		}
+	}
+	return rr
+}
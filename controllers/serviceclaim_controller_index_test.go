@@ -0,0 +1,110 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/primaza/primaza/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Pending ServiceClaims for RegisteredService", func() {
+	sci := func(kv ...string) []v1alpha1.ServiceClassIdentityItem {
+		ii := []v1alpha1.ServiceClassIdentityItem{}
+		for i := 0; i+1 < len(kv); i += 2 {
+			ii = append(ii, v1alpha1.ServiceClassIdentityItem{Name: kv[i], Value: kv[i+1]})
+		}
+		return ii
+	}
+	newServiceClaim := func(name, namespace string, state v1alpha1.ServiceClaimState, identity []v1alpha1.ServiceClassIdentityItem) client.Object {
+		return &v1alpha1.ServiceClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       v1alpha1.ServiceClaimSpec{ServiceClassIdentity: identity},
+			Status:     v1alpha1.ServiceClaimStatus{State: state},
+		}
+	}
+	rs := v1alpha1.RegisteredService{
+		ObjectMeta: metav1.ObjectMeta{Name: "rs", Namespace: "primaza-system"},
+		Spec:       v1alpha1.RegisteredServiceSpec{ServiceClassIdentity: sci("type", "psql", "provider", "aws")},
+		Status:     v1alpha1.RegisteredServiceStatus{State: v1alpha1.RegisteredServiceStateAvailable},
+	}
+
+	DescribeTable("enqueueing pending ServiceClaims",
+		func(state v1alpha1.RegisteredServiceState, objs []client.Object, expected []string) {
+			scheme := runtime.NewScheme()
+			Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+			r := ServiceClaimReconciler{
+				Client: fake.NewClientBuilder().
+					WithScheme(scheme).
+					WithObjects(objs...).
+					WithIndex(&v1alpha1.ServiceClaim{}, ServiceClassIdentityIndexKey, indexServiceClassIdentity).
+					Build(),
+				Scheme: scheme,
+			}
+
+			rs := *rs.DeepCopy()
+			rs.Status.State = state
+			names := []string{}
+			for _, req := range r.pendingServiceClaimsForRegisteredService(context.Background(), rs) {
+				names = append(names, req.Name)
+			}
+			Expect(names).To(ConsistOf(expected))
+		},
+		Entry("matching pending claims",
+			v1alpha1.RegisteredServiceStateAvailable,
+			[]client.Object{
+				newServiceClaim("type", "primaza-system", v1alpha1.ServiceClaimStatePending, sci("type", "psql")),
+				newServiceClaim("type-and-provider", "primaza-system", v1alpha1.ServiceClaimStatePending, sci("provider", "aws", "type", "psql")),
+				newServiceClaim("resolved", "primaza-system", v1alpha1.ServiceClaimStateResolved, sci("type", "psql")),
+				newServiceClaim("other-namespace", "other", v1alpha1.ServiceClaimStatePending, sci("type", "psql")),
+				newServiceClaim("not-subset", "primaza-system", v1alpha1.ServiceClaimStatePending, sci("type", "psql", "version", "15")),
+				newServiceClaim("other-type", "primaza-system", v1alpha1.ServiceClaimStatePending, sci("type", "mysql")),
+			},
+			[]string{"type", "type-and-provider"}),
+		Entry("pending claims with an empty service class identity",
+			v1alpha1.RegisteredServiceStateAvailable,
+			[]client.Object{
+				newServiceClaim("any", "primaza-system", v1alpha1.ServiceClaimStatePending, nil),
+				newServiceClaim("any-resolved", "primaza-system", v1alpha1.ServiceClaimStateResolved, nil),
+				newServiceClaim("type", "primaza-system", v1alpha1.ServiceClaimStatePending, sci("type", "psql")),
+			},
+			[]string{"any", "type"}),
+		Entry("registered service can not be claimed",
+			v1alpha1.RegisteredServiceStateClaimed,
+			[]client.Object{
+				newServiceClaim("type", "primaza-system", v1alpha1.ServiceClaimStatePending, sci("type", "psql")),
+			},
+			[]string{}),
+		Entry("registered service state is unknown",
+			v1alpha1.RegisteredServiceStateUnknown,
+			[]client.Object{
+				newServiceClaim("type", "primaza-system", v1alpha1.ServiceClaimStatePending, sci("type", "psql")),
+				func() client.Object {
+					sc := newServiceClaim("allow-unknown", "primaza-system", v1alpha1.ServiceClaimStatePending, sci("type", "psql"))
+					sc.(*v1alpha1.ServiceClaim).Spec.SelectionPolicy = &v1alpha1.ServiceClaimSelectionPolicy{AllowUnknown: true}
+					return sc
+				}(),
+			},
+			[]string{"allow-unknown"}),
+	)
+})
@@ -0,0 +1,124 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	primazaiov1alpha1 "github.com/primaza/primaza/api/v1alpha1"
+	"github.com/primaza/primaza/pkg/primaza/constants"
+)
+
+// MaxLeaseExpiryWarningPeriod is the longest period before its expiration
+// during which a ServiceClaim is reported as Expiring
+const MaxLeaseExpiryWarningPeriod = time.Hour
+
+// leaseExpiryWarningPeriod returns how long before its expiration a
+// ServiceClaim is reported as Expiring: a fifth of its lease, up to
+// MaxLeaseExpiryWarningPeriod
+func leaseExpiryWarningPeriod(sclaim primazaiov1alpha1.ServiceClaim, expiry time.Time) time.Duration {
+	w := expiry.Sub(sclaim.LeaseStart()) / 5
+	if w > MaxLeaseExpiryWarningPeriod {
+		return MaxLeaseExpiryWarningPeriod
+	}
+	if w < 0 {
+		return 0
+	}
+	return w
+}
+
+// expireIfNeeded deletes the ServiceClaim if its lease is expired, so that
+// it is unbound and its RegisteredService released when it is finalized.
+// Otherwise, it records the expiration time and whether the ServiceClaim is
+// about to expire in its status.
+//
+// It returns true if the ServiceClaim has been deleted, and when it needs to
+// be reconciled again to check its lease, or 0 if it has none.
+func (r *ServiceClaimReconciler) expireIfNeeded(
+	ctx context.Context,
+	sclaim *primazaiov1alpha1.ServiceClaim,
+	now time.Time,
+) (bool, time.Duration, error) {
+	l := log.FromContext(ctx).WithValues("service-claim", sclaim.Name)
+
+	expiry := sclaim.ExpirationTime()
+	if expiry == nil {
+		if sclaim.Status.ExpirationTime == nil &&
+			meta.FindStatusCondition(sclaim.Status.Conditions, string(primazaiov1alpha1.ServiceClaimConditionExpiring)) == nil {
+			return false, 0, nil
+		}
+		sclaim.Status.ExpirationTime = nil
+		meta.RemoveStatusCondition(&sclaim.Status.Conditions, string(primazaiov1alpha1.ServiceClaimConditionExpiring))
+		return false, 0, r.updateServiceClaimStatus(ctx, sclaim)
+	}
+
+	remaining := expiry.Sub(now)
+	if remaining <= 0 {
+		l.Info("service claim lease expired, deleting the service claim", "expiration-time", expiry)
+		if err := r.Delete(ctx, sclaim); err != nil {
+			return false, 0, client.IgnoreNotFound(err)
+		}
+		return true, 0, nil
+	}
+
+	warning := leaseExpiryWarningPeriod(*sclaim, *expiry)
+	c := metav1.Condition{
+		Type:    string(primazaiov1alpha1.ServiceClaimConditionExpiring),
+		Status:  metav1.ConditionFalse,
+		Reason:  constants.LeaseValidReason,
+		Message: fmt.Sprintf("ServiceClaim expires at %s", expiry.UTC().Format(time.RFC3339)),
+	}
+	requeueAfter := remaining - warning
+	if remaining <= warning {
+		l.Info("service claim lease is about to expire", "expiration-time", expiry)
+		c.Status = metav1.ConditionTrue
+		c.Reason = constants.LeaseExpiringReason
+		c.Message = fmt.Sprintf("ServiceClaim expires at %s unless its lease is renewed", expiry.UTC().Format(time.RFC3339))
+		requeueAfter = remaining
+	}
+
+	// status timestamps are serialized with a precision of a second
+	et := metav1.NewTime(expiry.Truncate(time.Second))
+	o := meta.FindStatusCondition(sclaim.Status.Conditions, c.Type)
+	if sclaim.Status.ExpirationTime != nil && sclaim.Status.ExpirationTime.Equal(&et) &&
+		o != nil && o.Status == c.Status && o.Reason == c.Reason && o.Message == c.Message {
+		return false, requeueAfter, nil
+	}
+
+	sclaim.Status.ExpirationTime = &et
+	meta.SetStatusCondition(&sclaim.Status.Conditions, c)
+	if err := r.updateServiceClaimStatus(ctx, sclaim); err != nil {
+		return false, 0, err
+	}
+	return false, requeueAfter, nil
+}
+
+// minRequeueAfter returns the shortest of the given requeue intervals,
+// ignoring the ones that are 0
+func minRequeueAfter(a, b time.Duration) time.Duration {
+	if a == 0 || (b > 0 && b < a) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/primaza/primaza/api/v1alpha1"
+	"github.com/primaza/primaza/pkg/primaza/constants"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("ServiceClaim lease", func() {
+	now := time.Now().Truncate(time.Second)
+	newServiceClaim := func(created time.Time, ttl time.Duration, annotations map[string]string) *v1alpha1.ServiceClaim {
+		return &v1alpha1.ServiceClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "claim",
+				Namespace:         "primaza-system",
+				CreationTimestamp: metav1.NewTime(created),
+				Annotations:       annotations,
+				Finalizers:        []string{ServiceClaimFinalizer},
+			},
+			Spec: v1alpha1.ServiceClaimSpec{
+				ServiceClassIdentity:          []v1alpha1.ServiceClassIdentityItem{{Name: "type", Value: "psql"}},
+				ServiceEndpointDefinitionKeys: []string{"host"},
+				Target:                        &v1alpha1.ServiceClaimTarget{EnvironmentTag: "dev"},
+				TTL:                           &metav1.Duration{Duration: ttl},
+			},
+			Status: v1alpha1.ServiceClaimStatus{
+				State:   v1alpha1.ServiceClaimStateResolved,
+				ClaimID: "claim-id",
+			},
+		}
+	}
+	newReconciler := func(sclaim *v1alpha1.ServiceClaim) (ServiceClaimReconciler, client.Client) {
+		scheme := runtime.NewScheme()
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		cli := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(sclaim).
+			WithStatusSubresource(sclaim).
+			Build()
+		Expect(cli.Get(context.Background(), client.ObjectKeyFromObject(sclaim), sclaim)).To(Succeed())
+		return ServiceClaimReconciler{Client: cli, Scheme: scheme}, cli
+	}
+
+	DescribeTable("checking the lease",
+		func(sclaim *v1alpha1.ServiceClaim, expiring metav1.ConditionStatus, requeueAfter time.Duration) {
+			r, cli := newReconciler(sclaim)
+			ctx := context.Background()
+
+			expired, obtained, err := r.expireIfNeeded(ctx, sclaim, now)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(expired).To(BeFalse())
+			Expect(obtained).To(Equal(requeueAfter))
+
+			Expect(cli.Get(ctx, client.ObjectKeyFromObject(sclaim), sclaim)).To(Succeed())
+			Expect(sclaim.Status.ExpirationTime).NotTo(BeNil())
+			Expect(sclaim.Status.ExpirationTime.Time).To(BeTemporally("==", *sclaim.ExpirationTime()))
+			c := meta.FindStatusCondition(sclaim.Status.Conditions, string(v1alpha1.ServiceClaimConditionExpiring))
+			Expect(c).NotTo(BeNil())
+			Expect(c.Status).To(Equal(expiring))
+		},
+		Entry("lease far from expiry",
+			newServiceClaim(now.Add(-time.Hour), 10*time.Hour, nil),
+			metav1.ConditionFalse, 8*time.Hour),
+		Entry("lease about to expire",
+			newServiceClaim(now.Add(-9*time.Hour), 10*time.Hour, nil),
+			metav1.ConditionTrue, time.Hour),
+		Entry("short lease about to expire",
+			newServiceClaim(now.Add(-9*time.Minute), 10*time.Minute, nil),
+			metav1.ConditionTrue, time.Minute),
+		Entry("renewed lease",
+			newServiceClaim(now.Add(-9*time.Hour), 10*time.Hour, map[string]string{
+				constants.ServiceClaimLeaseRenewedAtAnnotation: now.Add(-time.Hour).Format(time.RFC3339),
+			}),
+			metav1.ConditionFalse, 8*time.Hour),
+	)
+
+	It("should delete expired claims", func() {
+		sclaim := newServiceClaim(now.Add(-2*time.Hour), time.Hour, nil)
+		r, cli := newReconciler(sclaim)
+		ctx := context.Background()
+
+		expired, requeueAfter, err := r.expireIfNeeded(ctx, sclaim, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(expired).To(BeTrue())
+		Expect(requeueAfter).To(BeZero())
+
+		// the finalizer holds the claim until it is released
+		Expect(cli.Get(ctx, client.ObjectKeyFromObject(sclaim), sclaim)).To(Succeed())
+		Expect(sclaim.HasDeletionTimestamp()).To(BeTrue())
+	})
+
+	It("should expire claims at the given time", func() {
+		sclaim := newServiceClaim(now.Add(-time.Hour), 10*time.Hour, nil)
+		at := metav1.NewTime(now.Add(-time.Minute))
+		sclaim.Spec.ExpiresAt = &at
+		r, cli := newReconciler(sclaim)
+		ctx := context.Background()
+
+		expired, _, err := r.expireIfNeeded(ctx, sclaim, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(expired).To(BeTrue())
+		Expect(cli.Get(ctx, client.ObjectKeyFromObject(sclaim), sclaim)).To(Succeed())
+		Expect(sclaim.HasDeletionTimestamp()).To(BeTrue())
+	})
+
+	It("should ignore claims without a lease", func() {
+		sclaim := newServiceClaim(now.Add(-time.Hour), 0, nil)
+		sclaim.Spec.TTL = nil
+		sclaim.Finalizers = nil
+		r, cli := newReconciler(sclaim)
+		ctx := context.Background()
+
+		expired, requeueAfter, err := r.expireIfNeeded(ctx, sclaim, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(expired).To(BeFalse())
+		Expect(requeueAfter).To(BeZero())
+		err = cli.Get(ctx, client.ObjectKeyFromObject(sclaim), sclaim)
+		Expect(apierrors.IsNotFound(err)).To(BeFalse())
+	})
+})
@@ -0,0 +1,284 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	primazaiov1alpha1 "github.com/primaza/primaza/api/v1alpha1"
+	"github.com/primaza/primaza/pkg/envtag"
+	"github.com/primaza/primaza/pkg/primaza/clustercontext"
+	"github.com/primaza/primaza/pkg/primaza/constants"
+	"github.com/primaza/primaza/pkg/primaza/controlplane"
+)
+
+const (
+	// ProvisioningRefreshInterval is the interval at which ServiceClaims
+	// waiting for a service to be provisioned are reconciled
+	ProvisioningRefreshInterval = 30 * time.Second
+	// ProvisioningRetryBackoff is the time waited before provisioning the
+	// service again after the first failure. It doubles after each failure.
+	ProvisioningRetryBackoff = 30 * time.Second
+	// MaxProvisioningAttempts is the number of failed attempts after which
+	// the service provisioning is given up
+	MaxProvisioningAttempts = 3
+)
+
+// provisionService asks a Service Agent to provision a new service for the
+// ServiceClaim, if a ServiceClass is able to provision it.
+// It returns false if no ServiceClass can provision the service.
+func (r *ServiceClaimReconciler) provisionService(ctx context.Context, sclaim *primazaiov1alpha1.ServiceClaim, env string) (bool, error) {
+	var failures int32
+	if p := sclaim.Status.Provisioning; p != nil {
+		switch {
+		case provisioningGivenUp(*p):
+			return true, nil
+		case p.RetryTime == nil:
+			// the service has already been requested, we are waiting for it
+			// to be discovered as a RegisteredService
+			r.refreshProvisioningState(ctx, sclaim)
+			return true, nil
+		case p.RetryTime.After(time.Now()):
+			// backing off after a failure
+			return true, nil
+		}
+
+		// the failed ServiceProvisioning has to be gone before requesting
+		// the service again
+		if deleted, err := r.serviceProvisioningDeleted(ctx, sclaim.Namespace, *p); err != nil || !deleted {
+			return true, err
+		}
+		failures = p.Failures
+	}
+
+	sc, ce, err := r.findServiceProvisioner(ctx, *sclaim, env)
+	if err != nil || sc == nil {
+		return false, err
+	}
+
+	manifest, err := controlplane.RenderProvisioningTemplate(*sc.Spec.Provisioning, *sclaim)
+	if err != nil {
+		return false, fmt.Errorf("error rendering provisioning template of service class %s: %w", sc.Name, err)
+	}
+
+	cli, err := clustercontext.CreateClient(ctx, r.Client, *ce, r.Scheme, r.Client.RESTMapper())
+	if err != nil {
+		return false, err
+	}
+
+	ns := ce.Spec.ServiceNamespaces[0]
+	name := controlplane.ServiceProvisioningName(*sclaim)
+	if err := controlplane.PushServiceProvisioning(ctx, cli, ns, name, *sc, *sclaim, manifest); err != nil {
+		return false, err
+	}
+
+	log.FromContext(ctx).Info("requested service provisioning",
+		"service class", sc.Name, "cluster environment", ce.Name, "namespace", ns, "service provisioning", name)
+	sclaim.Status.Provisioning = &primazaiov1alpha1.ServiceClaimProvisioning{
+		ServiceClass:       sc.Name,
+		ClusterEnvironment: ce.Name,
+		Namespace:          ns,
+		Name:               name,
+		State:              primazaiov1alpha1.ServiceProvisioningStatePending,
+		Failures:           failures,
+	}
+	return true, nil
+}
+
+// findServiceProvisioner looks for a ServiceClass able to provision a service
+// for the ServiceClaim, and for the ClusterEnvironment to provision it in
+func (r *ServiceClaimReconciler) findServiceProvisioner(
+	ctx context.Context,
+	sclaim primazaiov1alpha1.ServiceClaim,
+	env string,
+) (*primazaiov1alpha1.ServiceClass, *primazaiov1alpha1.ClusterEnvironment, error) {
+	var scl primazaiov1alpha1.ServiceClassList
+	if err := r.List(ctx, &scl, client.InNamespace(sclaim.Namespace)); err != nil {
+		return nil, nil, err
+	}
+	var cel primazaiov1alpha1.ClusterEnvironmentList
+	if err := r.List(ctx, &cel, client.InNamespace(sclaim.Namespace)); err != nil {
+		return nil, nil, err
+	}
+
+	sort.Slice(scl.Items, func(i, j int) bool { return scl.Items[i].Name < scl.Items[j].Name })
+	sort.Slice(cel.Items, func(i, j int) bool { return cel.Items[i].Name < cel.Items[j].Name })
+	for i, sc := range scl.Items {
+		if !canProvision(sc, sclaim) {
+			continue
+		}
+
+		for j, ce := range cel.Items {
+			if ce.Spec.EnvironmentName == env &&
+				ce.Status.State == primazaiov1alpha1.ClusterEnvironmentStateOnline &&
+				len(ce.Spec.ServiceNamespaces) > 0 &&
+				envtag.MatchEnvironment(environmentOf(ce), sc.Spec.GetEnvironmentConstraints()) {
+				return &scl.Items[i], &cel.Items[j], nil
+			}
+		}
+	}
+	return nil, nil, nil
+}
+
+// canProvision checks whether the ServiceClass can provision a service
+// matching the ServiceClaim
+func canProvision(sc primazaiov1alpha1.ServiceClass, sclaim primazaiov1alpha1.ServiceClaim) bool {
+	if sc.Spec.Provisioning == nil ||
+		!checkSCISubset(sclaim.Spec.ServiceClassIdentity, sc.Spec.ServiceClassIdentity) {
+		return false
+	}
+
+	keys := sc.Spec.GetServiceEndpointDefinitionKeys()
+	for _, k := range sclaim.Spec.ServiceEndpointDefinitionKeys {
+		if !slices.Contains(keys, k) {
+			return false
+		}
+	}
+	return true
+}
+
+// refreshProvisioningState copies the state of the ServiceProvisioning into
+// the ServiceClaim's status. A failed or missing ServiceProvisioning is
+// deleted and the provisioning is scheduled to be retried, until it fails
+// MaxProvisioningAttempts times.
+func (r *ServiceClaimReconciler) refreshProvisioningState(ctx context.Context, sclaim *primazaiov1alpha1.ServiceClaim) {
+	l := log.FromContext(ctx)
+	p := sclaim.Status.Provisioning
+
+	ce, err := r.getEnvironmentFromClusterEnvironment(ctx, sclaim.Namespace, p.ClusterEnvironment)
+	if err != nil {
+		return
+	}
+	cli, err := clustercontext.CreateClient(ctx, r.Client, *ce, r.Scheme, r.Client.RESTMapper())
+	if err != nil {
+		l.Info("unable to create client for cluster environment", "error", err, "cluster environment", ce.Name)
+		return
+	}
+	sp, err := controlplane.GetServiceProvisioning(ctx, cli, p.Namespace, p.Name)
+	switch {
+	case apierrors.IsNotFound(err):
+		p.State = primazaiov1alpha1.ServiceProvisioningStateFailed
+		p.Message = fmt.Sprintf("ServiceProvisioning %s not found in namespace %s", p.Name, p.Namespace)
+	case err != nil:
+		l.Info("unable to retrieve ServiceProvisioning", "error", err, "service provisioning", p.Name)
+		return
+	default:
+		p.State = sp.Status.State
+		p.Message = sp.Status.Message
+	}
+	if p.State != primazaiov1alpha1.ServiceProvisioningStateFailed {
+		return
+	}
+
+	p.Failures++
+	if provisioningGivenUp(*p) {
+		l.Info("giving up service provisioning", "failures", p.Failures, "service provisioning", p.Name)
+		return
+	}
+	if err := controlplane.DeleteServiceProvisioning(ctx, cli, p.Namespace, p.Name); err != nil {
+		l.Info("unable to delete failed ServiceProvisioning", "error", err, "service provisioning", p.Name)
+	}
+	retryTime := metav1.NewTime(time.Now().Add(ProvisioningRetryBackoff << (p.Failures - 1)))
+	p.RetryTime = &retryTime
+	l.Info("service provisioning failed, retrying", "failures", p.Failures, "retry-time", retryTime)
+}
+
+// serviceProvisioningDeleted checks whether the ServiceProvisioning recorded
+// in the ServiceClaim's status does not exist anymore
+func (r *ServiceClaimReconciler) serviceProvisioningDeleted(
+	ctx context.Context,
+	namespace string,
+	p primazaiov1alpha1.ServiceClaimProvisioning,
+) (bool, error) {
+	ce, err := r.getEnvironmentFromClusterEnvironment(ctx, namespace, p.ClusterEnvironment)
+	if err != nil {
+		return apierrors.IsNotFound(err), client.IgnoreNotFound(err)
+	}
+	cli, err := clustercontext.CreateClient(ctx, r.Client, *ce, r.Scheme, r.Client.RESTMapper())
+	if err != nil {
+		return false, err
+	}
+	if _, err := controlplane.GetServiceProvisioning(ctx, cli, p.Namespace, p.Name); !apierrors.IsNotFound(err) {
+		return false, err
+	}
+	return true, nil
+}
+
+// deleteServiceProvisioning deletes the ServiceProvisioning created for the
+// ServiceClaim. The provisioned service is deleted or retained by the Service
+// Agent according to the reclaim policy.
+func (r *ServiceClaimReconciler) deleteServiceProvisioning(ctx context.Context, sclaim primazaiov1alpha1.ServiceClaim) error {
+	p := sclaim.Status.Provisioning
+	if p == nil {
+		return nil
+	}
+
+	ce, err := r.getEnvironmentFromClusterEnvironment(ctx, sclaim.Namespace, p.ClusterEnvironment)
+	if err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	cli, err := clustercontext.CreateClient(ctx, r.Client, *ce, r.Scheme, r.Client.RESTMapper())
+	if err != nil {
+		return err
+	}
+	return controlplane.DeleteServiceProvisioning(ctx, cli, p.Namespace, p.Name)
+}
+
+// provisioningGivenUp checks whether the service provisioning failed too
+// many times to be retried
+func provisioningGivenUp(p primazaiov1alpha1.ServiceClaimProvisioning) bool {
+	return p.Failures >= MaxProvisioningAttempts
+}
+
+// provisioningRequeueAfter returns when a ServiceClaim waiting for a service
+// to be provisioned has to be reconciled again, or 0 if the provisioning has
+// been given up
+func provisioningRequeueAfter(p primazaiov1alpha1.ServiceClaimProvisioning, now time.Time) time.Duration {
+	switch {
+	case provisioningGivenUp(p):
+		return 0
+	case p.RetryTime != nil:
+		return max(p.RetryTime.Sub(now), time.Second)
+	default:
+		return ProvisioningRefreshInterval
+	}
+}
+
+// provisioningCondition describes the state of the service provisioning
+func provisioningCondition(p primazaiov1alpha1.ServiceClaimProvisioning) (string, string) {
+	switch {
+	case provisioningGivenUp(p):
+		return constants.ServiceProvisioningFailedReason,
+			fmt.Sprintf("service provisioning failed %d times, giving up: %s", p.Failures, p.Message)
+	case p.RetryTime != nil:
+		return constants.ServiceProvisioningRetryReason,
+			fmt.Sprintf("service provisioning failed (attempt %d of %d), retrying at %s: %s",
+				p.Failures, MaxProvisioningAttempts, p.RetryTime.UTC().Format(time.RFC3339), p.Message)
+	}
+	return constants.ServiceProvisioningReason,
+		fmt.Sprintf("provisioning service with ServiceClass %s in namespace %s of cluster environment %s",
+			p.ServiceClass, p.Namespace, p.ClusterEnvironment)
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/primaza/primaza/api/v1alpha1"
+	"github.com/primaza/primaza/pkg/primaza/constants"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("ServiceClaim dynamic provisioning", func() {
+	now := time.Now()
+	newServiceClass := func(name string, provisioning *v1alpha1.ServiceClassProvisioning, constraints ...string) *v1alpha1.ServiceClass {
+		return &v1alpha1.ServiceClass{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "primaza-system"},
+			Spec: v1alpha1.ServiceClassSpec{
+				Constraints:          &v1alpha1.EnvironmentConstraints{Environments: constraints},
+				ServiceClassIdentity: []v1alpha1.ServiceClassIdentityItem{{Name: "type", Value: "psql"}},
+				Resource: v1alpha1.ServiceClassResource{
+					ServiceEndpointDefinitionMappings: v1alpha1.ServiceEndpointDefinitionMappings{
+						ResourceFields: []v1alpha1.ServiceClassResourceFieldMapping{{Name: "host", JsonPath: ".spec.host"}},
+					},
+				},
+				Provisioning: provisioning,
+			},
+		}
+	}
+	newClusterEnvironment := func(name, env string, state v1alpha1.ClusterEnvironmentState, namespaces ...string) *v1alpha1.ClusterEnvironment {
+		return &v1alpha1.ClusterEnvironment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "primaza-system"},
+			Spec:       v1alpha1.ClusterEnvironmentSpec{EnvironmentName: env, ServiceNamespaces: namespaces},
+			Status:     v1alpha1.ClusterEnvironmentStatus{State: state},
+		}
+	}
+	provisioning := &v1alpha1.ServiceClassProvisioning{Template: "apiVersion: v1\nkind: ConfigMap\n"}
+	sclaim := v1alpha1.ServiceClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "claim", Namespace: "primaza-system"},
+		Spec: v1alpha1.ServiceClaimSpec{
+			ServiceClassIdentity:          []v1alpha1.ServiceClassIdentityItem{{Name: "type", Value: "psql"}},
+			ServiceEndpointDefinitionKeys: []string{"host"},
+		},
+	}
+
+	DescribeTable("finding the service provisioner",
+		func(objs []client.Object, expectedClass, expectedEnvironment string) {
+			scheme := runtime.NewScheme()
+			Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+			r := ServiceClaimReconciler{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+				Scheme: scheme,
+			}
+
+			sc, ce, err := r.findServiceProvisioner(context.Background(), sclaim, "dev")
+			Expect(err).NotTo(HaveOccurred())
+			if expectedClass == "" {
+				Expect(sc).To(BeNil())
+				Expect(ce).To(BeNil())
+				return
+			}
+			Expect(sc.Name).To(Equal(expectedClass))
+			Expect(ce.Name).To(Equal(expectedEnvironment))
+		},
+		Entry("service class with provisioning template",
+			[]client.Object{
+				newServiceClass("no-provisioning", nil, "dev"),
+				newServiceClass("psql", provisioning, "dev"),
+				newClusterEnvironment("worker", "dev", v1alpha1.ClusterEnvironmentStateOnline, "services"),
+			},
+			"psql", "worker"),
+		Entry("no service class with provisioning template",
+			[]client.Object{
+				newServiceClass("no-provisioning", nil, "dev"),
+				newClusterEnvironment("worker", "dev", v1alpha1.ClusterEnvironmentStateOnline, "services"),
+			},
+			"", ""),
+		Entry("service class missing requested keys",
+			[]client.Object{
+				func() client.Object {
+					sc := newServiceClass("psql", provisioning, "dev")
+					sc.Spec.Resource.ServiceEndpointDefinitionMappings.ResourceFields[0].Name = "url"
+					return sc
+				}(),
+				newClusterEnvironment("worker", "dev", v1alpha1.ClusterEnvironmentStateOnline, "services"),
+			},
+			"", ""),
+		Entry("cluster environments not suitable",
+			[]client.Object{
+				newServiceClass("psql", provisioning, "dev"),
+				newClusterEnvironment("offline", "dev", v1alpha1.ClusterEnvironmentStateOffline, "services"),
+				newClusterEnvironment("prod", "prod", v1alpha1.ClusterEnvironmentStateOnline, "services"),
+				newClusterEnvironment("no-namespaces", "dev", v1alpha1.ClusterEnvironmentStateOnline),
+			},
+			"", ""),
+		Entry("cluster environment excluded by constraints",
+			[]client.Object{
+				newServiceClass("psql", provisioning, "!dev"),
+				newClusterEnvironment("worker", "dev", v1alpha1.ClusterEnvironmentStateOnline, "services"),
+			},
+			"", ""),
+	)
+
+	DescribeTable("computing when to check the service provisioning again",
+		func(p v1alpha1.ServiceClaimProvisioning, expectedReason string, expectedRequeueAfter time.Duration) {
+			reason, _ := provisioningCondition(p)
+			Expect(reason).To(Equal(expectedReason))
+			Expect(provisioningRequeueAfter(p, now)).To(Equal(expectedRequeueAfter))
+		},
+		Entry("pending provisioning",
+			v1alpha1.ServiceClaimProvisioning{State: v1alpha1.ServiceProvisioningStatePending},
+			constants.ServiceProvisioningReason, ProvisioningRefreshInterval),
+		Entry("failed provisioning backing off",
+			v1alpha1.ServiceClaimProvisioning{
+				State:     v1alpha1.ServiceProvisioningStateFailed,
+				Failures:  1,
+				RetryTime: &metav1.Time{Time: now.Add(time.Minute)},
+			},
+			constants.ServiceProvisioningRetryReason, time.Minute),
+		Entry("failed provisioning to retry",
+			v1alpha1.ServiceClaimProvisioning{
+				State:     v1alpha1.ServiceProvisioningStateFailed,
+				Failures:  1,
+				RetryTime: &metav1.Time{Time: now.Add(-time.Minute)},
+			},
+			constants.ServiceProvisioningRetryReason, time.Second),
+		Entry("given up provisioning",
+			v1alpha1.ServiceClaimProvisioning{
+				State:    v1alpha1.ServiceProvisioningStateFailed,
+				Failures: MaxProvisioningAttempts,
+			},
+			constants.ServiceProvisioningFailedReason, time.Duration(0)),
+	)
+})
@@ -0,0 +1,89 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	primazaiov1alpha1 "github.com/primaza/primaza/api/v1alpha1"
+	"github.com/primaza/primaza/pkg/primaza/constants"
+)
+
+// exceededClaimQuota returns the first ClaimQuota the ServiceClaim exceeds,
+// if any. The ServiceClaims counting against a quota are served oldest
+// first: a ServiceClaim exceeds the quota if it is not among the first
+// `hard` ones.
+func (r *ServiceClaimReconciler) exceededClaimQuota(
+	ctx context.Context,
+	sclaim primazaiov1alpha1.ServiceClaim,
+) (*primazaiov1alpha1.ClaimQuota, error) {
+	var cql primazaiov1alpha1.ClaimQuotaList
+	if err := r.List(ctx, &cql, client.InNamespace(sclaim.Namespace)); err != nil || len(cql.Items) == 0 {
+		return nil, err
+	}
+
+	claims, environments, err := listQuotaServiceClaims(ctx, r.Client, sclaim.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	env := sclaim.Spec.Target.EnvironmentName(environments)
+	for i, q := range cql.Items {
+		if !q.Matches(sclaim, env) {
+			continue
+		}
+
+		counted := q.CountedClaims(claims, environments)
+		rank := len(counted)
+		for j, c := range counted {
+			if c.Name == sclaim.Name {
+				rank = j
+				break
+			}
+		}
+		if rank >= q.Spec.Hard {
+			return &cql.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// reportClaimQuotaExceeded records in the ServiceClaim's status that it
+// can not be resolved until the exceeded ClaimQuota frees up
+func (r *ServiceClaimReconciler) reportClaimQuotaExceeded(
+	ctx context.Context,
+	sclaim *primazaiov1alpha1.ServiceClaim,
+	q primazaiov1alpha1.ClaimQuota,
+) error {
+	log.FromContext(ctx).Info("claim quota exceeded", "service-claim", sclaim.Name, "claim-quota", q.Name)
+
+	meta.SetStatusCondition(&sclaim.Status.Conditions, metav1.Condition{
+		LastTransitionTime: metav1.Now(),
+		Type:               string(primazaiov1alpha1.ServiceClaimConditionReady),
+		Status:             metav1.ConditionFalse,
+		Reason:             constants.ClaimQuotaExceededReason,
+		Message:            fmt.Sprintf("exceeded claim quota %s: limited %d", q.Name, q.Spec.Hard),
+	})
+	sclaim.Status.State = primazaiov1alpha1.ServiceClaimStatePending
+	return r.updateServiceClaimStatus(ctx, sclaim)
+}
@@ -0,0 +1,226 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	primazaiov1alpha1 "github.com/primaza/primaza/api/v1alpha1"
+	"github.com/primaza/primaza/pkg/envtag"
+	"github.com/primaza/primaza/pkg/primaza/constants"
+)
+
+// selectRegisteredService chooses the RegisteredService to claim among the
+// ones matching the ServiceClaim, according to the ServiceClaim's selection
+// policy. It returns nil if no RegisteredService can be claimed.
+// The returned selection records the chosen candidate and why the other
+// RegisteredServices matching the ServiceClassIdentity were rejected.
+func selectRegisteredService(
+	sclaim primazaiov1alpha1.ServiceClaim,
+	env envtag.Environment,
+	rss []primazaiov1alpha1.RegisteredService,
+) (*primazaiov1alpha1.RegisteredService, primazaiov1alpha1.ServiceClaimSelection) {
+	p := primazaiov1alpha1.ServiceClaimSelectionPolicy{}
+	if sclaim.Spec.SelectionPolicy != nil {
+		p = *sclaim.Spec.SelectionPolicy
+	}
+
+	selection := primazaiov1alpha1.ServiceClaimSelection{}
+	reject := func(rs primazaiov1alpha1.RegisteredService, reason string) {
+		selection.Rejected = append(selection.Rejected,
+			primazaiov1alpha1.ServiceClaimRejectedCandidate{Name: rs.Name, Reason: reason})
+	}
+
+	candidates := []primazaiov1alpha1.RegisteredService{}
+	for _, rs := range rss {
+		// Check if the ServiceClassIdentity given in ServiceClaim is a subset of
+		// ServiceClassIdentity given in the RegisteredService
+		if !checkSCISubset(sclaim.Spec.ServiceClassIdentity, rs.Spec.ServiceClassIdentity) {
+			continue
+		}
+
+		switch {
+		case rs.Spec.ReservedFor != "" && rs.Spec.ReservedFor != sclaim.Status.ClaimID:
+			reject(rs, "provisioned for another service claim")
+		case rs.Spec.Constraints != nil && !envtag.MatchEnvironment(env, rs.Spec.Constraints.Environments):
+			reject(rs, fmt.Sprintf("environment constraints are not satisfied by environment '%s'", env.Name))
+		case !isClaimableState(rs.Status.State, p):
+			reject(rs, fmt.Sprintf("state is %s", rs.Status.State))
+		default:
+			if mk := missingServiceEndpointDefinitionKeys(rs, sclaim.Spec.ServiceEndpointDefinitionKeys); len(mk) > 0 {
+				reject(rs, fmt.Sprintf("missing service endpoint definition keys: %s", strings.Join(mk, ", ")))
+				continue
+			}
+			candidates = append(candidates, rs)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return nil, selection
+	}
+
+	// the RegisteredService provisioned for the ServiceClaim, if any, is
+	// always preferred
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if ri, rj := candidates[i].Spec.ReservedFor != "", candidates[j].Spec.ReservedFor != ""; ri != rj {
+			return ri
+		}
+		return compareCandidates(candidates[i], candidates[j], p) < 0
+	})
+
+	selected := candidates[0]
+	selection.Selected = selected.Name
+	for _, rs := range candidates[1:] {
+		reject(rs, lowerPriorityReason(rs, selected, p))
+	}
+	return &selected, selection
+}
+
+// reservedRegisteredService returns the RegisteredService already holding
+// the ServiceClaim, if any
+func reservedRegisteredService(
+	sclaim primazaiov1alpha1.ServiceClaim,
+	rss []primazaiov1alpha1.RegisteredService,
+) *primazaiov1alpha1.RegisteredService {
+	if sclaim.Status.RegisteredService == nil || sclaim.Status.ClaimID == "" {
+		return nil
+	}
+	for i, rs := range rss {
+		if rs.UID == sclaim.Status.RegisteredService.UID && slices.Contains(rs.Status.Claims, sclaim.Status.ClaimID) {
+			return &rss[i]
+		}
+	}
+	return nil
+}
+
+func isClaimableState(state primazaiov1alpha1.RegisteredServiceState, p primazaiov1alpha1.ServiceClaimSelectionPolicy) bool {
+	return state == primazaiov1alpha1.RegisteredServiceStateAvailable ||
+		(p.AllowUnknown && state == primazaiov1alpha1.RegisteredServiceStateUnknown)
+}
+
+func missingServiceEndpointDefinitionKeys(rs primazaiov1alpha1.RegisteredService, keys []string) []string {
+	mk := []string{}
+	for _, k := range keys {
+		if slices.Contains(issuedKeys(rs), k) {
+			continue
+		}
+		if !slices.ContainsFunc(rs.Spec.ServiceEndpointDefinition,
+			func(sed primazaiov1alpha1.ServiceEndpointDefinitionItem) bool { return sed.Name == k }) {
+			mk = append(mk, k)
+		}
+	}
+	return mk
+}
+
+// rank returns the rank of the RegisteredService as defined by its
+// `primaza.io/rank` annotation. Missing or invalid ranks are considered 0
+func rank(rs primazaiov1alpha1.RegisteredService) int {
+	r, err := strconv.Atoi(rs.GetAnnotations()[constants.RankAnnotation])
+	if err != nil {
+		return 0
+	}
+	return r
+}
+
+// selectionCriteria returns the ordered criteria used to compare candidates.
+// Each criterion returns a negative value if a is preferred over b, a positive
+// value if b is preferred over a, and zero if they are equally preferred
+func selectionCriteria(p primazaiov1alpha1.ServiceClaimSelectionPolicy) []func(a, b primazaiov1alpha1.RegisteredService) int {
+	boolCmp := func(a, b bool) int {
+		switch {
+		case a == b:
+			return 0
+		case a:
+			return -1
+		default:
+			return 1
+		}
+	}
+
+	cc := []func(a, b primazaiov1alpha1.RegisteredService) int{
+		func(a, b primazaiov1alpha1.RegisteredService) int {
+			return boolCmp(
+				a.Status.State == primazaiov1alpha1.RegisteredServiceStateAvailable,
+				b.Status.State == primazaiov1alpha1.RegisteredServiceStateAvailable)
+		},
+	}
+
+	if p.PreferredSLA != "" {
+		cc = append(cc, func(a, b primazaiov1alpha1.RegisteredService) int {
+			return boolCmp(a.Spec.SLA == p.PreferredSLA, b.Spec.SLA == p.PreferredSLA)
+		})
+	}
+
+	switch p.Strategy {
+	case primazaiov1alpha1.ServiceClaimSelectionStrategyRanked:
+		cc = append(cc, func(a, b primazaiov1alpha1.RegisteredService) int {
+			return cmp.Compare(rank(b), rank(a))
+		})
+	case primazaiov1alpha1.ServiceClaimSelectionStrategyLeastRecentlyClaimed:
+		cc = append(cc, func(a, b primazaiov1alpha1.RegisteredService) int {
+			ta, tb := a.Status.LastClaimTime, b.Status.LastClaimTime
+			switch {
+			case ta == nil || tb == nil:
+				return boolCmp(ta == nil, tb == nil)
+			case ta.Equal(tb):
+				return 0
+			case ta.Before(tb):
+				return -1
+			default:
+				return 1
+			}
+		})
+	}
+
+	return append(cc, func(a, b primazaiov1alpha1.RegisteredService) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+}
+
+func compareCandidates(a, b primazaiov1alpha1.RegisteredService, p primazaiov1alpha1.ServiceClaimSelectionPolicy) int {
+	for _, c := range selectionCriteria(p) {
+		if r := c(a, b); r != 0 {
+			return r
+		}
+	}
+	return 0
+}
+
+// lowerPriorityReason explains why the candidate was not preferred over the
+// selected RegisteredService
+func lowerPriorityReason(rs, selected primazaiov1alpha1.RegisteredService, p primazaiov1alpha1.ServiceClaimSelectionPolicy) string {
+	switch {
+	case selected.Spec.ReservedFor != "":
+		return fmt.Sprintf("'%s' has been provisioned for the service claim", selected.Name)
+	case rs.Status.State != selected.Status.State:
+		return fmt.Sprintf("state %s is less preferred than %s of '%s'", rs.Status.State, selected.Status.State, selected.Name)
+	case p.PreferredSLA != "" && rs.Spec.SLA != p.PreferredSLA && selected.Spec.SLA == p.PreferredSLA:
+		return fmt.Sprintf("SLA '%s' does not match preferred SLA '%s' of '%s'", rs.Spec.SLA, p.PreferredSLA, selected.Name)
+	case p.Strategy == primazaiov1alpha1.ServiceClaimSelectionStrategyRanked && rank(rs) != rank(selected):
+		return fmt.Sprintf("rank %d is lower than rank %d of '%s'", rank(rs), rank(selected), selected.Name)
+	case p.Strategy == primazaiov1alpha1.ServiceClaimSelectionStrategyLeastRecentlyClaimed &&
+		!rs.Status.LastClaimTime.Equal(selected.Status.LastClaimTime):
+		return fmt.Sprintf("claimed more recently than '%s'", selected.Name)
+	default:
+		return fmt.Sprintf("'%s' comes first in name order", selected.Name)
+	}
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/primaza/primaza/api/v1alpha1"
+	"github.com/primaza/primaza/pkg/envtag"
+	"github.com/primaza/primaza/pkg/primaza/constants"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("ServiceClaim RegisteredService selection", func() {
+	sci := []v1alpha1.ServiceClassIdentityItem{{Name: "type", Value: "psql"}}
+	sed := []v1alpha1.ServiceEndpointDefinitionItem{{Name: "host", Value: "localhost"}}
+
+	registeredService := func(name string, state v1alpha1.RegisteredServiceState, mutate ...func(*v1alpha1.RegisteredService)) v1alpha1.RegisteredService {
+		rs := v1alpha1.RegisteredService{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "primaza-system"},
+			Spec: v1alpha1.RegisteredServiceSpec{
+				ServiceClassIdentity:      sci,
+				ServiceEndpointDefinition: sed,
+			},
+			Status: v1alpha1.RegisteredServiceStatus{State: state},
+		}
+		for _, m := range mutate {
+			m(&rs)
+		}
+		return rs
+	}
+	claimedAt := func(t time.Time) func(*v1alpha1.RegisteredService) {
+		return func(rs *v1alpha1.RegisteredService) {
+			mt := metav1.NewTime(t)
+			rs.Status.LastClaimTime = &mt
+		}
+	}
+
+	DescribeTable("selects the expected RegisteredService",
+		func(policy *v1alpha1.ServiceClaimSelectionPolicy, rss []v1alpha1.RegisteredService, selected string, rejected []string) {
+			sclaim := v1alpha1.ServiceClaim{
+				Spec: v1alpha1.ServiceClaimSpec{
+					ServiceClassIdentity:          sci,
+					ServiceEndpointDefinitionKeys: []string{"host"},
+					SelectionPolicy:               policy,
+				},
+				Status: v1alpha1.ServiceClaimStatus{ClaimID: "claim-1"},
+			}
+
+			rs, selection := selectRegisteredService(sclaim, envtag.Environment{Name: "dev"}, rss)
+			if selected == "" {
+				Expect(rs).To(BeNil())
+			} else {
+				Expect(rs).NotTo(BeNil())
+				Expect(rs.Name).To(Equal(selected))
+			}
+			Expect(selection.Selected).To(Equal(selected))
+
+			names := []string{}
+			for _, r := range selection.Rejected {
+				names = append(names, r.Name)
+			}
+			Expect(names).To(ConsistOf(rejected))
+		},
+		Entry("no policy selects by name",
+			nil,
+			[]v1alpha1.RegisteredService{
+				registeredService("b", v1alpha1.RegisteredServiceStateAvailable),
+				registeredService("a", v1alpha1.RegisteredServiceStateAvailable),
+			},
+			"a", []string{"b"}),
+		Entry("non matching identity is not a candidate",
+			nil,
+			[]v1alpha1.RegisteredService{
+				registeredService("a", v1alpha1.RegisteredServiceStateAvailable, func(rs *v1alpha1.RegisteredService) {
+					rs.Spec.ServiceClassIdentity = []v1alpha1.ServiceClassIdentityItem{{Name: "type", Value: "mysql"}}
+				}),
+			},
+			"", []string{}),
+		Entry("claimed, unknown and constrained services are rejected",
+			nil,
+			[]v1alpha1.RegisteredService{
+				registeredService("a", v1alpha1.RegisteredServiceStateClaimed),
+				registeredService("b", v1alpha1.RegisteredServiceStateUnknown),
+				registeredService("c", v1alpha1.RegisteredServiceStateAvailable, func(rs *v1alpha1.RegisteredService) {
+					rs.Spec.Constraints = &v1alpha1.RegisteredServiceConstraints{Environments: []string{"!dev"}}
+				}),
+				registeredService("d", v1alpha1.RegisteredServiceStateAvailable, func(rs *v1alpha1.RegisteredService) {
+					rs.Spec.ServiceEndpointDefinition = nil
+				}),
+			},
+			"", []string{"a", "b", "c", "d"}),
+		Entry("available is preferred over unknown",
+			&v1alpha1.ServiceClaimSelectionPolicy{AllowUnknown: true},
+			[]v1alpha1.RegisteredService{
+				registeredService("a", v1alpha1.RegisteredServiceStateUnknown),
+				registeredService("b", v1alpha1.RegisteredServiceStateAvailable),
+			},
+			"b", []string{"a"}),
+		Entry("preferred SLA",
+			&v1alpha1.ServiceClaimSelectionPolicy{PreferredSLA: "gold"},
+			[]v1alpha1.RegisteredService{
+				registeredService("a", v1alpha1.RegisteredServiceStateAvailable),
+				registeredService("b", v1alpha1.RegisteredServiceStateAvailable, func(rs *v1alpha1.RegisteredService) {
+					rs.Spec.SLA = "gold"
+				}),
+			},
+			"b", []string{"a"}),
+		Entry("ranked",
+			&v1alpha1.ServiceClaimSelectionPolicy{Strategy: v1alpha1.ServiceClaimSelectionStrategyRanked},
+			[]v1alpha1.RegisteredService{
+				registeredService("a", v1alpha1.RegisteredServiceStateAvailable),
+				registeredService("b", v1alpha1.RegisteredServiceStateAvailable, func(rs *v1alpha1.RegisteredService) {
+					rs.Annotations = map[string]string{constants.RankAnnotation: "10"}
+				}),
+				registeredService("c", v1alpha1.RegisteredServiceStateAvailable, func(rs *v1alpha1.RegisteredService) {
+					rs.Annotations = map[string]string{constants.RankAnnotation: "5"}
+				}),
+			},
+			"b", []string{"a", "c"}),
+		Entry("ranked with extreme ranks",
+			&v1alpha1.ServiceClaimSelectionPolicy{Strategy: v1alpha1.ServiceClaimSelectionStrategyRanked},
+			[]v1alpha1.RegisteredService{
+				registeredService("a", v1alpha1.RegisteredServiceStateAvailable, func(rs *v1alpha1.RegisteredService) {
+					rs.Annotations = map[string]string{constants.RankAnnotation: strconv.Itoa(math.MinInt)}
+				}),
+				registeredService("b", v1alpha1.RegisteredServiceStateAvailable, func(rs *v1alpha1.RegisteredService) {
+					rs.Annotations = map[string]string{constants.RankAnnotation: strconv.Itoa(math.MaxInt)}
+				}),
+			},
+			"b", []string{"a"}),
+		Entry("service provisioned for another claim is rejected",
+			nil,
+			[]v1alpha1.RegisteredService{
+				registeredService("a", v1alpha1.RegisteredServiceStateAvailable, func(rs *v1alpha1.RegisteredService) {
+					rs.Spec.ReservedFor = "claim-2"
+				}),
+			},
+			"", []string{"a"}),
+		Entry("service provisioned for the claim is preferred",
+			&v1alpha1.ServiceClaimSelectionPolicy{PreferredSLA: "gold"},
+			[]v1alpha1.RegisteredService{
+				registeredService("a", v1alpha1.RegisteredServiceStateAvailable, func(rs *v1alpha1.RegisteredService) {
+					rs.Spec.SLA = "gold"
+				}),
+				registeredService("b", v1alpha1.RegisteredServiceStateAvailable, func(rs *v1alpha1.RegisteredService) {
+					rs.Spec.ReservedFor = "claim-1"
+				}),
+			},
+			"b", []string{"a"}),
+		Entry("least recently claimed",
+			&v1alpha1.ServiceClaimSelectionPolicy{Strategy: v1alpha1.ServiceClaimSelectionStrategyLeastRecentlyClaimed},
+			[]v1alpha1.RegisteredService{
+				registeredService("a", v1alpha1.RegisteredServiceStateAvailable, claimedAt(time.Now())),
+				registeredService("b", v1alpha1.RegisteredServiceStateAvailable, claimedAt(time.Now().Add(-time.Hour))),
+			},
+			"b", []string{"a"}),
+		Entry("never claimed is least recently claimed",
+			&v1alpha1.ServiceClaimSelectionPolicy{Strategy: v1alpha1.ServiceClaimSelectionStrategyLeastRecentlyClaimed},
+			[]v1alpha1.RegisteredService{
+				registeredService("a", v1alpha1.RegisteredServiceStateAvailable, claimedAt(time.Now().Add(-time.Hour))),
+				registeredService("b", v1alpha1.RegisteredServiceStateAvailable),
+			},
+			"b", []string{"a"}),
+	)
+})
@@ -0,0 +1,201 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/primaza/primaza/api/v1alpha1"
+	"github.com/primaza/primaza/pkg/primaza/controlplane"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("ServiceClaim reconciler tests", func() {
+	Describe("RegisteredService sharing", func() {
+		var (
+			ctx context.Context
+			cli client.Client
+			r   ServiceClaimReconciler
+		)
+
+		newRegisteredService := func(mode v1alpha1.RegisteredServiceSharingMode, maxClaims int) *v1alpha1.RegisteredService {
+			rs := &v1alpha1.RegisteredService{
+				ObjectMeta: metav1.ObjectMeta{Name: "rs", Namespace: "primaza-system"},
+				Spec: v1alpha1.RegisteredServiceSpec{
+					SharingMode: mode,
+					MaxClaims:   maxClaims,
+				},
+				Status: v1alpha1.RegisteredServiceStatus{State: v1alpha1.RegisteredServiceStateAvailable},
+			}
+
+			scheme := runtime.NewScheme()
+			Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+			cli = fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(rs).
+				WithStatusSubresource(rs).
+				Build()
+			r = ServiceClaimReconciler{Client: cli, Scheme: scheme}
+
+			Expect(cli.Get(ctx, client.ObjectKeyFromObject(rs), rs)).To(Succeed())
+			return rs
+		}
+
+		BeforeEach(func() {
+			ctx = context.Background()
+		})
+
+		It("should mark an exclusive service as claimed at the first claim", func() {
+			rs := newRegisteredService(v1alpha1.RegisteredServiceSharingModeExclusive, 0)
+
+			Expect(r.claimService(ctx, rs, "claim-1")).To(Succeed())
+			Expect(rs.Status.State).To(Equal(v1alpha1.RegisteredServiceStateClaimed))
+			Expect(rs.Status.Claims).To(ConsistOf("claim-1"))
+			Expect(rs.Status.LastClaimTime).NotTo(BeNil())
+
+			Expect(r.releaseService(ctx, rs, "claim-1")).To(Succeed())
+			Expect(rs.Status.State).To(Equal(v1alpha1.RegisteredServiceStateAvailable))
+			Expect(rs.Status.Claims).To(BeEmpty())
+		})
+
+		It("should keep a shared service available until capacity is exhausted", func() {
+			rs := newRegisteredService(v1alpha1.RegisteredServiceSharingModeShared, 2)
+
+			Expect(r.claimService(ctx, rs, "claim-1")).To(Succeed())
+			Expect(rs.Status.State).To(Equal(v1alpha1.RegisteredServiceStateAvailable))
+
+			// claiming twice with the same claim does not consume capacity
+			Expect(r.claimService(ctx, rs, "claim-1")).To(Succeed())
+			Expect(rs.Status.State).To(Equal(v1alpha1.RegisteredServiceStateAvailable))
+
+			Expect(r.claimService(ctx, rs, "claim-2")).To(Succeed())
+			Expect(rs.Status.State).To(Equal(v1alpha1.RegisteredServiceStateClaimed))
+			Expect(rs.Status.Claims).To(ConsistOf("claim-1", "claim-2"))
+
+			Expect(r.releaseService(ctx, rs, "claim-2")).To(Succeed())
+			Expect(rs.Status.State).To(Equal(v1alpha1.RegisteredServiceStateAvailable))
+			Expect(rs.Status.Claims).To(ConsistOf("claim-1"))
+		})
+
+		It("should never exhaust an unlimited shared service", func() {
+			rs := newRegisteredService(v1alpha1.RegisteredServiceSharingModeShared, 0)
+
+			for _, id := range []string{"claim-1", "claim-2", "claim-3"} {
+				Expect(r.claimService(ctx, rs, id)).To(Succeed())
+			}
+			Expect(rs.Status.State).To(Equal(v1alpha1.RegisteredServiceStateAvailable))
+			Expect(rs.Status.Claims).To(HaveLen(3))
+		})
+	})
+
+	DescribeTable("ServiceClaim bindings",
+		func(rr []controlplane.ServiceBindingPushResult, expected []v1alpha1.ServiceClaimBinding, ready bool) {
+			bb := serviceClaimBindings("ce", rr)
+			Expect(bb).To(Equal(expected))
+			Expect(v1alpha1.ServiceClaimStatus{Bindings: bb}.BindingsReady()).To(Equal(ready))
+		},
+		Entry("ready binding",
+			[]controlplane.ServiceBindingPushResult{{
+				Namespace: "app",
+				Status: &v1alpha1.ServiceBindingStatus{
+					State:       v1alpha1.ServiceBindingStateReady,
+					Connections: []v1alpha1.BoundWorkload{{Name: "workload"}},
+				},
+			}},
+			[]v1alpha1.ServiceClaimBinding{{
+				ClusterEnvironment: "ce",
+				Namespace:          "app",
+				State:              v1alpha1.ServiceBindingStateReady,
+				Workloads:          []v1alpha1.BoundWorkload{{Name: "workload"}},
+			}},
+			true),
+		Entry("failed push",
+			[]controlplane.ServiceBindingPushResult{
+				{Namespace: "app", Status: &v1alpha1.ServiceBindingStatus{State: v1alpha1.ServiceBindingStateReady}},
+				{Namespace: "other", Err: errors.New("forbidden")},
+			},
+			[]v1alpha1.ServiceClaimBinding{
+				{ClusterEnvironment: "ce", Namespace: "app", State: v1alpha1.ServiceBindingStateReady},
+				{ClusterEnvironment: "ce", Namespace: "other", LastError: "forbidden"},
+			},
+			false),
+		Entry("binding not yet reconciled by the agent",
+			[]controlplane.ServiceBindingPushResult{{Namespace: "app", Status: &v1alpha1.ServiceBindingStatus{}}},
+			[]v1alpha1.ServiceClaimBinding{{ClusterEnvironment: "ce", Namespace: "app"}},
+			false),
+	)
+
+	Describe("ServiceClaim bindings refresh", func() {
+		now := time.Now()
+		pending := []v1alpha1.ServiceClaimBinding{{ClusterEnvironment: "ce", Namespace: "app"}}
+
+		It("should back off between refreshes", func() {
+			s := v1alpha1.ServiceClaimStatus{State: v1alpha1.ServiceClaimStateResolved, Bindings: pending}
+
+			scheduleBindingsRefresh(&s, 0, now)
+			Expect(s.BindingsRefresh.Time.Time).To(Equal(now.Add(BindingsRefreshInterval)))
+			scheduleBindingsRefresh(&s, 2, now)
+			Expect(s.BindingsRefresh.Time.Time).To(Equal(now.Add(4 * BindingsRefreshInterval)))
+		})
+
+		It("should stop refreshing after the maximum number of refreshes", func() {
+			s := v1alpha1.ServiceClaimStatus{State: v1alpha1.ServiceClaimStateResolved, Bindings: pending}
+
+			scheduleBindingsRefresh(&s, MaxBindingsRefreshes, now)
+			Expect(s.BindingsRefresh).To(BeNil())
+			Expect(bindingsRefreshRequeueAfter(v1alpha1.ServiceClaim{Status: s}, now)).To(BeZero())
+		})
+
+		It("should not refresh bindings that are ready, even without bound workloads", func() {
+			s := v1alpha1.ServiceClaimStatus{
+				State:    v1alpha1.ServiceClaimStateResolved,
+				Bindings: []v1alpha1.ServiceClaimBinding{{ClusterEnvironment: "ce", Namespace: "app", State: v1alpha1.ServiceBindingStateReady}},
+			}
+
+			scheduleBindingsRefresh(&s, 0, now)
+			Expect(s.BindingsRefresh).To(BeNil())
+			Expect(bindingsRefreshRequeueAfter(v1alpha1.ServiceClaim{Status: s}, now)).To(BeZero())
+		})
+
+		DescribeTable("due refreshes",
+			func(s v1alpha1.ServiceClaimStatus, due bool) {
+				Expect(bindingsRefreshDue(v1alpha1.ServiceClaim{Status: s}, now)).To(Equal(due))
+			},
+			Entry("scheduled in the past", v1alpha1.ServiceClaimStatus{
+				State:           v1alpha1.ServiceClaimStateResolved,
+				BindingsRefresh: &v1alpha1.ServiceClaimBindingsRefresh{Time: metav1.NewTime(now.Add(-time.Second))},
+			}, true),
+			Entry("scheduled in the future", v1alpha1.ServiceClaimStatus{
+				State:           v1alpha1.ServiceClaimStateResolved,
+				BindingsRefresh: &v1alpha1.ServiceClaimBindingsRefresh{Time: metav1.NewTime(now.Add(time.Second))},
+			}, false),
+			Entry("not scheduled", v1alpha1.ServiceClaimStatus{State: v1alpha1.ServiceClaimStateResolved}, false),
+			Entry("unreachable service", v1alpha1.ServiceClaimStatus{
+				State:            v1alpha1.ServiceClaimStateResolved,
+				UnreachableSince: &metav1.Time{Time: now},
+				BindingsRefresh:  &v1alpha1.ServiceClaimBindingsRefresh{Time: metav1.NewTime(now.Add(-time.Second))},
+			}, false),
+		)
+	})
+})
@@ -0,0 +1,223 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	primazaiov1alpha1 "github.com/primaza/primaza/api/v1alpha1"
+	"github.com/primaza/primaza/pkg/primaza/constants"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+//+kubebuilder:rbac:groups=primaza.io,namespace=system,resources=serviceclaimtemplates,verbs=get;list;watch
+//+kubebuilder:rbac:groups=primaza.io,namespace=system,resources=serviceclaimtemplates/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=primaza.io,namespace=system,resources=serviceclaimtemplates/finalizers,verbs=update
+
+// ServiceClaimTemplateReconciler reconciles a ServiceClaimTemplate object.
+// It generates one ServiceClaim per environment listed in the template,
+// which are then resolved by the ServiceClaimReconciler.
+type ServiceClaimTemplateReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+func (r *ServiceClaimTemplateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+	l.Info("Reconcile ServiceClaimTemplate")
+
+	var t primazaiov1alpha1.ServiceClaimTemplate
+	if err := r.Get(ctx, req.NamespacedName, &t); err != nil {
+		l.Info("unable to retrieve ServiceClaimTemplate", "error", err)
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !t.DeletionTimestamp.IsZero() {
+		// generated ServiceClaims are garbage collected
+		return ctrl.Result{}, nil
+	}
+
+	var scl primazaiov1alpha1.ServiceClaimList
+	if err := r.List(ctx, &scl,
+		client.InNamespace(t.Namespace),
+		client.MatchingLabels{constants.PrimazaServiceClaimTemplateLabel: t.Name}); err != nil {
+		l.Error(err, "unable to list the generated ServiceClaims")
+		return ctrl.Result{}, err
+	}
+	generated := map[string]primazaiov1alpha1.ServiceClaim{}
+	for _, sc := range scl.Items {
+		generated[sc.Name] = sc
+	}
+
+	var errs []error
+	claims := make([]primazaiov1alpha1.ServiceClaimTemplateClaimStatus, 0, len(t.Spec.Environments))
+	for _, env := range t.Spec.Environments {
+		name := t.ServiceClaimName(env.Name)
+		sc, found := generated[name]
+		delete(generated, name)
+
+		status, err := r.syncServiceClaim(ctx, &t, env, sc, found)
+		if err != nil {
+			l.Error(err, "unable to sync the generated ServiceClaim", "service-claim", name)
+			errs = append(errs, err)
+		}
+		claims = append(claims, status)
+	}
+
+	// prune the ServiceClaims generated for environments no longer listed
+	for _, sc := range generated {
+		if sc.HasDeletionTimestamp() {
+			continue
+		}
+		l.Info("deleting ServiceClaim generated for an environment no longer listed", "service-claim", sc.Name)
+		if err := r.Delete(ctx, &sc); client.IgnoreNotFound(err) != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	r.aggregateStatus(&t, claims)
+	if err := r.Status().Update(ctx, &t); err != nil {
+		l.Error(err, "unable to update the ServiceClaimTemplate status")
+		errs = append(errs, err)
+	}
+	return ctrl.Result{}, errors.Join(errs...)
+}
+
+// syncServiceClaim ensures the ServiceClaim generated for the environment
+// exists and is up to date. As ServiceClaims' spec is immutable, outdated
+// ServiceClaims are deleted and created again on the next reconciliation.
+func (r *ServiceClaimTemplateReconciler) syncServiceClaim(
+	ctx context.Context,
+	t *primazaiov1alpha1.ServiceClaimTemplate,
+	env primazaiov1alpha1.ServiceClaimTemplateEnvironment,
+	sc primazaiov1alpha1.ServiceClaim,
+	found bool,
+) (primazaiov1alpha1.ServiceClaimTemplateClaimStatus, error) {
+	status := primazaiov1alpha1.ServiceClaimTemplateClaimStatus{
+		Environment: env.Name,
+		Name:        t.ServiceClaimName(env.Name),
+	}
+
+	spec := t.ServiceClaimSpecFor(env)
+	hash, err := specHash(spec)
+	if err != nil {
+		return status, err
+	}
+
+	switch {
+	case !found:
+		nsc := primazaiov1alpha1.ServiceClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      status.Name,
+				Namespace: t.Namespace,
+				Labels: map[string]string{
+					constants.PrimazaServiceClaimTemplateLabel: t.Name,
+					constants.PrimazaEnvironmentLabel:          env.Name,
+				},
+				Annotations: map[string]string{
+					constants.ServiceClaimTemplateHashAnnotation: hash,
+				},
+			},
+			Spec: spec,
+		}
+		if err := controllerutil.SetControllerReference(t, &nsc, r.Scheme); err != nil {
+			return status, err
+		}
+		if err := r.Create(ctx, &nsc); err != nil {
+			status.Message = fmt.Sprintf("unable to create the ServiceClaim: %s", err)
+			return status, err
+		}
+		status.State = primazaiov1alpha1.ServiceClaimStatePending
+		status.Message = "ServiceClaim created"
+
+	case sc.HasDeletionTimestamp():
+		status.Message = "waiting for the outdated ServiceClaim to be deleted"
+
+	case sc.Annotations[constants.ServiceClaimTemplateHashAnnotation] != hash:
+		log.FromContext(ctx).Info("replacing outdated ServiceClaim", "service-claim", sc.Name)
+		if err := r.Delete(ctx, &sc); client.IgnoreNotFound(err) != nil {
+			return status, err
+		}
+		status.Message = "waiting for the outdated ServiceClaim to be deleted"
+
+	default:
+		status.State = sc.Status.State
+		if c := meta.FindStatusCondition(sc.Status.Conditions, string(primazaiov1alpha1.ServiceClaimConditionReady)); c != nil &&
+			sc.Status.State != primazaiov1alpha1.ServiceClaimStateResolved {
+			status.Message = c.Message
+		}
+	}
+	return status, nil
+}
+
+// aggregateStatus summarizes the state of the generated ServiceClaims in the
+// ServiceClaimTemplate's status
+func (r *ServiceClaimTemplateReconciler) aggregateStatus(
+	t *primazaiov1alpha1.ServiceClaimTemplate,
+	claims []primazaiov1alpha1.ServiceClaimTemplateClaimStatus,
+) {
+	resolved := int32(0)
+	for _, c := range claims {
+		if c.State == primazaiov1alpha1.ServiceClaimStateResolved {
+			resolved++
+		}
+	}
+	t.Status.Claims = claims
+	t.Status.Resolved = resolved
+
+	c := metav1.Condition{
+		Type:    primazaiov1alpha1.ServiceClaimTemplateConditionReady,
+		Status:  metav1.ConditionTrue,
+		Reason:  constants.ServiceClaimsResolvedReason,
+		Message: "all the generated ServiceClaims are Resolved",
+	}
+	if int(resolved) < len(claims) {
+		c.Status = metav1.ConditionFalse
+		c.Reason = constants.ServiceClaimsPendingReason
+		c.Message = fmt.Sprintf("%d of %d generated ServiceClaims are Resolved", resolved, len(claims))
+	}
+	meta.SetStatusCondition(&t.Status.Conditions, c)
+}
+
+// specHash returns the hash of the ServiceClaim's spec, used to detect
+// outdated generated ServiceClaims
+func specHash(spec primazaiov1alpha1.ServiceClaimSpec) (string, error) {
+	b, err := json.Marshal(spec)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:8]), nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ServiceClaimTemplateReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&primazaiov1alpha1.ServiceClaimTemplate{}).
+		Owns(&primazaiov1alpha1.ServiceClaim{}).
+		Complete(r)
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/primaza/primaza/api/v1alpha1"
+	"github.com/primaza/primaza/pkg/primaza/constants"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("ServiceClaimTemplate", func() {
+	var (
+		ctx = context.Background()
+		cli client.Client
+		r   ServiceClaimTemplateReconciler
+		nn  = types.NamespacedName{Namespace: "primaza-system", Name: "orders-db"}
+	)
+
+	newTemplate := func(envs ...v1alpha1.ServiceClaimTemplateEnvironment) *v1alpha1.ServiceClaimTemplate {
+		return &v1alpha1.ServiceClaimTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: nn.Name, Namespace: nn.Namespace, UID: "template-uid"},
+			Spec: v1alpha1.ServiceClaimTemplateSpec{
+				Template: v1alpha1.ServiceClaimTemplateClaim{
+					ServiceClassIdentity:          []v1alpha1.ServiceClassIdentityItem{{Name: "type", Value: "psql"}},
+					ServiceEndpointDefinitionKeys: []string{"host", "password"},
+					Application:                   v1alpha1.ApplicationSelector{APIVersion: "apps/v1", Kind: "Deployment", Name: "orders"},
+					Parameters:                    map[string]string{"size": "1Gi"},
+				},
+				Environments: envs,
+			},
+		}
+	}
+	reconcile := func() {
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: nn})
+		Expect(err).NotTo(HaveOccurred())
+	}
+	generatedClaim := func(env string) v1alpha1.ServiceClaim {
+		sc := v1alpha1.ServiceClaim{}
+		Expect(cli.Get(ctx, types.NamespacedName{Namespace: nn.Namespace, Name: nn.Name + "-" + env}, &sc)).To(Succeed())
+		return sc
+	}
+	setup := func(objs ...client.Object) {
+		scheme := runtime.NewScheme()
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		cli = fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(objs...).
+			WithStatusSubresource(&v1alpha1.ServiceClaimTemplate{}, &v1alpha1.ServiceClaim{}).
+			Build()
+		r = ServiceClaimTemplateReconciler{Client: cli, Scheme: scheme}
+	}
+
+	It("generates one ServiceClaim per environment applying the overrides", func() {
+		setup(newTemplate(
+			v1alpha1.ServiceClaimTemplateEnvironment{Name: "dev"},
+			v1alpha1.ServiceClaimTemplateEnvironment{
+				Name:                 "prod",
+				ServiceClassIdentity: []v1alpha1.ServiceClassIdentityItem{{Name: "type", Value: "psql-ha"}, {Name: "tier", Value: "gold"}},
+				Parameters:           map[string]string{"size": "100Gi"},
+			},
+		))
+		reconcile()
+
+		dev := generatedClaim("dev")
+		Expect(dev.Spec.Target).To(Equal(&v1alpha1.ServiceClaimTarget{EnvironmentTag: "dev"}))
+		Expect(dev.Spec.ServiceClassIdentity).To(Equal([]v1alpha1.ServiceClassIdentityItem{{Name: "type", Value: "psql"}}))
+		Expect(dev.Spec.Parameters).To(Equal(map[string]string{"size": "1Gi"}))
+		Expect(dev.Labels).To(HaveKeyWithValue(constants.PrimazaServiceClaimTemplateLabel, nn.Name))
+		Expect(dev.OwnerReferences).To(HaveLen(1))
+
+		prod := generatedClaim("prod")
+		Expect(prod.Spec.Target).To(Equal(&v1alpha1.ServiceClaimTarget{EnvironmentTag: "prod"}))
+		Expect(prod.Spec.ServiceClassIdentity).To(Equal([]v1alpha1.ServiceClassIdentityItem{
+			{Name: "type", Value: "psql-ha"},
+			{Name: "tier", Value: "gold"},
+		}))
+		Expect(prod.Spec.ServiceEndpointDefinitionKeys).To(Equal([]string{"host", "password"}))
+		Expect(prod.Spec.Parameters).To(Equal(map[string]string{"size": "100Gi"}))
+	})
+
+	It("aggregates the state of the generated ServiceClaims", func() {
+		setup(newTemplate(
+			v1alpha1.ServiceClaimTemplateEnvironment{Name: "dev"},
+			v1alpha1.ServiceClaimTemplateEnvironment{Name: "prod"},
+		))
+		reconcile()
+
+		dev := generatedClaim("dev")
+		dev.Status.State = v1alpha1.ServiceClaimStateResolved
+		Expect(cli.Status().Update(ctx, &dev)).To(Succeed())
+		prod := generatedClaim("prod")
+		prod.Status.State = v1alpha1.ServiceClaimStatePending
+		meta.SetStatusCondition(&prod.Status.Conditions, metav1.Condition{
+			Type:    string(v1alpha1.ServiceClaimConditionReady),
+			Status:  metav1.ConditionFalse,
+			Reason:  constants.NoMatchingServiceFoundReason,
+			Message: "SCI is not matched",
+		})
+		Expect(cli.Status().Update(ctx, &prod)).To(Succeed())
+		reconcile()
+
+		t := v1alpha1.ServiceClaimTemplate{}
+		Expect(cli.Get(ctx, nn, &t)).To(Succeed())
+		Expect(t.Status.Resolved).To(Equal(int32(1)))
+		Expect(t.Status.Claims).To(Equal([]v1alpha1.ServiceClaimTemplateClaimStatus{
+			{Environment: "dev", Name: "orders-db-dev", State: v1alpha1.ServiceClaimStateResolved},
+			{Environment: "prod", Name: "orders-db-prod", State: v1alpha1.ServiceClaimStatePending, Message: "SCI is not matched"},
+		}))
+		Expect(meta.IsStatusConditionFalse(t.Status.Conditions, v1alpha1.ServiceClaimTemplateConditionReady)).To(BeTrue())
+	})
+
+	It("replaces outdated ServiceClaims and prunes the ones of removed environments", func() {
+		setup(newTemplate(
+			v1alpha1.ServiceClaimTemplateEnvironment{Name: "dev"},
+			v1alpha1.ServiceClaimTemplateEnvironment{Name: "prod"},
+		))
+		reconcile()
+
+		t := v1alpha1.ServiceClaimTemplate{}
+		Expect(cli.Get(ctx, nn, &t)).To(Succeed())
+		t.Spec.Environments = []v1alpha1.ServiceClaimTemplateEnvironment{
+			{Name: "dev", ServiceEndpointDefinitionKeys: []string{"host"}},
+		}
+		Expect(cli.Update(ctx, &t)).To(Succeed())
+		reconcile()
+
+		scl := v1alpha1.ServiceClaimList{}
+		Expect(cli.List(ctx, &scl, client.InNamespace(nn.Namespace))).To(Succeed())
+		Expect(scl.Items).To(BeEmpty())
+
+		// the outdated ServiceClaim is created again once deleted
+		reconcile()
+		Expect(generatedClaim("dev").Spec.ServiceEndpointDefinitionKeys).To(Equal([]string{"host"}))
+		Expect(cli.List(ctx, &scl, client.InNamespace(nn.Namespace))).To(Succeed())
+		Expect(scl.Items).To(HaveLen(1))
+	})
+})
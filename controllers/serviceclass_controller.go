@@ -159,7 +159,7 @@ func (r *ServiceClassReconciler) filterClusterEnvironments(
 
 	cee := []primazaiov1alpha1.ClusterEnvironment{}
 	for _, ce := range clusterEnvironments {
-		if envtag.Match(ce.Spec.EnvironmentName, environmentConstraints) {
+		if envtag.MatchEnvironment(environmentOf(ce), environmentConstraints) {
 			cee = append(cee, ce)
 		}
 	}
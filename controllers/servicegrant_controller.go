@@ -0,0 +1,386 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	primazaiov1alpha1 "github.com/primaza/primaza/api/v1alpha1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+//+kubebuilder:rbac:groups=primaza.io,namespace=system,resources=servicegrants,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=primaza.io,namespace=system,resources=servicegrants/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=primaza.io,namespace=system,resources=servicegrants/finalizers,verbs=update
+//+kubebuilder:rbac:groups=primaza.io,namespace=system,resources=servicegrantreferences,verbs=get;list;watch
+//+kubebuilder:rbac:groups=primaza.io,resources=servicegrantreferences,verbs=create;patch;delete
+//+kubebuilder:rbac:groups=rbac.authorization.k8s.io,namespace=system,resources=roles,verbs=get;list;watch;create;update;patch;delete
+
+// ServiceGrantFinalizer is the finalizer removing the ServiceGrantReferences
+// published to the consumer tenants
+const ServiceGrantFinalizer = "servicegrants.primaza.io/finalizer"
+
+// ServiceGrantReconciler reconciles a ServiceGrant object.
+// It records the granted RegisteredServices, and allows the control planes
+// of the consumer tenants to read and claim them, and to read the Secrets
+// their Service Endpoint Definition refers to. The granted RegisteredServices
+// are published to the consumer tenants as a ServiceGrantReference in their
+// namespace.
+type ServiceGrantReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+func (r *ServiceGrantReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+	l.Info("Reconcile ServiceGrant")
+
+	var g primazaiov1alpha1.ServiceGrant
+	if err := r.Get(ctx, req.NamespacedName, &g); err != nil {
+		l.Info("unable to retrieve ServiceGrant", "error", err)
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !g.DeletionTimestamp.IsZero() {
+		// the Role and RoleBinding are garbage collected, while the
+		// ServiceGrantReferences live in the namespaces of the consumer
+		// tenants
+		if !controllerutil.ContainsFinalizer(&g, ServiceGrantFinalizer) {
+			return ctrl.Result{}, nil
+		}
+		if err := r.unpublishGrant(ctx, g, append(serviceGrantTenants(g), g.Status.Tenants...)); err != nil {
+			l.Error(err, "unable to delete the ServiceGrantReferences")
+			return ctrl.Result{}, err
+		}
+		controllerutil.RemoveFinalizer(&g, ServiceGrantFinalizer)
+		return ctrl.Result{}, r.Update(ctx, &g)
+	}
+	if controllerutil.AddFinalizer(&g, ServiceGrantFinalizer) {
+		if err := r.Update(ctx, &g); err != nil {
+			l.Error(err, "unable to add the finalizer to the ServiceGrant")
+			return ctrl.Result{}, err
+		}
+	}
+
+	var rsl primazaiov1alpha1.RegisteredServiceList
+	if err := r.List(ctx, &rsl, client.InNamespace(g.Namespace)); err != nil {
+		l.Error(err, "unable to list the RegisteredServices")
+		return ctrl.Result{}, err
+	}
+	granted := []primazaiov1alpha1.RegisteredService{}
+	names := []string{}
+	for _, rs := range rsl.Items {
+		if g.Grants(rs) {
+			granted = append(granted, rs)
+			names = append(names, rs.Name)
+		}
+	}
+	slices.Sort(names)
+	slices.SortFunc(granted, func(a, b primazaiov1alpha1.RegisteredService) int { return cmp.Compare(a.Name, b.Name) })
+
+	if err := r.grantAccess(ctx, g, granted); err != nil {
+		l.Error(err, "unable to grant access to the RegisteredServices")
+		return ctrl.Result{}, err
+	}
+
+	tenants, err := r.publishGrant(ctx, g, granted)
+	if err != nil {
+		l.Error(err, "unable to publish the ServiceGrant to the consumer tenants")
+		return ctrl.Result{}, err
+	}
+
+	if slices.Equal(g.Status.RegisteredServices, names) && slices.Equal(g.Status.Tenants, tenants) {
+		return ctrl.Result{}, nil
+	}
+	g.Status.RegisteredServices = names
+	g.Status.Tenants = tenants
+	if err := r.Status().Update(ctx, &g); err != nil {
+		l.Error(err, "unable to update the ServiceGrant status")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// serviceGrantRoleName returns the name of the Role, and of the RoleBinding,
+// giving the consumer tenants access to the granted RegisteredServices
+func serviceGrantRoleName(g primazaiov1alpha1.ServiceGrant) string {
+	return fmt.Sprintf("primaza-servicegrant-%s", g.Name)
+}
+
+// grantAccess creates or updates the Role allowing to read and claim the
+// granted RegisteredServices, and binds it to the service account the
+// control plane of each consumer tenant runs as
+func (r *ServiceGrantReconciler) grantAccess(
+	ctx context.Context,
+	g primazaiov1alpha1.ServiceGrant,
+	granted []primazaiov1alpha1.RegisteredService,
+) error {
+	role := rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Name: serviceGrantRoleName(g), Namespace: g.Namespace}}
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, &role, func() error {
+		role.Rules = serviceGrantRules(granted)
+		return controllerutil.SetControllerReference(&g, &role, r.Scheme)
+	}); err != nil {
+		return err
+	}
+
+	rb := rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: serviceGrantRoleName(g), Namespace: g.Namespace}}
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, &rb, func() error {
+		rb.RoleRef = rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     role.Name,
+		}
+		rb.Subjects = []rbacv1.Subject{}
+		for _, t := range g.Spec.Tenants {
+			if g.GrantsTo(t) {
+				rb.Subjects = append(rb.Subjects, rbacv1.Subject{
+					Kind:      rbacv1.ServiceAccountKind,
+					Name:      TenantManagerServiceAccountName,
+					Namespace: t,
+				})
+			}
+		}
+		return controllerutil.SetControllerReference(&g, &rb, r.Scheme)
+	})
+	return err
+}
+
+// serviceGrantRules returns the rules allowing to read and claim the granted
+// RegisteredServices, and to read the Secrets their Service Endpoint
+// Definition refers to
+func serviceGrantRules(granted []primazaiov1alpha1.RegisteredService) []rbacv1.PolicyRule {
+	names, secrets := []string{}, []string{}
+	for _, rs := range granted {
+		names = append(names, rs.Name)
+		for _, sed := range rs.Spec.ServiceEndpointDefinition {
+			if sed.ValueFromSecret != nil {
+				secrets = append(secrets, sed.ValueFromSecret.Name)
+			}
+		}
+	}
+	// an empty list of resource names would allow access to all resources
+	if len(names) == 0 {
+		return []rbacv1.PolicyRule{}
+	}
+	slices.Sort(names)
+	slices.Sort(secrets)
+
+	rules := []rbacv1.PolicyRule{
+		{
+			APIGroups:     []string{primazaiov1alpha1.GroupVersion.Group},
+			Resources:     []string{"registeredservices"},
+			ResourceNames: names,
+			Verbs:         []string{"get"},
+		},
+		{
+			APIGroups:     []string{primazaiov1alpha1.GroupVersion.Group},
+			Resources:     []string{"registeredservices/status"},
+			ResourceNames: names,
+			Verbs:         []string{"get", "update", "patch"},
+		},
+	}
+	if secrets = slices.Compact(secrets); len(secrets) > 0 {
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups:     []string{""},
+			Resources:     []string{"secrets"},
+			ResourceNames: secrets,
+			Verbs:         []string{"get"},
+		})
+	}
+	return rules
+}
+
+// serviceGrantReferenceName returns the name of the ServiceGrantReference
+// published to the consumer tenants. Namespaces can not contain dots, so the
+// name is unique among the ServiceGrants of all tenants.
+func serviceGrantReferenceName(g primazaiov1alpha1.ServiceGrant) string {
+	return fmt.Sprintf("%s.%s", g.Namespace, g.Name)
+}
+
+// serviceGrantTenants returns the consumer tenants the ServiceGrant grants
+// the RegisteredServices to
+func serviceGrantTenants(g primazaiov1alpha1.ServiceGrant) []string {
+	tenants := []string{}
+	for _, t := range g.Spec.Tenants {
+		if g.GrantsTo(t) {
+			tenants = append(tenants, t)
+		}
+	}
+	slices.Sort(tenants)
+	return slices.Compact(tenants)
+}
+
+// publishGrant creates or updates the ServiceGrantReference in the namespace
+// of each consumer tenant, and deletes the ones of the tenants the
+// RegisteredServices are not granted to anymore. It returns the tenants the
+// ServiceGrant is published to.
+func (r *ServiceGrantReconciler) publishGrant(
+	ctx context.Context,
+	g primazaiov1alpha1.ServiceGrant,
+	granted []primazaiov1alpha1.RegisteredService,
+) ([]string, error) {
+	spec := primazaiov1alpha1.ServiceGrantReferenceSpec{
+		Tenant:             g.Namespace,
+		ServiceGrant:       g.Name,
+		RegisteredServices: []primazaiov1alpha1.GrantedRegisteredService{},
+	}
+	for _, rs := range granted {
+		spec.RegisteredServices = append(spec.RegisteredServices, primazaiov1alpha1.GrantedRegisteredService{
+			Name:       rs.Name,
+			Generation: rs.Generation,
+			State:      rs.Status.State,
+		})
+	}
+
+	tenants := serviceGrantTenants(g)
+	errs := []error{}
+	for _, t := range tenants {
+		errs = append(errs, r.publishGrantReference(ctx, g, t, spec))
+	}
+	revoked := slices.DeleteFunc(slices.Clone(g.Status.Tenants), func(t string) bool { return slices.Contains(tenants, t) })
+	errs = append(errs, r.unpublishGrant(ctx, g, revoked))
+	return tenants, errors.Join(errs...)
+}
+
+// publishGrantReference creates or updates the ServiceGrantReference in the
+// namespace of the consumer tenant. The resources of the consumer tenants
+// can not be read, so the ServiceGrantReference is patched without
+// retrieving it first.
+func (r *ServiceGrantReconciler) publishGrantReference(
+	ctx context.Context,
+	g primazaiov1alpha1.ServiceGrant,
+	tenant string,
+	spec primazaiov1alpha1.ServiceGrantReferenceSpec,
+) error {
+	ref := primazaiov1alpha1.ServiceGrantReference{
+		ObjectMeta: metav1.ObjectMeta{Name: serviceGrantReferenceName(g), Namespace: tenant},
+		Spec:       spec,
+	}
+	// the list of RegisteredServices is always included, so that it is
+	// emptied when no RegisteredService is granted anymore
+	p, err := json.Marshal(map[string]any{"spec": map[string]any{
+		"tenant":             spec.Tenant,
+		"serviceGrant":       spec.ServiceGrant,
+		"registeredServices": spec.RegisteredServices,
+	}})
+	if err != nil {
+		return err
+	}
+	err = r.Patch(ctx, ref.DeepCopy(), client.RawPatch(types.MergePatchType, p))
+	if apierrors.IsNotFound(err) {
+		return r.Create(ctx, &ref)
+	}
+	return err
+}
+
+// unpublishGrant deletes the ServiceGrantReferences of the given tenants
+func (r *ServiceGrantReconciler) unpublishGrant(ctx context.Context, g primazaiov1alpha1.ServiceGrant, tenants []string) error {
+	errs := []error{}
+	for _, t := range tenants {
+		ref := primazaiov1alpha1.ServiceGrantReference{
+			ObjectMeta: metav1.ObjectMeta{Name: serviceGrantReferenceName(g), Namespace: t},
+		}
+		errs = append(errs, client.IgnoreNotFound(r.Delete(ctx, &ref)))
+	}
+	return errors.Join(errs...)
+}
+
+// grantedRegisteredServices returns the RegisteredServices other tenants
+// grant to the given tenant, as published by the ServiceGrantReferences of
+// the tenant's namespace, which are read with the given client. As the
+// resources of other tenants are not cached, the given reader is expected to
+// read the RegisteredServices from the API server.
+func grantedRegisteredServices(
+	ctx context.Context,
+	cli client.Reader,
+	reader client.Reader,
+	tenant string,
+) ([]primazaiov1alpha1.RegisteredService, error) {
+	var rl primazaiov1alpha1.ServiceGrantReferenceList
+	if err := cli.List(ctx, &rl, client.InNamespace(tenant)); err != nil {
+		return nil, err
+	}
+
+	granted := []primazaiov1alpha1.RegisteredService{}
+	for _, ref := range rl.Items {
+		if ref.Spec.Tenant == tenant {
+			continue
+		}
+		for _, s := range ref.Spec.RegisteredServices {
+			var rs primazaiov1alpha1.RegisteredService
+			if err := reader.Get(ctx, types.NamespacedName{Namespace: ref.Spec.Tenant, Name: s.Name}, &rs); err != nil {
+				// the RegisteredService may have been deleted, or the
+				// owning tenant may not have granted access to it:
+				// only the owning tenant's Role allows reading it
+				if apierrors.IsNotFound(err) || apierrors.IsForbidden(err) {
+					continue
+				}
+				return nil, err
+			}
+			// the same RegisteredService may be granted more than once
+			if slices.ContainsFunc(granted, func(o primazaiov1alpha1.RegisteredService) bool { return o.UID == rs.UID }) {
+				continue
+			}
+			granted = append(granted, rs)
+		}
+	}
+	return granted, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ServiceGrantReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// RegisteredServices may start or stop matching the ServiceGrants of
+	// their namespace, and their Service Endpoint Definition may refer
+	// to other Secrets
+	reconcileNamespaceServiceGrants := func(ctx context.Context, a client.Object) []reconcile.Request {
+		l := log.FromContext(ctx)
+
+		var gl primazaiov1alpha1.ServiceGrantList
+		if err := r.List(ctx, &gl, client.InNamespace(a.GetNamespace())); err != nil {
+			l.Error(err, "unable to list the ServiceGrants to reconcile", "object", a.GetName())
+			return []reconcile.Request{}
+		}
+
+		rr := make([]reconcile.Request, 0, len(gl.Items))
+		for _, g := range gl.Items {
+			rr = append(rr, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: g.Namespace, Name: g.Name}})
+		}
+		return rr
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&primazaiov1alpha1.ServiceGrant{}).
+		Owns(&rbacv1.Role{}).
+		Owns(&rbacv1.RoleBinding{}).
+		Watches(&primazaiov1alpha1.RegisteredService{},
+			handler.EnqueueRequestsFromMapFunc(reconcileNamespaceServiceGrants)).
+		Complete(r)
+}
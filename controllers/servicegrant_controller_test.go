@@ -0,0 +1,244 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/primaza/primaza/api/v1alpha1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("ServiceGrant", func() {
+	ctx := context.Background()
+	nn := types.NamespacedName{Namespace: "platform", Name: "shared"}
+
+	newRegisteredService := func(name string, state v1alpha1.RegisteredServiceState, sci ...v1alpha1.ServiceClassIdentityItem) *v1alpha1.RegisteredService {
+		return &v1alpha1.RegisteredService{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: nn.Namespace, UID: types.UID(name)},
+			Spec: v1alpha1.RegisteredServiceSpec{
+				ServiceClassIdentity: sci,
+				ServiceEndpointDefinition: []v1alpha1.ServiceEndpointDefinitionItem{
+					{Name: "host", Value: name},
+					{Name: "password", ValueFromSecret: &v1alpha1.ServiceEndpointDefinitionSecretRef{Name: name + "-credentials", Key: "password"}},
+				},
+			},
+			Status: v1alpha1.RegisteredServiceStatus{State: state},
+		}
+	}
+	psql := v1alpha1.ServiceClassIdentityItem{Name: "type", Value: "psql"}
+	redis := v1alpha1.ServiceClassIdentityItem{Name: "type", Value: "redis"}
+	newGrant := func(names []string, sci []v1alpha1.ServiceClassIdentityItem, tenants ...string) *v1alpha1.ServiceGrant {
+		return &v1alpha1.ServiceGrant{
+			ObjectMeta: metav1.ObjectMeta{Name: nn.Name, Namespace: nn.Namespace},
+			Spec: v1alpha1.ServiceGrantSpec{
+				RegisteredServices:   names,
+				ServiceClassIdentity: sci,
+				Tenants:              tenants,
+			},
+		}
+	}
+	newClient := func(objs ...client.Object) client.Client {
+		scheme := runtime.NewScheme()
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		Expect(rbacv1.AddToScheme(scheme)).To(Succeed())
+		return fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(objs...).
+			WithStatusSubresource(objs...).
+			Build()
+	}
+
+	Describe("granting access", func() {
+		It("grants the matching registered services to the consumer tenants", func() {
+			grant := newGrant([]string{"cache"}, []v1alpha1.ServiceClassIdentityItem{psql}, "team-a", nn.Namespace)
+			cli := newClient(grant,
+				newRegisteredService("db", v1alpha1.RegisteredServiceStateAvailable, psql),
+				newRegisteredService("cache", v1alpha1.RegisteredServiceStateAvailable, redis),
+				newRegisteredService("queue", v1alpha1.RegisteredServiceStateAvailable, redis))
+			r := ServiceGrantReconciler{Client: cli, Scheme: cli.Scheme()}
+
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: nn})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(cli.Get(ctx, nn, grant)).To(Succeed())
+			Expect(grant.Status.RegisteredServices).To(Equal([]string{"cache", "db"}))
+
+			k := types.NamespacedName{Namespace: nn.Namespace, Name: "primaza-servicegrant-shared"}
+			role := rbacv1.Role{}
+			Expect(cli.Get(ctx, k, &role)).To(Succeed())
+			Expect(role.Rules).To(HaveLen(3))
+			Expect(role.Rules[0].ResourceNames).To(Equal([]string{"cache", "db"}))
+			Expect(role.Rules[2].Resources).To(Equal([]string{"secrets"}))
+			Expect(role.Rules[2].ResourceNames).To(Equal([]string{"cache-credentials", "db-credentials"}))
+
+			rb := rbacv1.RoleBinding{}
+			Expect(cli.Get(ctx, k, &rb)).To(Succeed())
+			Expect(rb.RoleRef.Name).To(Equal(role.Name))
+			Expect(rb.Subjects).To(ConsistOf(rbacv1.Subject{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      TenantManagerServiceAccountName,
+				Namespace: "team-a",
+			}))
+
+			Expect(grant.Status.Tenants).To(Equal([]string{"team-a"}))
+			ref := v1alpha1.ServiceGrantReference{}
+			Expect(cli.Get(ctx, types.NamespacedName{Namespace: "team-a", Name: "platform.shared"}, &ref)).To(Succeed())
+			Expect(ref.Spec).To(Equal(v1alpha1.ServiceGrantReferenceSpec{
+				Tenant:       nn.Namespace,
+				ServiceGrant: nn.Name,
+				RegisteredServices: []v1alpha1.GrantedRegisteredService{
+					{Name: "cache", State: v1alpha1.RegisteredServiceStateAvailable},
+					{Name: "db", State: v1alpha1.RegisteredServiceStateAvailable},
+				},
+			}))
+			Expect(cli.Get(ctx, types.NamespacedName{Namespace: nn.Namespace, Name: "platform.shared"}, &ref)).NotTo(Succeed())
+		})
+
+		It("updates and deletes the references published to the consumer tenants", func() {
+			grant := newGrant([]string{"db"}, nil, "team-a", "team-b")
+			db := newRegisteredService("db", v1alpha1.RegisteredServiceStateAvailable, psql)
+			cli := newClient(grant, db)
+			r := ServiceGrantReconciler{Client: cli, Scheme: cli.Scheme()}
+
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: nn})
+			Expect(err).NotTo(HaveOccurred())
+
+			// stop granting the service to team-b, and the service to both tenants
+			Expect(cli.Get(ctx, nn, grant)).To(Succeed())
+			grant.Spec.RegisteredServices = []string{"missing"}
+			grant.Spec.Tenants = []string{"team-a"}
+			Expect(cli.Update(ctx, grant)).To(Succeed())
+			_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: nn})
+			Expect(err).NotTo(HaveOccurred())
+
+			ref := v1alpha1.ServiceGrantReference{}
+			Expect(cli.Get(ctx, types.NamespacedName{Namespace: "team-a", Name: "platform.shared"}, &ref)).To(Succeed())
+			Expect(ref.Spec.RegisteredServices).To(BeEmpty())
+			err = cli.Get(ctx, types.NamespacedName{Namespace: "team-b", Name: "platform.shared"}, &ref)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+			// delete the grant
+			Expect(cli.Delete(ctx, grant)).To(Succeed())
+			_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: nn})
+			Expect(err).NotTo(HaveOccurred())
+			err = cli.Get(ctx, types.NamespacedName{Namespace: "team-a", Name: "platform.shared"}, &ref)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			Expect(apierrors.IsNotFound(cli.Get(ctx, nn, grant))).To(BeTrue())
+		})
+
+		It("grants nothing when no registered service matches", func() {
+			grant := newGrant([]string{"missing"}, nil, "team-a")
+			cli := newClient(grant, newRegisteredService("db", v1alpha1.RegisteredServiceStateAvailable, psql))
+			r := ServiceGrantReconciler{Client: cli, Scheme: cli.Scheme()}
+
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: nn})
+			Expect(err).NotTo(HaveOccurred())
+
+			role := rbacv1.Role{}
+			Expect(cli.Get(ctx, types.NamespacedName{Namespace: nn.Namespace, Name: "primaza-servicegrant-shared"}, &role)).To(Succeed())
+			Expect(role.Rules).To(BeEmpty())
+		})
+	})
+
+	Describe("consuming granted services", func() {
+		granted := func(namespace, tenant string) *v1alpha1.ServiceGrantReference {
+			return &v1alpha1.ServiceGrantReference{
+				ObjectMeta: metav1.ObjectMeta{Name: tenant + "." + nn.Name, Namespace: namespace},
+				Spec: v1alpha1.ServiceGrantReferenceSpec{
+					Tenant:             tenant,
+					ServiceGrant:       nn.Name,
+					RegisteredServices: []v1alpha1.GrantedRegisteredService{{Name: "db"}},
+				},
+			}
+		}
+		own := &v1alpha1.RegisteredService{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "team-a", UID: "own-db"},
+			Spec:       v1alpha1.RegisteredServiceSpec{ServiceClassIdentity: []v1alpha1.ServiceClassIdentityItem{psql}},
+		}
+
+		DescribeTable("listing the registered services of a tenant",
+			func(grant *v1alpha1.ServiceGrantReference, expected []types.UID) {
+				cli := newClient(grant, own, newRegisteredService("db", v1alpha1.RegisteredServiceStateAvailable, psql))
+				r := ServiceClaimReconciler{Client: cli, Scheme: cli.Scheme()}
+
+				rsl, err := r.listRegisteredServices(ctx, "team-a")
+				Expect(err).NotTo(HaveOccurred())
+				uids := []types.UID{}
+				for _, rs := range rsl.Items {
+					uids = append(uids, rs.UID)
+				}
+				Expect(uids).To(Equal(expected))
+			},
+			Entry("granted to the tenant", granted("team-a", nn.Namespace), []types.UID{"own-db", "db"}),
+			Entry("granted to other tenants", granted("team-b", nn.Namespace), []types.UID{"own-db"}),
+			Entry("published by the tenant itself", granted("team-a", "team-a"), []types.UID{"own-db"}),
+		)
+
+		It("records the namespace of granted registered services only", func() {
+			sclaim := v1alpha1.ServiceClaim{ObjectMeta: metav1.ObjectMeta{Name: "claim", Namespace: "team-a"}}
+
+			Expect(registeredServiceReference(sclaim, *own).Namespace).To(BeEmpty())
+			ref := registeredServiceReference(sclaim, *newRegisteredService("db", v1alpha1.RegisteredServiceStateAvailable))
+			Expect(ref.Namespace).To(Equal(nn.Namespace))
+			Expect(ref.Name).To(Equal("db"))
+		})
+
+		It("lists the available granted services in the service catalogs", func() {
+			catalog := &v1alpha1.ServiceCatalog{
+				ObjectMeta: metav1.ObjectMeta{Name: "dev", Namespace: "team-a"},
+				Spec: v1alpha1.ServiceCatalogSpec{Services: []v1alpha1.ServiceCatalogService{
+					{Name: "db", ServiceClassIdentity: []v1alpha1.ServiceClassIdentityItem{psql}},
+				}},
+			}
+			grant := granted("team-a", nn.Namespace)
+			cli := newClient(catalog, grant, newRegisteredService("db", v1alpha1.RegisteredServiceStateAvailable, psql))
+			r := ServiceCatalogReconciler{Client: cli, Scheme: cli.Scheme()}
+
+			updated, err := r.updateGrantedServices(ctx, catalog)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(updated).To(BeTrue())
+
+			Expect(cli.Get(ctx, client.ObjectKeyFromObject(catalog), catalog)).To(Succeed())
+			Expect(catalog.Spec.Services).To(HaveLen(2))
+			Expect(catalog.Spec.Services[0].GrantedBy).To(BeEmpty())
+			Expect(catalog.Spec.Services[1].GrantedBy).To(Equal(nn.Namespace))
+			Expect(catalog.Spec.Services[1].ServiceEndpointDefinitionKeys).To(Equal([]string{"host", "password"}))
+			Expect(ServiceInCatalog(*catalog, "db")).To(Equal(0))
+
+			updated, err = r.updateGrantedServices(ctx, catalog)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(updated).To(BeFalse())
+
+			// revoke the grant
+			Expect(cli.Delete(ctx, grant)).To(Succeed())
+			updated, err = r.updateGrantedServices(ctx, catalog)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(updated).To(BeTrue())
+			Expect(catalog.Spec.Services).To(HaveLen(1))
+		})
+	})
+})
@@ -0,0 +1,515 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	primazaiov1alpha1 "github.com/primaza/primaza/api/v1alpha1"
+	"github.com/primaza/primaza/pkg/primaza/clustercontext"
+	"github.com/primaza/primaza/pkg/primaza/constants"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// The permissions of the TenantReconciler are cluster-wide, so they are not
+// declared with RBAC markers, that would grant them to the control planes of
+// all the tenants as well: they are declared in the config/tenants overlay
+// instead.
+
+const (
+	tenantFinalizer = "tenant.primaza.io/finalizer"
+
+	// TenantManagerServiceAccountName is the name of the service account
+	// the control plane of a tenant runs as
+	TenantManagerServiceAccountName = "primaza-controller-manager"
+	// TenantManagerRoleName is the name of the Role, and of the ClusterRole,
+	// granting the permissions the control plane of a tenant requires
+	TenantManagerRoleName = "primaza-manager-role"
+	// TenantManagerClusterRoleBindingName is the name of the
+	// ClusterRoleBinding granting the manager ClusterRole to the control
+	// planes of all the tenants. It is deployed along with the
+	// TenantReconciler's permissions, that only allow to update it.
+	TenantManagerClusterRoleBindingName = "primaza-manager-tenants"
+	// TenantAdminRoleName is the name of the Role granted to the
+	// administrators of a tenant
+	TenantAdminRoleName = "primaza-tenant-admin"
+	// TenantProvisionerRoleName is the name of the ClusterRole, and of the
+	// RoleBindings in the tenants' namespaces, granting the control plane
+	// managing the tenants the permissions to provision them and to read
+	// their cluster context Secrets
+	TenantProvisionerRoleName = "primaza-tenant-provisioner"
+
+	// TenantHealthRefreshInterval is the interval at which the health of
+	// the tenants is refreshed, as the ClusterEnvironments of other
+	// namespaces are not watched
+	TenantHealthRefreshInterval = time.Minute
+)
+
+// TenantReconciler reconciles a Tenant object.
+// It provisions the namespace of the tenant's control plane, along with the
+// permissions of the control plane and of the tenant's administrators, and
+// the tenant's default ServiceCatalogs and ClaimQuotas.
+type TenantReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// APIReader reads the resources of the tenants' namespaces, which are
+	// not cached
+	APIReader client.Reader
+
+	// ControlPlaneNamespace is the namespace of the control plane running
+	// the TenantReconciler: its manager Role is replicated in the tenants'
+	// namespaces
+	ControlPlaneNamespace string
+	// ClusterEnvironment configures the removal of the agents of the
+	// tenants' ClusterEnvironments
+	ClusterEnvironment ClusterEnvironmentReconcilerConfig
+}
+
+func (r *TenantReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+	l.Info("Reconcile Tenant")
+
+	var t primazaiov1alpha1.Tenant
+	if err := r.Get(ctx, req.NamespacedName, &t); err != nil {
+		l.Info("unable to retrieve Tenant", "error", err)
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if t.HasDeletionTimestamp() {
+		if !controllerutil.ContainsFinalizer(&t, tenantFinalizer) {
+			return ctrl.Result{}, nil
+		}
+		if err := r.finalizeTenant(ctx, t); err != nil {
+			l.Error(err, "unable to tear down the Tenant")
+			return ctrl.Result{}, err
+		}
+		controllerutil.RemoveFinalizer(&t, tenantFinalizer)
+		return ctrl.Result{}, r.Update(ctx, &t)
+	}
+
+	if controllerutil.AddFinalizer(&t, tenantFinalizer) {
+		if err := r.Update(ctx, &t); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	c := metav1.Condition{
+		Type:    primazaiov1alpha1.TenantProvisionedCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  constants.TenantProvisionedReason,
+		Message: fmt.Sprintf("namespace %s is provisioned", t.Name),
+	}
+	perr := r.provisionTenant(ctx, t)
+	if errors.Is(perr, errTenantNamespaceNotOwned) {
+		// the namespace is left untouched, and it is not reported as the
+		// tenant's one
+		l.Info("namespace of the Tenant is not owned by the Tenant", "namespace", t.Name)
+		c.Status = metav1.ConditionFalse
+		c.Reason = constants.TenantNamespaceConflictReason
+		c.Message = perr.Error()
+		meta.SetStatusCondition(&t.Status.Conditions, c)
+		t.Status.Namespace = ""
+		t.Status.ClusterEnvironments = nil
+		t.Status.Health = primazaiov1alpha1.HealthOf(nil)
+		return ctrl.Result{}, r.Status().Update(ctx, &t)
+	}
+	if perr != nil {
+		l.Error(perr, "unable to provision the Tenant")
+		c.Status = metav1.ConditionFalse
+		c.Reason = constants.TenantProvisioningFailedReason
+		c.Message = perr.Error()
+	}
+	meta.SetStatusCondition(&t.Status.Conditions, c)
+	t.Status.Namespace = t.Name
+
+	cee, err := r.tenantClusterEnvironments(ctx, t)
+	if err != nil {
+		l.Error(err, "unable to list the ClusterEnvironments of the Tenant")
+		return ctrl.Result{}, errors.Join(perr, err)
+	}
+	t.Status.ClusterEnvironments = cee
+	t.Status.Health = primazaiov1alpha1.HealthOf(cee)
+
+	if err := r.Status().Update(ctx, &t); err != nil {
+		l.Error(err, "unable to update the Tenant status")
+		return ctrl.Result{}, errors.Join(perr, err)
+	}
+	return ctrl.Result{RequeueAfter: TenantHealthRefreshInterval}, perr
+}
+
+// reader returns the client.Reader for the resources of the tenants'
+// namespaces
+func (r *TenantReconciler) reader() client.Reader {
+	if r.APIReader == nil {
+		return r.Client
+	}
+	return r.APIReader
+}
+
+// tenantLabels returns the labels of the resources provisioned for the Tenant
+func tenantLabels(t primazaiov1alpha1.Tenant) map[string]string {
+	return map[string]string{
+		"app":                        "primaza",
+		constants.PrimazaTenantLabel: t.Name,
+	}
+}
+
+// errTenantNamespaceNotOwned is returned when a namespace named after the
+// Tenant already exists, and it has not been created for the Tenant
+var errTenantNamespaceNotOwned = errors.New("namespace is not owned by the tenant")
+
+// provisionTenant creates or updates the resources of the Tenant. An
+// existing namespace is never adopted: it is only provisioned if it has been
+// created for the Tenant.
+func (r *TenantReconciler) provisionTenant(ctx context.Context, t primazaiov1alpha1.Tenant) error {
+	ns := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: t.Name}}
+	switch err := r.reader().Get(ctx, client.ObjectKeyFromObject(&ns), &ns); {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return err
+	case !metav1.IsControlledBy(&ns, &t):
+		return fmt.Errorf("%w: namespace %s already exists", errTenantNamespaceNotOwned, ns.Name)
+	}
+	if err := r.createOrUpdate(ctx, t, &ns, func() error { return nil }); err != nil {
+		return err
+	}
+	if !ns.DeletionTimestamp.IsZero() {
+		return fmt.Errorf("namespace %s is being deleted", ns.Name)
+	}
+
+	// the other resources of the tenant namespace are provisioned with the
+	// permissions of the provisioner ClusterRole
+	if err := r.provisionProvisionerPermissions(ctx, t); err != nil {
+		return err
+	}
+	return errors.Join(
+		r.provisionManagerPermissions(ctx, t),
+		r.provisionAdminPermissions(ctx, t),
+		r.provisionServiceCatalogs(ctx, t),
+		r.provisionClaimQuotas(ctx, t),
+	)
+}
+
+// createOrUpdate creates or updates the given resource of the Tenant, that
+// is labelled and owned by the Tenant. The resources of the tenants'
+// namespaces, and the cluster-scoped ones, are not cached, so the resource
+// is retrieved from the API server.
+func (r *TenantReconciler) createOrUpdate(ctx context.Context, t primazaiov1alpha1.Tenant, obj client.Object, f controllerutil.MutateFn) error {
+	mutate := func() error {
+		labels := obj.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		for k, v := range tenantLabels(t) {
+			labels[k] = v
+		}
+		obj.SetLabels(labels)
+		if err := controllerutil.SetControllerReference(&t, obj, r.Scheme); err != nil {
+			return err
+		}
+		return f()
+	}
+
+	if err := r.reader().Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		if err := mutate(); err != nil {
+			return err
+		}
+		return r.Create(ctx, obj)
+	}
+
+	existing := obj.DeepCopyObject()
+	if err := mutate(); err != nil {
+		return err
+	}
+	if equality.Semantic.DeepEqual(existing, obj) {
+		return nil
+	}
+	return r.Update(ctx, obj)
+}
+
+// provisionManagerPermissions grants the control plane of the tenant the
+// permissions of the manager Role of Primaza's control plane, in the tenant
+// namespace, and the cluster-wide ones of the manager ClusterRole
+func (r *TenantReconciler) provisionManagerPermissions(ctx context.Context, t primazaiov1alpha1.Tenant) error {
+	var mr rbacv1.Role
+	k := types.NamespacedName{Namespace: r.ControlPlaneNamespace, Name: TenantManagerRoleName}
+	if err := r.reader().Get(ctx, k, &mr); err != nil {
+		return fmt.Errorf("unable to retrieve the manager role %s: %w", k, err)
+	}
+
+	sa := corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: TenantManagerServiceAccountName, Namespace: t.Name}}
+	if err := r.createOrUpdate(ctx, t, &sa, func() error { return nil }); err != nil {
+		return err
+	}
+	subjects := []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: sa.Name, Namespace: sa.Namespace}}
+
+	role := rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Name: TenantManagerRoleName, Namespace: t.Name}}
+	if err := r.createOrUpdate(ctx, t, &role, func() error {
+		role.Rules = mr.Rules
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	rb := rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: TenantManagerRoleName, Namespace: t.Name}}
+	if err := r.createOrUpdate(ctx, t, &rb, func() error {
+		rb.RoleRef = rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: role.Name}
+		rb.Subjects = subjects
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return r.updateManagerClusterRoleBinding(ctx, t, true)
+}
+
+// updateManagerClusterRoleBinding adds the service account of the tenant's
+// control plane to the subjects of the ClusterRoleBinding granting the
+// manager ClusterRole, or removes it
+func (r *TenantReconciler) updateManagerClusterRoleBinding(ctx context.Context, t primazaiov1alpha1.Tenant, granted bool) error {
+	var crb rbacv1.ClusterRoleBinding
+	k := types.NamespacedName{Name: TenantManagerClusterRoleBindingName}
+	if err := r.reader().Get(ctx, k, &crb); err != nil {
+		return fmt.Errorf("unable to retrieve the manager cluster role binding %s: %w", k.Name, err)
+	}
+
+	sa := rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: TenantManagerServiceAccountName, Namespace: t.Name}
+	subjects := slices.DeleteFunc(slices.Clone(crb.Subjects), func(s rbacv1.Subject) bool { return s == sa })
+	if granted {
+		subjects = append(subjects, sa)
+		slices.SortFunc(subjects, func(a, b rbacv1.Subject) int { return strings.Compare(a.Namespace, b.Namespace) })
+	}
+	if equality.Semantic.DeepEqual(subjects, crb.Subjects) {
+		return nil
+	}
+	crb.Subjects = subjects
+	return r.Update(ctx, &crb)
+}
+
+// provisionProvisionerPermissions grants the control plane running the
+// TenantReconciler the permissions to provision the tenant namespace, and to
+// read its Secrets, so that it can remove the agents of the tenant's
+// ClusterEnvironments
+func (r *TenantReconciler) provisionProvisionerPermissions(ctx context.Context, t primazaiov1alpha1.Tenant) error {
+	rb := rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: TenantProvisionerRoleName, Namespace: t.Name}}
+	return r.createOrUpdate(ctx, t, &rb, func() error {
+		rb.RoleRef = rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: TenantProvisionerRoleName}
+		rb.Subjects = []rbacv1.Subject{{
+			Kind:      rbacv1.ServiceAccountKind,
+			Name:      TenantManagerServiceAccountName,
+			Namespace: r.ControlPlaneNamespace,
+		}}
+		return nil
+	})
+}
+
+// tenantAdminRules returns the rules of the Role granted to the
+// administrators of a tenant: they manage Primaza's resources and the
+// Secrets ClusterEnvironments and RegisteredServices refer to
+func tenantAdminRules() []rbacv1.PolicyRule {
+	return []rbacv1.PolicyRule{
+		{
+			APIGroups: []string{primazaiov1alpha1.GroupVersion.Group},
+			Resources: []string{rbacv1.ResourceAll},
+			Verbs:     []string{rbacv1.VerbAll},
+		},
+		{
+			APIGroups: []string{""},
+			Resources: []string{"secrets"},
+			Verbs:     []string{"get", "list", "watch", "create", "update", "patch", "delete"},
+		},
+	}
+}
+
+// provisionAdminPermissions grants the tenant-admin Role to the Tenant's
+// admins
+func (r *TenantReconciler) provisionAdminPermissions(ctx context.Context, t primazaiov1alpha1.Tenant) error {
+	role := rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Name: TenantAdminRoleName, Namespace: t.Name}}
+	if err := r.createOrUpdate(ctx, t, &role, func() error {
+		role.Rules = tenantAdminRules()
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	rb := rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: TenantAdminRoleName, Namespace: t.Name}}
+	return r.createOrUpdate(ctx, t, &rb, func() error {
+		rb.RoleRef = rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: role.Name}
+		rb.Subjects = t.Spec.Admins
+		return nil
+	})
+}
+
+// provisionServiceCatalogs creates the ServiceCatalogs of the Tenant's
+// environments. Existing ServiceCatalogs are left untouched, as their
+// services are managed by the tenant's control plane.
+func (r *TenantReconciler) provisionServiceCatalogs(ctx context.Context, t primazaiov1alpha1.Tenant) error {
+	errs := []error{}
+	for _, e := range t.Spec.Environments {
+		sc := primazaiov1alpha1.ServiceCatalog{
+			ObjectMeta: metav1.ObjectMeta{Name: e, Namespace: t.Name, Labels: tenantLabels(t)},
+		}
+		if err := r.Create(ctx, &sc); err != nil && !apierrors.IsAlreadyExists(err) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// provisionClaimQuotas creates or updates the ClaimQuotas of the Tenant, and
+// deletes the ones removed from the Tenant
+func (r *TenantReconciler) provisionClaimQuotas(ctx context.Context, t primazaiov1alpha1.Tenant) error {
+	errs := []error{}
+	for _, tq := range t.Spec.ClaimQuotas {
+		q := primazaiov1alpha1.ClaimQuota{ObjectMeta: metav1.ObjectMeta{Name: tq.Name, Namespace: t.Name}}
+		if err := r.createOrUpdate(ctx, t, &q, func() error {
+			q.Spec = tq.ClaimQuotaSpec
+			return nil
+		}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	var cql primazaiov1alpha1.ClaimQuotaList
+	if err := r.reader().List(ctx, &cql, client.InNamespace(t.Name), client.MatchingLabels(tenantLabels(t))); err != nil {
+		return errors.Join(append(errs, err)...)
+	}
+	for i, q := range cql.Items {
+		if !slices.ContainsFunc(t.Spec.ClaimQuotas, func(tq primazaiov1alpha1.TenantClaimQuota) bool { return tq.Name == q.Name }) {
+			if err := r.Delete(ctx, &cql.Items[i]); client.IgnoreNotFound(err) != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// tenantClusterEnvironments returns the state of the ClusterEnvironments of
+// the Tenant
+func (r *TenantReconciler) tenantClusterEnvironments(ctx context.Context, t primazaiov1alpha1.Tenant) ([]primazaiov1alpha1.TenantClusterEnvironment, error) {
+	var cel primazaiov1alpha1.ClusterEnvironmentList
+	if err := r.reader().List(ctx, &cel, client.InNamespace(t.Name)); err != nil {
+		return nil, err
+	}
+
+	cee := make([]primazaiov1alpha1.TenantClusterEnvironment, 0, len(cel.Items))
+	for _, ce := range cel.Items {
+		cee = append(cee, primazaiov1alpha1.TenantClusterEnvironment{Name: ce.Name, State: ce.Status.State})
+	}
+	slices.SortFunc(cee, func(a, b primazaiov1alpha1.TenantClusterEnvironment) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return cee, nil
+}
+
+// finalizeTenant tears the Tenant down. The agents of its ClusterEnvironments
+// are removed, as the tenant's control plane may not be running anymore to
+// finalize them, and the tenant namespace is deleted. A namespace that has
+// not been created for the Tenant is left untouched.
+func (r *TenantReconciler) finalizeTenant(ctx context.Context, t primazaiov1alpha1.Tenant) error {
+	l := log.FromContext(ctx)
+
+	if err := r.updateManagerClusterRoleBinding(ctx, t, false); client.IgnoreNotFound(err) != nil {
+		return err
+	}
+
+	ns := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: t.Name}}
+	if err := r.reader().Get(ctx, client.ObjectKeyFromObject(&ns), &ns); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !metav1.IsControlledBy(&ns, &t) {
+		l.Info("namespace of the Tenant is not owned by the Tenant, leaving it untouched", "namespace", ns.Name)
+		return nil
+	}
+
+	var cel primazaiov1alpha1.ClusterEnvironmentList
+	if err := r.reader().List(ctx, &cel, client.InNamespace(t.Name)); err != nil {
+		return err
+	}
+
+	errs := []error{}
+	for i := range cel.Items {
+		ce := &cel.Items[i]
+		if err := r.removeAgents(ctx, ce); err != nil {
+			l.Error(err, "unable to remove the agents of the ClusterEnvironment", "cluster-environment", ce.Name)
+			errs = append(errs, err)
+			continue
+		}
+		if controllerutil.RemoveFinalizer(ce, clusterEnvironmentFinalizer) {
+			if err := r.Update(ctx, ce); client.IgnoreNotFound(err) != nil {
+				errs = append(errs, err)
+				continue
+			}
+		}
+		if err := r.Delete(ctx, ce); client.IgnoreNotFound(err) != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	return client.IgnoreNotFound(r.Delete(ctx, &ns))
+}
+
+// removeAgents removes the agents of the ClusterEnvironment. Agents can not
+// be removed if the cluster context secret has already been deleted.
+func (r *TenantReconciler) removeAgents(ctx context.Context, ce *primazaiov1alpha1.ClusterEnvironment) error {
+	var s corev1.Secret
+	k := types.NamespacedName{Namespace: ce.Namespace, Name: ce.Spec.ClusterContextSecret}
+	if err := r.reader().Get(ctx, k, &s); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.FromContext(ctx).Info("cluster context secret not found, agents can not be removed", "secret", k)
+			return nil
+		}
+		return err
+	}
+
+	kcfg, err := clustercontext.ExtractClusterRESTConfig(&s)
+	if err != nil {
+		return err
+	}
+	return removeClusterEnvironmentAgents(ctx, kcfg, ce, r.ClusterEnvironment)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *TenantReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&primazaiov1alpha1.Tenant{}).
+		Owns(&corev1.Namespace{}).
+		Complete(r)
+}
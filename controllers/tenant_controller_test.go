@@ -0,0 +1,285 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/primaza/primaza/api/v1alpha1"
+	"github.com/primaza/primaza/pkg/primaza/constants"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+var _ = Describe("Tenant", func() {
+	ctx := context.Background()
+	nn := types.NamespacedName{Name: "team-a"}
+	controlPlane := "primaza-system"
+
+	managerRole := &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{Name: TenantManagerRoleName, Namespace: controlPlane},
+		Rules: []rbacv1.PolicyRule{{
+			APIGroups: []string{"primaza.io"},
+			Resources: []string{"serviceclaims"},
+			Verbs:     []string{"get", "list", "watch"},
+		}},
+	}
+	newManagerBinding := func(subjects ...rbacv1.Subject) *rbacv1.ClusterRoleBinding {
+		return &rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: TenantManagerClusterRoleBindingName},
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: TenantManagerRoleName},
+			Subjects:   subjects,
+		}
+	}
+	tenantManager := rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: TenantManagerServiceAccountName, Namespace: nn.Name}
+	admins := []rbacv1.Subject{{APIGroup: rbacv1.GroupName, Kind: rbacv1.GroupKind, Name: "team-a"}}
+	newTenant := func() *v1alpha1.Tenant {
+		return &v1alpha1.Tenant{
+			ObjectMeta: metav1.ObjectMeta{Name: nn.Name},
+			Spec: v1alpha1.TenantSpec{
+				Environments: []string{"dev", "prod"},
+				ClaimQuotas: []v1alpha1.TenantClaimQuota{
+					{Name: "prod", ClaimQuotaSpec: v1alpha1.ClaimQuotaSpec{Hard: 5, Environments: []string{"prod"}}},
+				},
+				Admins: admins,
+			},
+		}
+	}
+	newClusterEnvironment := func(name string, state v1alpha1.ClusterEnvironmentState) *v1alpha1.ClusterEnvironment {
+		return &v1alpha1.ClusterEnvironment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: nn.Name},
+			Spec: v1alpha1.ClusterEnvironmentSpec{
+				EnvironmentName:      "dev",
+				ClusterContextSecret: name + "-kubeconfig",
+			},
+			Status: v1alpha1.ClusterEnvironmentStatus{State: state},
+		}
+	}
+	newClient := func(objs ...client.Object) client.WithWatch {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		return fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(objs...).
+			WithStatusSubresource(&v1alpha1.Tenant{}).
+			Build()
+	}
+	// the cache of the control plane only holds the Tenants and the
+	// resources of its own namespace: the other resources are read with
+	// the APIReader
+	newReconciler := func(cli client.WithWatch) TenantReconciler {
+		cached := interceptor.NewClient(cli, interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				if _, ok := obj.(*v1alpha1.Tenant); !ok && key.Namespace != controlPlane {
+					return apierrors.NewNotFound(schema.GroupResource{}, key.Name)
+				}
+				return c.Get(ctx, key, obj, opts...)
+			},
+		})
+		return TenantReconciler{Client: cached, APIReader: cli, Scheme: cli.Scheme(), ControlPlaneNamespace: controlPlane}
+	}
+
+	Describe("provisioning", func() {
+		It("provisions the tenant namespace", func() {
+			tenant := newTenant()
+			cli := newClient(tenant, managerRole, newManagerBinding(),
+				newClusterEnvironment("worker", v1alpha1.ClusterEnvironmentStateOnline))
+			r := newReconciler(cli)
+
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: nn})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(cli.Get(ctx, nn, tenant)).To(Succeed())
+			Expect(tenant.Finalizers).To(ContainElement(tenantFinalizer))
+			Expect(tenant.Status.Namespace).To(Equal(nn.Name))
+			Expect(tenant.Status.Health).To(Equal(v1alpha1.TenantHealthHealthy))
+			Expect(tenant.Status.ClusterEnvironments).To(Equal([]v1alpha1.TenantClusterEnvironment{
+				{Name: "worker", State: v1alpha1.ClusterEnvironmentStateOnline},
+			}))
+			Expect(meta.IsStatusConditionTrue(tenant.Status.Conditions, v1alpha1.TenantProvisionedCondition)).To(BeTrue())
+
+			ns := corev1.Namespace{}
+			Expect(cli.Get(ctx, nn, &ns)).To(Succeed())
+			Expect(ns.Labels).To(HaveKeyWithValue(constants.PrimazaTenantLabel, nn.Name))
+
+			sa := corev1.ServiceAccount{}
+			Expect(cli.Get(ctx, types.NamespacedName{Namespace: nn.Name, Name: TenantManagerServiceAccountName}, &sa)).To(Succeed())
+			role := rbacv1.Role{}
+			Expect(cli.Get(ctx, types.NamespacedName{Namespace: nn.Name, Name: TenantManagerRoleName}, &role)).To(Succeed())
+			Expect(role.Rules).To(Equal(managerRole.Rules))
+			crb := rbacv1.ClusterRoleBinding{}
+			Expect(cli.Get(ctx, types.NamespacedName{Name: TenantManagerClusterRoleBindingName}, &crb)).To(Succeed())
+			Expect(crb.Subjects).To(ConsistOf(rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: sa.Name, Namespace: nn.Name}))
+
+			rb := rbacv1.RoleBinding{}
+			Expect(cli.Get(ctx, types.NamespacedName{Namespace: nn.Name, Name: TenantProvisionerRoleName}, &rb)).To(Succeed())
+			Expect(rb.RoleRef.Kind).To(Equal("ClusterRole"))
+			Expect(rb.Subjects).To(ConsistOf(rbacv1.Subject{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      TenantManagerServiceAccountName,
+				Namespace: controlPlane,
+			}))
+
+			rb = rbacv1.RoleBinding{}
+			Expect(cli.Get(ctx, types.NamespacedName{Namespace: nn.Name, Name: TenantAdminRoleName}, &rb)).To(Succeed())
+			Expect(rb.Subjects).To(Equal(admins))
+
+			for _, e := range []string{"dev", "prod"} {
+				sc := v1alpha1.ServiceCatalog{}
+				Expect(cli.Get(ctx, types.NamespacedName{Namespace: nn.Name, Name: e}, &sc)).To(Succeed())
+			}
+			q := v1alpha1.ClaimQuota{}
+			Expect(cli.Get(ctx, types.NamespacedName{Namespace: nn.Name, Name: "prod"}, &q)).To(Succeed())
+			Expect(q.Spec.Hard).To(Equal(5))
+		})
+
+		It("reconciles the same tenant twice", func() {
+			tenant := newTenant()
+			other := rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: TenantManagerServiceAccountName, Namespace: "team-b"}
+			cli := newClient(tenant, managerRole, newManagerBinding(other))
+			r := newReconciler(cli)
+
+			for i := 0; i < 2; i++ {
+				_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: nn})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			Expect(cli.Get(ctx, nn, tenant)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(tenant.Status.Conditions, v1alpha1.TenantProvisionedCondition)).To(BeTrue())
+			crb := rbacv1.ClusterRoleBinding{}
+			Expect(cli.Get(ctx, types.NamespacedName{Name: TenantManagerClusterRoleBindingName}, &crb)).To(Succeed())
+			Expect(crb.Subjects).To(Equal([]rbacv1.Subject{tenantManager, other}))
+		})
+
+		It("does not adopt an existing namespace", func() {
+			tenant := newTenant()
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: nn.Name}}
+			cli := newClient(tenant, managerRole, newManagerBinding(), ns)
+			r := newReconciler(cli)
+
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: nn})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(cli.Get(ctx, nn, tenant)).To(Succeed())
+			Expect(tenant.Status.Namespace).To(BeEmpty())
+			c := meta.FindStatusCondition(tenant.Status.Conditions, v1alpha1.TenantProvisionedCondition)
+			Expect(c).NotTo(BeNil())
+			Expect(c.Status).To(Equal(metav1.ConditionFalse))
+			Expect(c.Reason).To(Equal(constants.TenantNamespaceConflictReason))
+			Expect(cli.Get(ctx, nn, ns)).To(Succeed())
+			Expect(ns.OwnerReferences).To(BeEmpty())
+			sa := corev1.ServiceAccount{}
+			err = cli.Get(ctx, types.NamespacedName{Namespace: nn.Name, Name: TenantManagerServiceAccountName}, &sa)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+			// the namespace is not deleted along with the tenant
+			Expect(cli.Delete(ctx, tenant)).To(Succeed())
+			_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: nn})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(apierrors.IsNotFound(cli.Get(ctx, nn, tenant))).To(BeTrue())
+			Expect(cli.Get(ctx, nn, ns)).To(Succeed())
+		})
+
+		It("deletes the claim quotas removed from the tenant", func() {
+			tenant := newTenant()
+			cli := newClient(tenant, managerRole, newManagerBinding())
+			r := newReconciler(cli)
+
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: nn})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(cli.Get(ctx, nn, tenant)).To(Succeed())
+			tenant.Spec.ClaimQuotas = nil
+			Expect(cli.Update(ctx, tenant)).To(Succeed())
+			_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: nn})
+			Expect(err).NotTo(HaveOccurred())
+
+			q := v1alpha1.ClaimQuota{}
+			err = cli.Get(ctx, types.NamespacedName{Namespace: nn.Name, Name: "prod"}, &q)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("reports the provisioning failures", func() {
+			tenant := newTenant()
+			cli := newClient(tenant)
+			r := newReconciler(cli)
+
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: nn})
+			Expect(err).To(HaveOccurred())
+
+			Expect(cli.Get(ctx, nn, tenant)).To(Succeed())
+			c := meta.FindStatusCondition(tenant.Status.Conditions, v1alpha1.TenantProvisionedCondition)
+			Expect(c).NotTo(BeNil())
+			Expect(c.Status).To(Equal(metav1.ConditionFalse))
+			Expect(c.Reason).To(Equal(constants.TenantProvisioningFailedReason))
+		})
+	})
+
+	DescribeTable("aggregating the health",
+		func(expected v1alpha1.TenantHealth, states ...v1alpha1.ClusterEnvironmentState) {
+			cee := []v1alpha1.TenantClusterEnvironment{}
+			for _, s := range states {
+				cee = append(cee, v1alpha1.TenantClusterEnvironment{Name: "ce", State: s})
+			}
+			Expect(v1alpha1.HealthOf(cee)).To(Equal(expected))
+		},
+		Entry("no cluster environment", v1alpha1.TenantHealthUnknown),
+		Entry("all online", v1alpha1.TenantHealthHealthy, v1alpha1.ClusterEnvironmentStateOnline, v1alpha1.ClusterEnvironmentStateOnline),
+		Entry("some online", v1alpha1.TenantHealthDegraded, v1alpha1.ClusterEnvironmentStateOnline, v1alpha1.ClusterEnvironmentStateOffline),
+		Entry("partial", v1alpha1.TenantHealthDegraded, v1alpha1.ClusterEnvironmentStatePartial),
+		Entry("all offline", v1alpha1.TenantHealthUnhealthy, v1alpha1.ClusterEnvironmentStateOffline, v1alpha1.ClusterEnvironmentStateOffline),
+	)
+
+	It("tears the tenant down", func() {
+		now := metav1.Now()
+		tenant := newTenant()
+		tenant.Finalizers = []string{tenantFinalizer}
+		tenant.DeletionTimestamp = &now
+		ce := newClusterEnvironment("worker", v1alpha1.ClusterEnvironmentStateOnline)
+		ce.Finalizers = []string{clusterEnvironmentFinalizer}
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:            nn.Name,
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(tenant, v1alpha1.GroupVersion.WithKind("Tenant"))},
+		}}
+		crb := newManagerBinding(tenantManager)
+		cli := newClient(tenant, ce, ns, crb)
+		r := newReconciler(cli)
+
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: nn})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(apierrors.IsNotFound(cli.Get(ctx, client.ObjectKeyFromObject(ce), ce))).To(BeTrue())
+		Expect(apierrors.IsNotFound(cli.Get(ctx, nn, ns))).To(BeTrue())
+		Expect(cli.Get(ctx, client.ObjectKeyFromObject(crb), crb)).To(Succeed())
+		Expect(crb.Subjects).To(BeEmpty())
+		Expect(apierrors.IsNotFound(cli.Get(ctx, nn, tenant))).To(BeTrue())
+	})
+})
@@ -33,6 +33,22 @@ var AppPermissionList = []Permission{
 		Name:          "primaza:app:manager",
 		Verbs:         []string{"get", "list", "watch", "update", "patch"},
 	},
+	{
+		APIGroups:     []string{"primaza.io"},
+		Resources:     []string{"workloadresourcemappings"},
+		ResourceNames: []string{},
+		Namespace:     "system",
+		Name:          "primaza:app:manager",
+		Verbs:         []string{"get", "list", "watch"},
+	},
+	{
+		APIGroups:     []string{"batch"},
+		Resources:     []string{"cronjobs"},
+		ResourceNames: []string{},
+		Namespace:     "system",
+		Name:          "primaza:app:manager",
+		Verbs:         []string{"get", "list", "watch", "update", "patch"},
+	},
 	{
 		APIGroups:     []string{"apps"},
 		Resources:     []string{"deployments", "deployments/finalizers"},
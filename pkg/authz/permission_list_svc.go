@@ -3,7 +3,7 @@ package authz
 var SvcPermissionList = []Permission{
 	{
 		APIGroups:     []string{"primaza.io"},
-		Resources:     []string{"serviceclasses", "registeredservices"},
+		Resources:     []string{"serviceclasses", "registeredservices", "serviceprovisionings"},
 		ResourceNames: []string{},
 		Namespace:     "system",
 		Name:          "primaza:svc:manager",
@@ -11,7 +11,7 @@ var SvcPermissionList = []Permission{
 	},
 	{
 		APIGroups:     []string{"primaza.io"},
-		Resources:     []string{"serviceclasses/status"},
+		Resources:     []string{"serviceclasses/status", "serviceprovisionings/status"},
 		ResourceNames: []string{},
 		Namespace:     "system",
 		Name:          "primaza:svc:manager",
@@ -25,6 +25,14 @@ var SvcPermissionList = []Permission{
 		Name:          "primaza:svc:manager",
 		Verbs:         []string{"create", "delete", "update", "get", "list", "watch"},
 	},
+	{
+		APIGroups:     []string{""},
+		Resources:     []string{"configmaps", "services"},
+		ResourceNames: []string{},
+		Namespace:     "system",
+		Name:          "primaza:svc:manager",
+		Verbs:         []string{"get", "list", "watch"},
+	},
 	{
 		APIGroups:     []string{"apps"},
 		Resources:     []string{"deployments"},
@@ -14,5 +14,9 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-// Package envtag contains logic to match environments against environment constraints
+// Package envtag contains logic to match environments against environment constraints.
+//
+// Each constraint is an expression combining glob patterns, regular
+// expressions delimited by `/` and `label:` patterns with `&&`, `||`, `!`
+// and parentheses. See Parse for the details.
 package envtag
@@ -28,15 +28,23 @@ const (
 	forbidden matchResult = 2
 )
 
-// Match matches an environment against a list of constraints
+// Match matches an environment name against a list of constraints
 func Match(environment string, constraints []string) bool {
+	return MatchEnvironment(Environment{Name: environment}, constraints)
+}
+
+// MatchEnvironment matches an environment against a list of constraints.
+// Negated constraints forbid the environment when their expression matches,
+// and are satisfied otherwise. The environment matches when at least one
+// constraint is satisfied and none forbids it.
+func MatchEnvironment(env Environment, constraints []string) bool {
 	if len(constraints) == 0 {
 		return true
 	}
 
 	v := false
 	for _, m := range constraints {
-		switch match(environment, m) {
+		switch match(env, m) {
 		case matched:
 			v = true
 		case forbidden:
@@ -46,16 +54,35 @@ func Match(environment string, constraints []string) bool {
 	return v
 }
 
-func match(environment, constraint string) matchResult {
-	if strings.HasPrefix(constraint, NegativeConstraintSymbol) {
-		pc := strings.TrimPrefix(constraint, NegativeConstraintSymbol)
-		if environment == pc {
+func match(env Environment, constraint string) matchResult {
+	e, err := Parse(constraint)
+	if err != nil {
+		// constraints rejected by the parser are compared as plain names,
+		// as they used to be before the introduction of expressions
+		return matchName(env.Name, constraint)
+	}
+
+	if n, ok := e.(notExpression); ok {
+		if n.e.Match(env) {
 			return forbidden
 		}
 
 		// environment is not excluded by constraint
 		return matched
+	}
 
+	if e.Match(env) {
+		return matched
+	}
+	return unmatched
+}
+
+func matchName(environment, constraint string) matchResult {
+	if pc, ok := strings.CutPrefix(constraint, NegativeConstraintSymbol); ok {
+		if environment == pc {
+			return forbidden
+		}
+		return matched
 	}
 
 	if environment == constraint {
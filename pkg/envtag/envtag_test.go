@@ -50,3 +50,61 @@ func Test_MatchEmptyContainsts(t *testing.T) {
 		}
 	}
 }
+
+func Test_MatchExpressions(t *testing.T) {
+	type test struct {
+		environment envtag.Environment
+		constraints []string
+		want        bool
+	}
+
+	prodEU := envtag.Environment{Name: "prod-eu", Labels: []string{"region=eu", "gpu"}}
+	prodUS := envtag.Environment{Name: "prod-us", Labels: []string{"region=us"}}
+	stage := envtag.Environment{Name: "stage"}
+
+	tt := []test{
+		{environment: prodEU, constraints: []string{"prod-*"}, want: true},
+		{environment: stage, constraints: []string{"prod-*"}, want: false},
+		{environment: prodEU, constraints: []string{"prod-*", "!prod-eu"}, want: false},
+		{environment: prodEU, constraints: []string{"/prod-(eu|us)/"}, want: true},
+		{environment: prodEU, constraints: []string{"/prod/"}, want: false},
+		{environment: prodEU, constraints: []string{"label:region=eu"}, want: true},
+		{environment: prodUS, constraints: []string{"label:region=eu"}, want: false},
+		{environment: prodUS, constraints: []string{"label:region=*"}, want: true},
+		{environment: prodEU, constraints: []string{"!label:gpu"}, want: false},
+		{environment: prodUS, constraints: []string{"!label:gpu"}, want: true},
+		{environment: prodEU, constraints: []string{"prod-* && label:gpu"}, want: true},
+		{environment: prodUS, constraints: []string{"prod-* && label:gpu"}, want: false},
+		{environment: stage, constraints: []string{"stage || prod-* && label:gpu"}, want: true},
+		{environment: prodUS, constraints: []string{"(stage || prod-*) && !prod-us"}, want: false},
+		{environment: prodEU, constraints: []string{"(stage || prod-*) && !prod-us"}, want: true},
+		{environment: stage, constraints: []string{"!(prod-* || label:gpu)"}, want: true},
+		{environment: envtag.Environment{Name: "a(b"}, constraints: []string{"a(b"}, want: true},
+	}
+
+	for _, te := range tt {
+		if got := envtag.MatchEnvironment(te.environment, te.constraints); got != te.want {
+			t.Errorf("%v on %v: expected %v, got %v", te.constraints, te.environment, te.want, got)
+		}
+	}
+}
+
+func Test_Parse(t *testing.T) {
+	valid := []string{"env", "!env", "dev-*", "/dev|stage/", "label:gpu", "a && (b || !c)", "!!a"}
+	for _, c := range valid {
+		if _, err := envtag.Parse(c); err != nil {
+			t.Errorf("%q: unexpected error %v", c, err)
+		}
+	}
+
+	invalid := []string{"", "!", "a &&", "|| a", "(a", "a)", "/a", "/(a/", "[a", "label:", "a b"}
+	for _, c := range invalid {
+		if _, err := envtag.Parse(c); err == nil {
+			t.Errorf("%q: expected error", c)
+		}
+	}
+
+	if err := envtag.Validate([]string{"a", "(b"}); err == nil {
+		t.Errorf("expected error validating invalid constraints")
+	}
+}
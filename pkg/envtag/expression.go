@@ -0,0 +1,242 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envtag
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// LabelPrefix is the prefix of the terms matching the environment's labels
+const LabelPrefix = "label:"
+
+// Environment is what constraints are matched against
+type Environment struct {
+	// Name of the environment
+	Name string
+	// Labels of the ClusterEnvironment, if any
+	Labels []string
+}
+
+// Expression is a parsed environment constraint
+type Expression interface {
+	// Match checks whether the environment satisfies the expression
+	Match(env Environment) bool
+	String() string
+}
+
+type notExpression struct{ e Expression }
+
+func (n notExpression) Match(env Environment) bool { return !n.e.Match(env) }
+func (n notExpression) String() string             { return NegativeConstraintSymbol + n.e.String() }
+
+type andExpression struct{ l, r Expression }
+
+func (a andExpression) Match(env Environment) bool { return a.l.Match(env) && a.r.Match(env) }
+func (a andExpression) String() string             { return fmt.Sprintf("(%s && %s)", a.l, a.r) }
+
+type orExpression struct{ l, r Expression }
+
+func (o orExpression) Match(env Environment) bool { return o.l.Match(env) || o.r.Match(env) }
+func (o orExpression) String() string             { return fmt.Sprintf("(%s || %s)", o.l, o.r) }
+
+// globExpression matches the environment's name against a glob pattern, as
+// defined by path.Match. Names without wildcards are matched exactly.
+type globExpression struct{ pattern string }
+
+func (g globExpression) Match(env Environment) bool {
+	ok, _ := path.Match(g.pattern, env.Name)
+	return ok
+}
+func (g globExpression) String() string { return g.pattern }
+
+// regexExpression matches the whole environment's name against a regular
+// expression
+type regexExpression struct{ re *regexp.Regexp }
+
+func (r regexExpression) Match(env Environment) bool { return r.re.MatchString(env.Name) }
+func (r regexExpression) String() string {
+	return "/" + strings.TrimSuffix(strings.TrimPrefix(r.re.String(), "^(?:"), ")$") + "/"
+}
+
+// labelExpression matches the environment's labels against a glob pattern
+type labelExpression struct{ pattern string }
+
+func (l labelExpression) Match(env Environment) bool {
+	for _, label := range env.Labels {
+		if ok, _ := path.Match(l.pattern, label); ok {
+			return true
+		}
+	}
+	return false
+}
+func (l labelExpression) String() string { return LabelPrefix + l.pattern }
+
+// Parse parses an environment constraint.
+//
+// A constraint is made of terms combined with `&&`, `||`, `!` and
+// parentheses. `!` binds tighter than `&&`, which binds tighter than `||`.
+// Terms can be:
+//   - glob patterns matching the environment's name, e.g. `dev-*`;
+//   - regular expressions, delimited by `/`, matching the whole environment's
+//     name, e.g. `/stage|prod-[0-9]+/`;
+//   - glob patterns prefixed by `label:`, matching any of the environment's
+//     labels, e.g. `label:region=eu-*`.
+func Parse(constraint string) (Expression, error) {
+	p := parser{input: constraint}
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.skipSpaces(); p.pos < len(p.input) {
+		return nil, p.errorf("unexpected %q", p.input[p.pos:])
+	}
+	return e, nil
+}
+
+// Validate parses all the constraints and returns the error of the first
+// invalid one, if any
+func Validate(constraints []string) error {
+	for _, c := range constraints {
+		if _, err := Parse(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type parser struct {
+	input string
+	pos   int
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("invalid constraint %q at position %d: %s", p.input, p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) skipSpaces() {
+	for p.pos < len(p.input) && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+}
+
+// consume skips the token if the input continues with it
+func (p *parser) consume(token string) bool {
+	p.skipSpaces()
+	if strings.HasPrefix(p.input[p.pos:], token) {
+		p.pos += len(token)
+		return true
+	}
+	return false
+}
+
+func (p *parser) parseOr() (Expression, error) {
+	l, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.consume("||") {
+		r, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l = orExpression{l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *parser) parseAnd() (Expression, error) {
+	l, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.consume("&&") {
+		r, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l = andExpression{l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *parser) parseUnary() (Expression, error) {
+	if p.consume(NegativeConstraintSymbol) {
+		e, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpression{e: e}, nil
+	}
+
+	if p.consume("(") {
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.consume(")") {
+			return nil, p.errorf("missing closing parenthesis")
+		}
+		return e, nil
+	}
+
+	return p.parseTerm()
+}
+
+func (p *parser) parseTerm() (Expression, error) {
+	p.skipSpaces()
+	if p.pos >= len(p.input) {
+		return nil, p.errorf("missing term")
+	}
+
+	if p.input[p.pos] == '/' {
+		end := strings.IndexByte(p.input[p.pos+1:], '/')
+		if end < 0 {
+			return nil, p.errorf("missing closing '/' of regular expression")
+		}
+		expr := p.input[p.pos+1 : p.pos+1+end]
+		re, err := regexp.Compile("^(?:" + expr + ")$")
+		if err != nil {
+			return nil, p.errorf("invalid regular expression: %s", err)
+		}
+		p.pos += end + 2
+		return regexExpression{re: re}, nil
+	}
+
+	start := p.pos
+	for p.pos < len(p.input) && !unicode.IsSpace(rune(p.input[p.pos])) && !strings.ContainsRune("()&|", rune(p.input[p.pos])) {
+		p.pos++
+	}
+	term := p.input[start:p.pos]
+	if term == "" {
+		return nil, p.errorf("missing term")
+	}
+
+	if pattern, ok := strings.CutPrefix(term, LabelPrefix); ok {
+		if _, err := path.Match(pattern, ""); err != nil || pattern == "" {
+			return nil, p.errorf("invalid label pattern %q", pattern)
+		}
+		return labelExpression{pattern: pattern}, nil
+	}
+	if _, err := path.Match(term, ""); err != nil {
+		return nil, p.errorf("invalid pattern %q", term)
+	}
+	return globExpression{pattern: term}, nil
+}
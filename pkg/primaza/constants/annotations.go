@@ -26,4 +26,35 @@ const (
 	ServiceNameAnnotation       = "primaza.io/service-name"
 	ServiceNamespaceAnnotation  = "primaza.io/service-namespace"
 	ServiceUIDAnnotation        = "primaza.io/service-uid"
+	// RankAnnotation defines the rank of a RegisteredService, used by
+	// ServiceClaims with the Ranked selection strategy.
+	// RegisteredServices with a higher rank are preferred.
+	RankAnnotation = "primaza.io/rank"
+
+	// Provisioned resources Annotations
+	// ServiceProvisioningAnnotation is set on the resources created by the
+	// Service Agent on behalf of a ServiceProvisioning, to the
+	// ServiceProvisioning's name
+	ServiceProvisioningAnnotation = "primaza.io/service-provisioning"
+	// ServiceProvisioningClaimIDAnnotation is set on the resources created by
+	// the Service Agent on behalf of a ServiceProvisioning, to the ID of the
+	// ServiceClaim the service is provisioned for. The RegisteredService
+	// discovered from the resource is reserved for that ServiceClaim.
+	ServiceProvisioningClaimIDAnnotation = "primaza.io/service-provisioning-claim-id"
+
+	// ServiceClaimTemplateHashAnnotation is set on the ServiceClaims
+	// generated from a ServiceClaimTemplate, to the hash of the spec they
+	// have been generated with
+	ServiceClaimTemplateHashAnnotation = "primaza.io/service-claim-template-hash"
+
+	// ServiceClaimLeaseRenewedAtAnnotation renews the lease of a ServiceClaim
+	// with a TTL. Its value is the RFC3339 time the lease has been renewed at
+	ServiceClaimLeaseRenewedAtAnnotation = "primaza.io/lease-renewed-at"
+
+	// Workload Annotations
+	// SecretHashAnnotationPrefix, followed by the ServiceBinding's name, is
+	// the annotation set in the pod template of bound workloads when
+	// ServiceBinding's RolloutOnSecretChange is enabled. Its value is the hash
+	// of the binding's secret data, so that data changes trigger a rollout.
+	SecretHashAnnotationPrefix = "primaza.io/secret-hash-"
 )
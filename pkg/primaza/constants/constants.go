@@ -26,10 +26,37 @@ const (
 	ApplicationAgentKubeconfigSecretName = "primaza-app-kubeconfig" // #nosec G101
 	ServiceAgentKubeconfigSecretName     = "primaza-svc-kubeconfig" // #nosec G101
 	// Reasons for status condition
-	NoMatchingServiceFoundReason = "NoMatchingServiceFound"
-	ValidationErrorReason        = "ValidationError"
+	NoMatchingServiceFoundReason    = "NoMatchingServiceFound"
+	ValidationErrorReason           = "ValidationError"
+	CredentialsPendingReason        = "CredentialsPending"
+	CredentialsIssuingFailedReason  = "CredentialsIssuingFailed"
+	NameCollisionReason             = "NameCollision"
+	NoNameCollisionReason           = "NoNameCollision"
+	ServiceProvisioningReason       = "ServiceProvisioning"
+	ServiceProvisioningFailedReason = "ServiceProvisioningFailed"
+	ServiceProvisioningRetryReason  = "ServiceProvisioningRetry"
+	EnvironmentFoundReason          = "EnvironmentFound"
+	UnknownEnvironmentReason        = "UnknownEnvironment"
+	ServiceCatalogFoundReason       = "ServiceCatalogFound"
+	ServiceCatalogNotFoundReason    = "ServiceCatalogNotFound"
+	ServiceClaimsResolvedReason     = "ServiceClaimsResolved"
+	ServiceClaimsPendingReason      = "ServiceClaimsPending"
+	LeaseValidReason                = "LeaseValid"
+	LeaseExpiringReason             = "LeaseExpiring"
+	AwaitingApprovalReason          = "AwaitingApproval"
+	ClaimRejectedReason             = "ClaimRejected"
+	ClaimQuotaExceededReason        = "ClaimQuotaExceeded"
+	TenantProvisionedReason         = "TenantProvisioned"
+	TenantProvisioningFailedReason  = "TenantProvisioningFailed"
+	TenantNamespaceConflictReason   = "TenantNamespaceConflict"
 
 	// ServiceBinding Annotations
 	BoundRegisteredServiceNameAnnotation = "primaza.io/registered-service-name"
 	BoundRegisteredServiceUIDAnnotation  = "primaza.io/registered-service-uid"
+
+	// Entries required by the Service Binding specification in a binding.
+	// They are derived from the ServiceClassIdentity items with the same name
+	// Refer: https://github.com/servicebinding/spec#provisioned-service
+	ServiceBindingTypeKey     = "type"
+	ServiceBindingProviderKey = "provider"
 )
@@ -5,4 +5,10 @@ const (
 	PrimazaClusterEnvironmentLabel string = "primaza.io/cluster-environment"
 	PrimazaNamespaceTypeLabel      string = "primaza.io/namespace-type"
 	PrimazaNamespaceLabel          string = "primaza.io/namespace"
+	// PrimazaServiceClaimTemplateLabel is set on the ServiceClaims generated
+	// from a ServiceClaimTemplate, to the ServiceClaimTemplate's name
+	PrimazaServiceClaimTemplateLabel string = "primaza.io/service-claim-template"
+	// PrimazaEnvironmentLabel is set on the ServiceClaims generated from a
+	// ServiceClaimTemplate, to the environment they target
+	PrimazaEnvironmentLabel string = "primaza.io/environment"
 )
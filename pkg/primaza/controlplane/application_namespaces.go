@@ -33,6 +33,15 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
+// ServiceBindingPushResult is the outcome of pushing a ServiceBinding into an
+// application namespace
+type ServiceBindingPushResult struct {
+	Namespace string
+	// Status is the status of the pushed ServiceBinding, nil if the push failed
+	Status *primazaiov1alpha1.ServiceBindingStatus
+	Err    error
+}
+
 func PushServiceBinding(
 	ctx context.Context,
 	sc *primazaiov1alpha1.ServiceClaim,
@@ -42,7 +51,7 @@ func PushServiceBinding(
 	nspace *string,
 	applicationNamespaces []string,
 	cfg *rest.Config,
-) error {
+) ([]ServiceBindingPushResult, error) {
 	l := log.FromContext(ctx)
 	oc := client.Options{
 		Scheme: scheme,
@@ -50,23 +59,66 @@ func PushServiceBinding(
 	}
 	cecli, err := client.New(cfg, oc)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	projectServiceBindingSpecEntries(sc, secret)
+
+	rr := []ServiceBindingPushResult{}
 	errs := []error{}
 	for _, ns := range applicationNamespaces {
 		if nspace == nil || *nspace == ns {
 			l.Info("pushing to application namespace", "application namespace", ns)
-			if err := pushServiceBindingToNamespace(ctx, cecli, ns, sc, secret); err != nil {
+			st, err := pushServiceBindingToNamespace(ctx, cecli, ns, sc, secret)
+			if err != nil {
 				errs = append(errs, err)
 				l.Error(err, "error pushing to application namespaces", "application namespace", ns)
 			}
+			rr = append(rr, ServiceBindingPushResult{Namespace: ns, Status: st, Err: err})
 		}
 	}
 	if len(errs) > 0 {
-		return errors.Join(errs...)
+		return rr, errors.Join(errs...)
+	}
+	return rr, nil
+}
+
+// serviceBindingSpecForServiceClaim builds the spec of the ServiceBinding
+// fulfilling the given ServiceClaim. The binding's type and provider are
+// derived from the claim's ServiceClassIdentity
+func serviceBindingSpecForServiceClaim(sc *primazaiov1alpha1.ServiceClaim) primazaiov1alpha1.ServiceBindingSpec {
+	sbs := primazaiov1alpha1.ServiceBindingSpec{
+		ServiceEndpointDefinitionSecret: sc.Name,
+		Application:                     sc.Spec.Application,
+		Envs:                            sc.Spec.Envs,
+		RolloutOnSecretChange:           sc.Spec.RolloutOnSecretChange,
+	}
+
+	for _, sci := range sc.Spec.ServiceClassIdentity {
+		switch sci.Name {
+		case constants.ServiceBindingTypeKey:
+			sbs.Type = sci.Value
+		case constants.ServiceBindingProviderKey:
+			sbs.Provider = sci.Value
+		}
+	}
+	return sbs
+}
+
+// projectServiceBindingSpecEntries ensures the secret contains the `type` and
+// `provider` entries required by the Service Binding specification, derived
+// from the ServiceClaim's ServiceClassIdentity
+func projectServiceBindingSpecEntries(sc *primazaiov1alpha1.ServiceClaim, secret *corev1.Secret) {
+	sbs := serviceBindingSpecForServiceClaim(sc)
+	if secret.StringData == nil {
+		secret.StringData = map[string]string{}
+	}
+	if sbs.Type != "" {
+		secret.StringData[constants.ServiceBindingTypeKey] = sbs.Type
+	}
+	if sbs.Provider != "" {
+		secret.StringData[constants.ServiceBindingProviderKey] = sbs.Provider
 	}
-	return nil
 }
 
 func pushServiceBindingToNamespace(
@@ -74,19 +126,16 @@ func pushServiceBindingToNamespace(
 	cli client.Client,
 	namespace string,
 	sc *primazaiov1alpha1.ServiceClaim,
-	secret *corev1.Secret) error {
+	secret *corev1.Secret) (*primazaiov1alpha1.ServiceBindingStatus, error) {
 	l := log.FromContext(ctx)
 
+	sbs := serviceBindingSpecForServiceClaim(sc)
 	sb := primazaiov1alpha1.ServiceBinding{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      sc.Name,
 			Namespace: namespace,
 		},
-		Spec: primazaiov1alpha1.ServiceBindingSpec{
-			ServiceEndpointDefinitionSecret: sc.Name,
-			Application:                     sc.Spec.Application,
-			Envs:                            sc.Spec.Envs,
-		},
+		Spec: sbs,
 	}
 
 	l.Info("pushing service binding", "service-binding", sb.Name, "service-claim-status", sc.Status)
@@ -97,23 +146,19 @@ func pushServiceBindingToNamespace(
 		sb.ObjectMeta.Annotations[constants.BoundRegisteredServiceNameAnnotation] = sc.Status.RegisteredService.Name
 		sb.ObjectMeta.Annotations[constants.BoundRegisteredServiceUIDAnnotation] = string(sc.Status.RegisteredService.UID)
 
-		sb.Spec = primazaiov1alpha1.ServiceBindingSpec{
-			ServiceEndpointDefinitionSecret: sc.Name,
-			Application:                     sc.Spec.Application,
-			Envs:                            sc.Spec.Envs,
-		}
+		sb.Spec = sbs
 		return nil
 	})
 
 	if err != nil {
 		l.Error(err, "Failed to create or update service binding")
-		return err
+		return nil, err
 	} else {
 		l.Info("Wrote service binding", "binding", sb.Name, "namespace", sb.Namespace, "operation", op)
 	}
 
 	if err := cli.Get(ctx, types.NamespacedName{Namespace: namespace, Name: sc.Name}, &sb); err != nil {
-		return err
+		return nil, err
 	}
 
 	secret.OwnerReferences = []metav1.OwnerReference{
@@ -134,12 +179,12 @@ func pushServiceBindingToNamespace(
 
 	if err != nil {
 		l.Error(err, "error creating or updating secret for service claim", "secret", secret, "service claim", sc)
-		return err
+		return nil, err
 	} else {
 		l.Info("Wrote secret", "secret", secret.Name, "namespace", secret.Namespace, "operation", op)
 	}
 
-	return nil
+	return &sb.Status, nil
 }
 
 func PushServiceCatalogToApplicationNamespaces(ctx context.Context, sc primazaiov1alpha1.ServiceCatalog, scheme *runtime.Scheme, controllerruntimeClient client.Client, applicationNamespaces []string, cfg *rest.Config) error {
@@ -0,0 +1,58 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controlplane
+
+import (
+	"reflect"
+	"testing"
+
+	primazaiov1alpha1 "github.com/primaza/primaza/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestProjectServiceBindingSpecEntries(t *testing.T) {
+	tests := []struct {
+		name     string
+		sci      []primazaiov1alpha1.ServiceClassIdentityItem
+		data     map[string]string
+		expected map[string]string
+	}{
+		{
+			name:     "type and provider",
+			sci:      []primazaiov1alpha1.ServiceClassIdentityItem{{Name: "type", Value: "postgres"}, {Name: "provider", Value: "aws"}},
+			data:     map[string]string{"type": "db", "host": "localhost"},
+			expected: map[string]string{"type": "postgres", "provider": "aws", "host": "localhost"},
+		},
+		{
+			name:     "no type in service class identity",
+			sci:      []primazaiov1alpha1.ServiceClassIdentityItem{{Name: "engine", Value: "postgres"}},
+			data:     map[string]string{"host": "localhost"},
+			expected: map[string]string{"host": "localhost"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc := primazaiov1alpha1.ServiceClaim{Spec: primazaiov1alpha1.ServiceClaimSpec{ServiceClassIdentity: tt.sci}}
+			secret := corev1.Secret{StringData: tt.data}
+			projectServiceBindingSpecEntries(&sc, &secret)
+			if !reflect.DeepEqual(secret.StringData, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, secret.StringData)
+			}
+		})
+	}
+}
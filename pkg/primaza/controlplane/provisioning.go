@@ -0,0 +1,161 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controlplane
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	primazaiov1alpha1 "github.com/primaza/primaza/api/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/yaml"
+)
+
+// ProvisioningTemplateData is the data available to ServiceClasses'
+// provisioning templates
+type ProvisioningTemplateData struct {
+	Claim      ProvisioningTemplateClaim
+	Parameters map[string]string
+}
+
+// ProvisioningTemplateClaim describes the ServiceClaim a service is
+// provisioned for
+type ProvisioningTemplateClaim struct {
+	Name      string
+	Namespace string
+	ID        string
+}
+
+// RenderProvisioningTemplate renders the ServiceClass' provisioning template
+// for the given ServiceClaim
+func RenderProvisioningTemplate(
+	provisioning primazaiov1alpha1.ServiceClassProvisioning,
+	sclaim primazaiov1alpha1.ServiceClaim,
+) (*unstructured.Unstructured, error) {
+	t, err := provisioning.ParseTemplate()
+	if err != nil {
+		return nil, err
+	}
+
+	data := ProvisioningTemplateData{
+		Claim: ProvisioningTemplateClaim{
+			Name:      sclaim.Name,
+			Namespace: sclaim.Namespace,
+			ID:        sclaim.Status.ClaimID,
+		},
+		Parameters: sclaim.Spec.Parameters,
+	}
+	buf := bytes.Buffer{}
+	if err := t.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+
+	j, err := yaml.YAMLToJSON(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("invalid provisioning manifest: %w", err)
+	}
+	u := &unstructured.Unstructured{}
+	if err := json.Unmarshal(j, &u.Object); err != nil {
+		return nil, fmt.Errorf("invalid provisioning manifest: %w", err)
+	}
+	if u.GetAPIVersion() == "" || u.GetKind() == "" {
+		return nil, fmt.Errorf("invalid provisioning manifest: apiVersion and kind are required")
+	}
+	return u, nil
+}
+
+// ServiceProvisioningName returns the name of the ServiceProvisioning
+// created for the given ServiceClaim
+func ServiceProvisioningName(sclaim primazaiov1alpha1.ServiceClaim) string {
+	id := sclaim.Status.ClaimID
+	if len(id) > 8 {
+		id = id[:8]
+	}
+
+	name := sclaim.Name
+	if l := validation.DNS1123SubdomainMaxLength - len(id) - 1; len(name) > l {
+		name = name[:l]
+	}
+	return fmt.Sprintf("%s-%s", name, id)
+}
+
+// PushServiceProvisioning creates or updates the ServiceProvisioning asking
+// the Service Agent in the namespace to provision the service
+func PushServiceProvisioning(
+	ctx context.Context,
+	cli client.Client,
+	namespace string,
+	name string,
+	sc primazaiov1alpha1.ServiceClass,
+	sclaim primazaiov1alpha1.ServiceClaim,
+	manifest *unstructured.Unstructured,
+) error {
+	raw, err := manifest.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	sp := &primazaiov1alpha1.ServiceProvisioning{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+	_, err = controllerutil.CreateOrUpdate(ctx, cli, sp, func() error {
+		sp.Spec = primazaiov1alpha1.ServiceProvisioningSpec{
+			ServiceClassName: sc.Name,
+			ClaimID:          sclaim.Status.ClaimID,
+			Manifest:         runtime.RawExtension{Raw: raw},
+			ReclaimPolicy:    sc.Spec.Provisioning.GetReclaimPolicy(),
+		}
+		return nil
+	})
+	return err
+}
+
+// GetServiceProvisioning retrieves the ServiceProvisioning from the namespace
+func GetServiceProvisioning(ctx context.Context, cli client.Client, namespace, name string) (*primazaiov1alpha1.ServiceProvisioning, error) {
+	sp := &primazaiov1alpha1.ServiceProvisioning{}
+	if err := cli.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, sp); err != nil {
+		return nil, err
+	}
+	return sp, nil
+}
+
+// DeleteServiceProvisioning deletes the ServiceProvisioning from the
+// namespace. The Service Agent deletes the provisioned resource according
+// to the ServiceProvisioning's reclaim policy.
+func DeleteServiceProvisioning(ctx context.Context, cli client.Client, namespace, name string) error {
+	sp := &primazaiov1alpha1.ServiceProvisioning{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+	if err := cli.Delete(ctx, sp); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controlplane_test
+
+import (
+	"strings"
+	"testing"
+
+	primazaiov1alpha1 "github.com/primaza/primaza/api/v1alpha1"
+	"github.com/primaza/primaza/pkg/primaza/controlplane"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestRenderProvisioningTemplate(t *testing.T) {
+	sclaim := primazaiov1alpha1.ServiceClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-claim", Namespace: "primaza-system"},
+		Spec: primazaiov1alpha1.ServiceClaimSpec{
+			Parameters: map[string]string{"size": "5Gi"},
+		},
+		Status: primazaiov1alpha1.ServiceClaimStatus{ClaimID: "1234"},
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		err      string
+		expected map[string]string
+	}{
+		{
+			name: "claim data and parameters",
+			template: `apiVersion: example.com/v1
+kind: Database
+metadata:
+  name: {{ .Claim.Name }}-db
+  labels:
+    claim-id: "{{ .Claim.ID }}"
+spec:
+  size: {{ .Parameters.size }}
+  version: "{{ or .Parameters.version "15" }}"
+`,
+			expected: map[string]string{
+				"metadata.name":            "my-claim-db",
+				"metadata.labels.claim-id": "1234",
+				"spec.size":                "5Gi",
+				"spec.version":             "15",
+			},
+		},
+		{
+			name:     "missing kind",
+			template: "apiVersion: v1\n",
+			err:      "apiVersion and kind are required",
+		},
+		{
+			name:     "invalid manifest",
+			template: "apiVersion: [v1\n",
+			err:      "invalid provisioning manifest",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := controlplane.RenderProvisioningTemplate(
+				primazaiov1alpha1.ServiceClassProvisioning{Template: tt.template}, sclaim)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("expected error containing %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for path, expected := range tt.expected {
+				v, _, _ := unstructured.NestedString(u.Object, strings.Split(path, ".")...)
+				if v != expected {
+					t.Errorf("expected %s to be %q, got %q", path, expected, v)
+				}
+			}
+		})
+	}
+}
+
+func TestServiceProvisioningName(t *testing.T) {
+	sclaim := primazaiov1alpha1.ServiceClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: strings.Repeat("a", 300)},
+		Status:     primazaiov1alpha1.ServiceClaimStatus{ClaimID: "4a3b4c6e-8a1f-4bd2-9b5c-6c2b2f7e1d90"},
+	}
+
+	name := controlplane.ServiceProvisioningName(sclaim)
+	if len(name) > 253 {
+		t.Errorf("expected name to be at most 253 characters, got %d", len(name))
+	}
+	if !strings.HasSuffix(name, "-4a3b4c6e") {
+		t.Errorf("expected name to end with the claim ID prefix, got %q", name)
+	}
+}
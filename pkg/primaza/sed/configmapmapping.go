@@ -0,0 +1,95 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sed contains logic for ServiceEndpointDefinition
+package sed
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/primaza/primaza/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type SEDConfigMapRefMapping struct {
+	namespace string
+	resource  unstructured.Unstructured
+	cli       client.Client
+
+	key           string
+	configMapName v1alpha1.FieldMapping
+	configMapKey  v1alpha1.FieldMapping
+	secret        bool
+}
+
+func NewSEDConfigMapRefMapping(
+	namespace string,
+	resource unstructured.Unstructured,
+	cli client.Client,
+	mapping v1alpha1.ServiceClassConfigMapRefFieldMapping,
+) (*SEDConfigMapRefMapping, error) {
+	return &SEDConfigMapRefMapping{
+		namespace:     namespace,
+		resource:      resource,
+		cli:           cli,
+		key:           mapping.Name,
+		configMapKey:  mapping.ConfigMapKey,
+		configMapName: mapping.ConfigMapName,
+		secret:        mapping.Secret,
+	}, nil
+}
+
+func (s *SEDConfigMapRefMapping) Key() string {
+	return s.key
+}
+
+func (mapping *SEDConfigMapRefMapping) ReadKey(ctx context.Context) (*string, error) {
+	cmKey, err := readValue(mapping.configMapKey, mapping.resource)
+	if err != nil {
+		return nil, err
+	}
+	cmName, err := readValue(mapping.configMapName, mapping.resource)
+	if err != nil {
+		return nil, err
+	}
+
+	cm := &corev1.ConfigMap{}
+	ok := types.NamespacedName{
+		Namespace: mapping.namespace,
+		Name:      *cmName,
+	}
+	if err := mapping.cli.Get(ctx, ok, cm, &client.GetOptions{}); err != nil {
+		return nil, err
+	}
+
+	if v, ok := cm.Data[*cmKey]; ok {
+		return &v, nil
+	}
+	if vb, ok := cm.BinaryData[*cmKey]; ok {
+		v := string(vb)
+		return &v, nil
+	}
+
+	return nil, fmt.Errorf("config map key '%s/%s:%s' not Found", mapping.namespace, *cmName, *cmKey)
+}
+
+func (s *SEDConfigMapRefMapping) InSecret() bool {
+	return s.secret
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sed contains logic for ServiceEndpointDefinition
+package sed
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/primaza/primaza/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/jsonpath"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SEDObjectRefMapping reads a value from an object linked to the service
+// resource, e.g. the Service exposing it
+type SEDObjectRefMapping struct {
+	namespace string
+	resource  unstructured.Unstructured
+	cli       client.Client
+
+	key        string
+	gvk        schema.GroupVersionKind
+	objectName v1alpha1.FieldMapping
+	path       *jsonpath.JSONPath
+	secret     bool
+}
+
+func NewSEDObjectRefMapping(
+	namespace string,
+	resource unstructured.Unstructured,
+	cli client.Client,
+	mapping v1alpha1.ServiceClassObjectRefFieldMapping,
+) (*SEDObjectRefMapping, error) {
+	gv, err := schema.ParseGroupVersion(mapping.APIVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	path := jsonpath.New("")
+	if err := path.Parse(fmt.Sprintf("{%s}", mapping.JsonPath)); err != nil {
+		return nil, err
+	}
+
+	return &SEDObjectRefMapping{
+		namespace:  namespace,
+		resource:   resource,
+		cli:        cli,
+		key:        mapping.Name,
+		gvk:        gv.WithKind(mapping.Kind),
+		objectName: mapping.ObjectName,
+		path:       path,
+		secret:     mapping.Secret,
+	}, nil
+}
+
+func (s *SEDObjectRefMapping) Key() string {
+	return s.key
+}
+
+func (mapping *SEDObjectRefMapping) ReadKey(ctx context.Context) (*string, error) {
+	objName, err := readValue(mapping.objectName, mapping.resource)
+	if err != nil {
+		return nil, err
+	}
+
+	obj := unstructured.Unstructured{}
+	obj.SetGroupVersionKind(mapping.gvk)
+	ok := types.NamespacedName{
+		Namespace: mapping.namespace,
+		Name:      *objName,
+	}
+	if err := mapping.cli.Get(ctx, ok, &obj, &client.GetOptions{}); err != nil {
+		return nil, err
+	}
+
+	return readSingleJsonPath(mapping.path, obj)
+}
+
+func (s *SEDObjectRefMapping) InSecret() bool {
+	return s.secret
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sed_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/primaza/primaza/api/v1alpha1"
+	"github.com/primaza/primaza/pkg/primaza/sed"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_SEDRefMappingsReadKey(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	cli := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "db-config", Namespace: "services"},
+				Data:       map[string]string{"database": "orders"},
+			},
+			&corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "services"},
+				Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Port: 5432}}},
+			},
+		).
+		Build()
+
+	resource := unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"configMap": "db-config"},
+	}}
+	str := func(s string) *string { return &s }
+
+	cm, err := sed.NewSEDConfigMapRefMapping("services", resource, cli, v1alpha1.ServiceClassConfigMapRefFieldMapping{
+		Name:          "database",
+		ConfigMapName: v1alpha1.FieldMapping{JsonPathExpr: str(".spec.configMap")},
+		ConfigMapKey:  v1alpha1.FieldMapping{Constant: str("database")},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v, err := cm.ReadKey(context.Background()); err != nil || *v != "orders" {
+		t.Errorf("expected 'orders', got %v, %v", v, err)
+	}
+
+	missing, _ := sed.NewSEDConfigMapRefMapping("services", resource, cli, v1alpha1.ServiceClassConfigMapRefFieldMapping{
+		Name:          "user",
+		ConfigMapName: v1alpha1.FieldMapping{Constant: str("db-config")},
+		ConfigMapKey:  v1alpha1.FieldMapping{Constant: str("user")},
+	})
+	if _, err := missing.ReadKey(context.Background()); err == nil {
+		t.Errorf("expected error reading missing config map key")
+	}
+
+	obj, err := sed.NewSEDObjectRefMapping("services", resource, cli, v1alpha1.ServiceClassObjectRefFieldMapping{
+		Name:       "port",
+		APIVersion: "v1",
+		Kind:       "Service",
+		ObjectName: v1alpha1.FieldMapping{Constant: str("db")},
+		JsonPath:   ".spec.ports[0].port",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v, err := obj.ReadKey(context.Background()); err != nil || *v != "5432" {
+		t.Errorf("expected '5432', got %v, %v", v, err)
+	}
+}
@@ -19,9 +19,13 @@ package sed
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
+	"strings"
+	"text/template"
 
 	"github.com/primaza/primaza/api/v1alpha1"
+	"github.com/primaza/primaza/pkg/sedtemplate"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/util/jsonpath"
 )
@@ -30,24 +34,41 @@ import (
 type SEDResourceMapping struct {
 	resource unstructured.Unstructured
 
-	key    string
-	path   *jsonpath.JSONPath
-	secret bool
+	key       string
+	path      *jsonpath.JSONPath
+	template  *template.Template
+	def       string
+	separator string
+	decode    v1alpha1.ServiceClassMappingDecoding
+	secret    bool
 }
 
 func NewSEDResourceMapping(resource unstructured.Unstructured, mapping v1alpha1.ServiceClassResourceFieldMapping) (*SEDResourceMapping, error) {
-	path := jsonpath.New("")
+	m := &SEDResourceMapping{
+		resource:  resource,
+		key:       mapping.Name,
+		def:       mapping.Default,
+		separator: mapping.Separator,
+		decode:    mapping.Decode,
+		secret:    mapping.Secret,
+	}
+
+	if mapping.Template != "" {
+		t, err := sedtemplate.Parse(mapping.Template)
+		if err != nil {
+			return nil, err
+		}
+		m.template = t
+		return m, nil
+	}
+
+	path := jsonpath.New("").AllowMissingKeys(true)
 	err := path.Parse(fmt.Sprintf("{%s}", mapping.JsonPath))
 	if err != nil {
 		return nil, err
 	}
-
-	return &SEDResourceMapping{
-		resource: resource,
-		key:      mapping.Name,
-		path:     path,
-		secret:   mapping.Secret,
-	}, nil
+	m.path = path
+	return m, nil
 }
 
 func (s *SEDResourceMapping) Key() string {
@@ -55,19 +76,57 @@ func (s *SEDResourceMapping) Key() string {
 }
 
 func (mapping *SEDResourceMapping) ReadKey(ctx context.Context) (*string, error) {
-	results, err := mapping.path.FindResults(mapping.resource.Object)
+	value, err := mapping.readValue()
 	if err != nil {
 		return nil, err
 	}
 
-	if len(results) != 1 || len(results[0]) != 1 {
-		return nil, fmt.Errorf("jsonPath lookup into resource returned multiple results: %v", results)
+	if mapping.decode == v1alpha1.ServiceClassMappingDecodingBase64 {
+		b, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding value of key %s: %w", mapping.key, err)
+		}
+		value = string(b)
 	}
-
-	value := fmt.Sprintf("%v", results[0][0])
 	return &value, nil
 }
 
+func (mapping *SEDResourceMapping) readValue() (string, error) {
+	if mapping.template != nil {
+		value, err := sedtemplate.Execute(mapping.template, mapping.resource.Object)
+		if err != nil {
+			return "", err
+		}
+		if value == "" {
+			value = mapping.def
+		}
+		return value, nil
+	}
+
+	results, err := mapping.path.FindResults(mapping.resource.Object)
+	if err != nil {
+		return "", err
+	}
+
+	vv := []string{}
+	for _, r := range results {
+		for _, v := range r {
+			vv = append(vv, fmt.Sprintf("%v", v))
+		}
+	}
+
+	switch {
+	case len(vv) == 0 && mapping.def != "":
+		return mapping.def, nil
+	case len(vv) == 0:
+		return "", fmt.Errorf("jsonPath lookup into resource returned no results")
+	case len(vv) > 1 && mapping.separator == "":
+		return "", fmt.Errorf("jsonPath lookup into resource returned multiple results: %v", results)
+	default:
+		return strings.Join(vv, mapping.separator), nil
+	}
+}
+
 func (s *SEDResourceMapping) InSecret() bool {
 	return s.secret
 }
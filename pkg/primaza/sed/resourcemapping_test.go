@@ -0,0 +1,80 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sed_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/primaza/primaza/api/v1alpha1"
+	"github.com/primaza/primaza/pkg/primaza/sed"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func Test_SEDResourceMappingReadKey(t *testing.T) {
+	resource := unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"port":     int64(5432),
+			"hosts":    []interface{}{"a", "b"},
+			"password": "c2VjcmV0",
+		},
+		"status": map[string]interface{}{
+			"host": "db.example.com",
+		},
+	}}
+
+	type test struct {
+		name    string
+		mapping v1alpha1.ServiceClassResourceFieldMapping
+		want    string
+		wantErr bool
+	}
+
+	tt := []test{
+		{name: "single value", mapping: v1alpha1.ServiceClassResourceFieldMapping{JsonPath: ".spec.port"}, want: "5432"},
+		{name: "missing value", mapping: v1alpha1.ServiceClassResourceFieldMapping{JsonPath: ".spec.user"}, wantErr: true},
+		{name: "default", mapping: v1alpha1.ServiceClassResourceFieldMapping{JsonPath: ".spec.user", Default: "admin"}, want: "admin"},
+		{name: "multiple values", mapping: v1alpha1.ServiceClassResourceFieldMapping{JsonPath: ".spec.hosts[*]"}, wantErr: true},
+		{name: "join", mapping: v1alpha1.ServiceClassResourceFieldMapping{JsonPath: ".spec.hosts[*]", Separator: ","}, want: "a,b"},
+		{
+			name:    "decode",
+			mapping: v1alpha1.ServiceClassResourceFieldMapping{JsonPath: ".spec.password", Decode: v1alpha1.ServiceClassMappingDecodingBase64},
+			want:    "secret",
+		},
+		{
+			name:    "template",
+			mapping: v1alpha1.ServiceClassResourceFieldMapping{Template: "{{.status.host}}:{{.spec.port}}"},
+			want:    "db.example.com:5432",
+		},
+	}
+
+	for _, te := range tt {
+		t.Run(te.name, func(t *testing.T) {
+			m, err := sed.NewSEDResourceMapping(resource, te.mapping)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got, err := m.ReadKey(context.Background())
+			if (err != nil) != te.wantErr {
+				t.Fatalf("expected error %v, got %v", te.wantErr, err)
+			}
+			if !te.wantErr && *got != te.want {
+				t.Errorf("expected %q, got %q", te.want, *got)
+			}
+		})
+	}
+}
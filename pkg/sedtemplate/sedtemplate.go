@@ -0,0 +1,111 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sedtemplate
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// Parse parses a Service Endpoint Definition template.
+// Besides Go templates' builtins, the following functions are available:
+//   - jsonPath: looks up a JSONPath in the resource, returning nil if missing
+//   - default: returns the given default if the value is nil or empty
+//   - b64enc and b64dec: base64-encode and base64-decode a string
+//   - join: joins the elements of a list with a separator
+func Parse(text string) (*template.Template, error) {
+	return template.New("sed").Funcs(funcs(nil)).Parse(text)
+}
+
+// Execute evaluates the template over the resource
+func Execute(t *template.Template, resource map[string]interface{}) (string, error) {
+	t, err := t.Clone()
+	if err != nil {
+		return "", err
+	}
+
+	b := strings.Builder{}
+	if err := t.Funcs(funcs(resource)).Execute(&b, resource); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+func funcs(resource map[string]interface{}) template.FuncMap {
+	return template.FuncMap{
+		"jsonPath": func(expr string) (interface{}, error) {
+			return lookup(expr, resource)
+		},
+		"default": func(def string, v interface{}) interface{} {
+			if v == nil || v == "" {
+				return def
+			}
+			return v
+		},
+		"b64enc": func(s string) string {
+			return base64.StdEncoding.EncodeToString([]byte(s))
+		},
+		"b64dec": func(s string) (string, error) {
+			b, err := base64.StdEncoding.DecodeString(s)
+			return string(b), err
+		},
+		"join": func(sep string, v interface{}) (string, error) {
+			l, ok := v.([]interface{})
+			if !ok {
+				return "", fmt.Errorf("join: expected a list, got %T", v)
+			}
+			ss := make([]string, 0, len(l))
+			for _, e := range l {
+				ss = append(ss, fmt.Sprintf("%v", e))
+			}
+			return strings.Join(ss, sep), nil
+		},
+	}
+}
+
+// lookup returns the values matching the JSONPath expression in the resource:
+// nil if no value matches, the value if exactly one matches, and the list of
+// values otherwise
+func lookup(expr string, resource map[string]interface{}) (interface{}, error) {
+	j := jsonpath.New("").AllowMissingKeys(true)
+	if err := j.Parse(fmt.Sprintf("{%s}", expr)); err != nil {
+		return nil, err
+	}
+	rr, err := j.FindResults(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	vv := []interface{}{}
+	for _, r := range rr {
+		for _, v := range r {
+			vv = append(vv, v.Interface())
+		}
+	}
+	switch len(vv) {
+	case 0:
+		return nil, nil
+	case 1:
+		return vv[0], nil
+	default:
+		return vv, nil
+	}
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2023 The Primaza Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sedtemplate_test
+
+import (
+	"testing"
+
+	"github.com/primaza/primaza/pkg/sedtemplate"
+)
+
+func Test_Execute(t *testing.T) {
+	resource := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"port":     int64(5432),
+			"db":       "orders",
+			"password": "c2VjcmV0",
+			"hosts":    []interface{}{"a", "b"},
+		},
+		"status": map[string]interface{}{
+			"host": "db.example.com",
+		},
+	}
+
+	type test struct {
+		name     string
+		template string
+		want     string
+		wantErr  bool
+	}
+
+	tt := []test{
+		{
+			name:     "compose",
+			template: "jdbc:postgresql://{{.status.host}}:{{.spec.port}}/{{.spec.db}}",
+			want:     "jdbc:postgresql://db.example.com:5432/orders",
+		},
+		{name: "default on missing path", template: `{{ jsonPath ".spec.user" | default "admin" }}`, want: "admin"},
+		{name: "default on existing path", template: `{{ jsonPath ".spec.db" | default "admin" }}`, want: "orders"},
+		{name: "base64 decode", template: `{{ b64dec .spec.password }}`, want: "secret"},
+		{name: "base64 encode", template: `{{ b64enc .spec.db }}`, want: "b3JkZXJz"},
+		{name: "join", template: `{{ join "," .spec.hosts }}`, want: "a,b"},
+		{name: "join jsonPath results", template: `{{ jsonPath ".spec.hosts[*]" | join ";" }}`, want: "a;b"},
+		{name: "join non list", template: `{{ join "," .spec.db }}`, wantErr: true},
+		{name: "invalid base64", template: `{{ b64dec .spec.db }}`, wantErr: true},
+	}
+
+	for _, te := range tt {
+		t.Run(te.name, func(t *testing.T) {
+			tpl, err := sedtemplate.Parse(te.template)
+			if err != nil {
+				t.Fatalf("unexpected parse error: %v", err)
+			}
+			got, err := sedtemplate.Execute(tpl, resource)
+			if (err != nil) != te.wantErr {
+				t.Fatalf("expected error %v, got %v", te.wantErr, err)
+			}
+			if !te.wantErr && got != te.want {
+				t.Errorf("expected %q, got %q", te.want, got)
+			}
+		})
+	}
+}
+
+func Test_ParseInvalid(t *testing.T) {
+	for _, s := range []string{"{{ .spec.db ", "{{ unknown .spec.db }}"} {
+		if _, err := sedtemplate.Parse(s); err == nil {
+			t.Errorf("expected error parsing %q", s)
+		}
+	}
+}